@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"example.com/stocker-back/internal/backtest"
+	"example.com/stocker-back/internal/journal"
+	"github.com/spf13/cobra"
+)
+
+// registerCommands adds subcommands to the pocketbase CLI, run with the app bootstrapped.
+func (app *Application) registerCommands() {
+	app.pb.RootCmd.AddCommand(app.backtestCommand())
+	app.pb.RootCmd.AddCommand(app.sweepCommand())
+	app.pb.RootCmd.AddCommand(app.journalImportCommand())
+}
+
+// backtestCommand runs a backtest from a JSON config file, or stdin if "-", and prints
+// the result as JSON; --start, --end and --tickers override the config. With --report
+// it writes the performance report to an HTML or Markdown file instead, against the
+// index of --benchmark if given.
+func (app *Application) backtestCommand() *cobra.Command {
+	var start, end, reportPath, benchmark string
+	var tickers []string
+
+	command := &cobra.Command{
+		Use:   "backtest <config.json>",
+		Short: "Backtest a screen rule over stored daily history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := readBacktestConfig(args[0])
+			if err != nil {
+				return err
+			}
+			if start != "" {
+				config.Start = start
+			}
+			if end != "" {
+				config.End = end
+			}
+			if len(tickers) > 0 {
+				config.Tickers = tickers
+			}
+
+			if reportPath != "" {
+				result, report, err := app.query.ReportBacktest(config, benchmark)
+				if err != nil {
+					return err
+				}
+				if err := writeReport(reportPath, result, report); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "report written to %s\n", reportPath)
+				return nil
+			}
+
+			result, err := app.query.RunBacktest(config)
+			if err != nil {
+				return err
+			}
+
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		},
+	}
+	command.Flags().StringVar(&start, "start", "", "first date entries are taken on, e.g. 2023-01-01")
+	command.Flags().StringVar(&end, "end", "", "last date replayed, e.g. 2023-12-31")
+	command.Flags().StringSliceVar(&tickers, "tickers", nil, "tickers to replay, all if empty")
+	command.Flags().StringVar(&reportPath, "report", "", "write the report to this .html or .md file")
+	command.Flags().StringVar(&benchmark, "benchmark", "", "index ticker in daily to compare with, e.g. 1.000300")
+
+	return command
+}
+
+// sweepCommand runs a parameter sweep from a JSON spec file, or stdin if "-", and
+// prints its ranked parameter sets as JSON. A sweep of the same name already saved is
+// resumed, skipping the runs it finished, unless its spec differs.
+func (app *Application) sweepCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sweep <spec.json>",
+		Short: "Sweep backtest parameters, with walk-forward if set, over stored daily history",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+			var sweep backtest.Sweep
+			if err := json.Unmarshal(b, &sweep); err != nil {
+				return fmt.Errorf("%w: %s", backtest.ErrInvalidConfig, err.Error())
+			}
+
+			if saved, err := app.query.GetSweep(sweep.Name); err != nil {
+				if err := app.command.StartSweep(sweep); err != nil {
+					return err
+				}
+			} else if !saved.SameSpec(sweep) {
+				return fmt.Errorf("%w: sweep %s exists with another spec", backtest.ErrInvalidConfig, sweep.Name)
+			}
+			if err := app.command.RunSweep(sweep.Name); err != nil {
+				return err
+			}
+
+			result, err := app.query.GetSweepResult(sweep.Name)
+			if err != nil {
+				return err
+			}
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(result)
+		},
+	}
+}
+
+// journalImportCommand imports trades of a broker CSV export, or stdin if "-", into the
+// trade journal, in the columns of a JSON format file if --format is given.
+func (app *Application) journalImportCommand() *cobra.Command {
+	var formatPath string
+
+	command := &cobra.Command{
+		Use:   "journal-import <export.csv>",
+		Short: "Import trades of a broker CSV export into the trade journal",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := journal.DefaultCSVFormat()
+			if formatPath != "" {
+				b, err := os.ReadFile(formatPath)
+				if err != nil {
+					return err
+				}
+				if err := json.Unmarshal(b, &format); err != nil {
+					return fmt.Errorf("%w: %s", journal.ErrInvalidCSV, err.Error())
+				}
+			}
+			b, err := readInput(args[0])
+			if err != nil {
+				return err
+			}
+
+			result, err := app.command.ImportTrades(bytes.NewReader(b), format)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "imported: %d duplicates: %d skipped: %d\n", result.Imported, result.Duplicates, result.Skipped)
+			return nil
+		},
+	}
+	command.Flags().StringVar(&formatPath, "format", "", "JSON file of the export columns over the default format")
+
+	return command
+}
+
+// readInput reads the file at path, or stdin if "-".
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func readBacktestConfig(path string) (backtest.Config, error) {
+	b, err := readInput(path)
+	if err != nil {
+		return backtest.Config{}, err
+	}
+
+	var config backtest.Config
+	if err := json.Unmarshal(b, &config); err != nil {
+		return backtest.Config{}, fmt.Errorf("%w: %s", backtest.ErrInvalidConfig, err.Error())
+	}
+
+	return config, nil
+}
@@ -1,14 +1,52 @@
 package main
 
+import (
+	"example.com/stocker-back/internal/screener"
+)
+
 func (app *Application) cronDailyDataUpdate() {
 	if err := app.command.UpdateDailyData(); err != nil {
 		app.pb.Logger().Error("cronDailyDataUpdate", "error", err.Error())
+		return
+	}
+
+	// Paper orders fill at the daily data just stored.
+	if err := app.command.FillOrders(); err != nil {
+		app.pb.Logger().Error("cronDailyDataUpdate", "error", err.Error())
 	}
 }
 
 func (app *Application) cronDailyScreening() {
 	if err := app.command.UpdateDailyScreen(); err != nil {
 		app.pb.Logger().Error("cronDailyScreening", "error", err.Error())
+		return
+	}
+
+	// Notify changes of the default screen and saved screens run along with it.
+	names := []string{""}
+	definitions, err := app.query.GetScreenDefinitions()
+	if err != nil {
+		app.pb.Logger().Error("cronDailyScreening", "error", err.Error())
+	}
+	for _, definition := range definitions {
+		if definition.Schedule == "" {
+			names = append(names, definition.Name)
+		}
+	}
+	for _, name := range names {
+		if err := app.command.NotifyScreenDiff(name); err != nil {
+			app.pb.Logger().Error("cronDailyScreening", "error", err.Error(), "screen", name)
+		}
+	}
+
+	if err := app.command.PlaceSignalOrders(); err != nil {
+		app.pb.Logger().Error("cronDailyScreening", "error", err.Error())
+	}
+}
+
+func (app *Application) cronDailyScoring() {
+	if err := app.command.UpdateScores(); err != nil {
+		app.pb.Logger().Error("cronDailyScoring", "error", err.Error())
 	}
 }
 
@@ -17,3 +55,46 @@ func (app *Application) cronWeeklyStocksUpdate() {
 		app.pb.Logger().Error("cronWeeklyStocksUpdate", "error", err.Error())
 	}
 }
+
+func (app *Application) cronWeeklyRisk() {
+	if err := app.command.NotifyRisk(); err != nil {
+		app.pb.Logger().Error("cronWeeklyRisk", "error", err.Error())
+	}
+}
+
+// screenJobID is the cron job id of a saved screen.
+func screenJobID(name string) string {
+	return "screen:" + name
+}
+
+// scheduleScreen (re)registers the cron job of a saved screen with own schedule;
+// screens without schedule run with the daily screen.
+func (app *Application) scheduleScreen(definition screener.Definition) error {
+	if app.scheduler == nil {
+		return nil
+	}
+
+	app.scheduler.Remove(screenJobID(definition.Name))
+	if definition.Schedule == "" {
+		return nil
+	}
+
+	name := definition.Name
+	return app.scheduler.Add(screenJobID(name), definition.Schedule, func() {
+		if err := app.command.RunScreen(name); err != nil {
+			app.pb.Logger().Error("cronScreen", "error", err.Error(), "name", name)
+			return
+		}
+		if err := app.command.NotifyScreenDiff(name); err != nil {
+			app.pb.Logger().Error("cronScreen", "error", err.Error(), "name", name)
+		}
+	})
+}
+
+// unscheduleScreen removes the cron job of a saved screen.
+func (app *Application) unscheduleScreen(name string) {
+	if app.scheduler == nil {
+		return
+	}
+	app.scheduler.Remove(screenJobID(name))
+}
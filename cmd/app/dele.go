@@ -31,6 +31,39 @@ func (app *Application) updateDailyData(c echo.Context) error {
 	return c.JSON(http.StatusOK, ResponseOk())
 }
 
+func (app *Application) rebuildIndicators(c echo.Context) error {
+	go func() {
+		err := app.command.RebuildIndicators()
+		if err != nil {
+			app.pb.Logger().Error("rebuildIndicators", "error", err.Error())
+			app.notifier.Sendf("rebuildIndicators", fmt.Sprintf("error: %v", err.Error()))
+		}
+	}()
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+func (app *Application) updateScores(c echo.Context) error {
+	go func() {
+		err := app.command.UpdateScores()
+		if err != nil {
+			app.pb.Logger().Error("updateScores", "error", err.Error())
+			app.notifier.Sendf("updateScores", fmt.Sprintf("error: %v", err.Error()))
+		}
+	}()
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+func (app *Application) fillOrders(c echo.Context) error {
+	go func() {
+		err := app.command.FillOrders()
+		if err != nil {
+			app.pb.Logger().Error("fillOrders", "error", err.Error())
+			app.notifier.Sendf("fillOrders", fmt.Sprintf("error: %v", err.Error()))
+		}
+	}()
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
 func (app *Application) deleDevHandler(c echo.Context) error {
 	_, err := app.query.GetStocksBySector("dele")
 	if err != nil {
@@ -67,3 +100,14 @@ func (app *Application) deleteStocksHandler(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, ResponseOk())
 }
+
+func (app *Application) notifyRisk(c echo.Context) error {
+	go func() {
+		err := app.command.NotifyRisk()
+		if err != nil {
+			app.pb.Logger().Error("notifyRisk", "error", err.Error())
+			app.notifier.Sendf("notifyRisk", fmt.Sprintf("error: %v", err.Error()))
+		}
+	}()
+	return c.JSON(http.StatusOK, ResponseOk())
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 
 	"example.com/stocker-back/internal/infra"
+	"example.com/stocker-back/internal/scoring"
+	"example.com/stocker-back/internal/screener"
 	"example.com/stocker-back/internal/usecase"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/apis"
@@ -15,10 +18,11 @@ import (
 )
 
 type Application struct {
-	pb       *pocketbase.PocketBase
-	command  *usecase.Command
-	query    *usecase.Query
-	notifier infra.Notifier
+	pb        *pocketbase.PocketBase
+	command   *usecase.Command
+	query     *usecase.Query
+	notifier  infra.Notifier
+	scheduler *cron.Cron
 }
 
 func main() {
@@ -37,19 +41,44 @@ func main() {
 	repoStock := infra.NewStockRepositoryPB(pb)
 	repoScreen := infra.NewScreenRepositoryPB(pb)
 	repoTracking := infra.NewTrackingRepositoryPB(pb)
+	repoScore := infra.NewScoreRepositoryPB(pb)
+	repoSweep := infra.NewSweepRepositoryPB(pb)
+	repoPortfolio := infra.NewPortfolioRepositoryPB(pb)
+	repoJournal := infra.NewJournalRepositoryPB(pb)
 	loggerSlog := infra.NewLoggerSlog(pb.Logger())
-	usecaseCommand := usecase.NewCommand(repoStock, repoScreen, repoTracking, loggerSlog, notifierPushbullet)
-	usecaseQuery := usecase.NewQuery(repoStock, repoScreen, repoTracking, loggerSlog, notifierPushbullet)
+	usecaseCommand := usecase.NewCommand(repoStock, repoScreen, repoTracking, repoScore, repoSweep, repoPortfolio, repoJournal, loggerSlog, notifierPushbullet)
+	if ruleJSON := os.Getenv("SCREEN_RULE"); ruleJSON != "" {
+		rule, err := screener.ParseRule([]byte(ruleJSON))
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := usecaseCommand.SetScreenRule(rule); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if configJSON := os.Getenv("SCORE_CONFIG"); configJSON != "" {
+		var config scoring.Config
+		if err := json.Unmarshal([]byte(configJSON), &config); err != nil {
+			log.Fatal(err)
+		}
+		if err := usecaseCommand.SetScoreConfig(config); err != nil {
+			log.Fatal(err)
+		}
+	}
+	usecaseQuery := usecase.NewQuery(repoStock, repoScreen, repoTracking, repoScore, repoSweep, repoPortfolio, repoJournal, loggerSlog, notifierPushbullet)
 
 	app := Application{
-		pb:       pb,
-		command:  usecaseCommand,
-		query:    usecaseQuery,
-		notifier: notifierPushbullet,
+		pb:        pb,
+		command:   usecaseCommand,
+		query:     usecaseQuery,
+		notifier:  notifierPushbullet,
+		scheduler: nil,
 	}
 
 	app.pb.Logger().Info("starting app...")
 
+	app.registerCommands()
+
 	// // loosely check if it was executed using "go run".
 	// isGoRun := strings.HasPrefix(os.Args[0], os.TempDir())
 
@@ -70,11 +99,19 @@ func main() {
 		gDele.POST("/deletestocks", app.deleteStocksHandler)
 		gDele.GET("/updatestocks", app.deleUpdateStocksHandler)
 		gDele.GET("/updatedaily", app.updateDailyData)
+		gDele.GET("/rebuildindicators", app.rebuildIndicators)
 		gDele.GET("/updatescreen", app.screenUpdateHandler)
+		gDele.GET("/updatescores", app.updateScores)
+		gDele.GET("/fillorders", app.fillOrders)
+		gDele.GET("/notifyrisk", app.notifyRisk)
 
 		gStock := e.Router.Group("/stocks")
 		gStock.Use(apis.RequireRecordAuth("users"))
 		gStock.GET("/:ticker", app.stockSearchHandler)
+		gStock.GET("/:ticker/patterns", app.stockPatternsHandler)
+		gStock.GET("/:ticker/divergences", app.stockDivergencesHandler)
+		gStock.GET("/:ticker/levels", app.stockLevelsHandler)
+		gStock.GET("/:ticker/events", app.stockEventsHandler)
 		gStock.POST("/:ticker", app.stockCreateHandler)
 		gStock.DELETE("/:ticker", app.stockDeleteHandler)
 
@@ -85,8 +122,53 @@ func main() {
 		gTracking.DELETE("/:ticker", app.trackingDeleteHandler)
 
 		e.Router.GET("/screen", app.screenReadHandler, apis.RequireRecordAuth("users"))
+		e.Router.GET("/screen/:name", app.screenReadByNameHandler, apis.RequireRecordAuth("users"))
+		e.Router.GET("/screenhistory/:ticker", app.screenHistoryHandler, apis.RequireRecordAuth("users"))
+		e.Router.GET("/screendiff", app.screenDiffHandler, apis.RequireRecordAuth("users"))
+
+		gScreens := e.Router.Group("/screens")
+		gScreens.Use(apis.RequireRecordAuth("users"))
+		gScreens.GET("", app.screenDefinitionListHandler)
+		gScreens.POST("", app.screenDefinitionCreateHandler)
+		gScreens.PUT("/:name", app.screenDefinitionUpdateHandler)
+		gScreens.DELETE("/:name", app.screenDefinitionDeleteHandler)
+		gScreens.POST("/:name/run", app.screenRunHandler)
+
+		e.Router.GET("/ranking", app.rankingReadHandler, apis.RequireRecordAuth("users"))
+
+		e.Router.POST("/backtest", app.backtestHandler, apis.RequireRecordAuth("users"))
+		e.Router.POST("/backtest/report", app.backtestReportHandler, apis.RequireRecordAuth("users"))
+
+		gPortfolios := e.Router.Group("/portfolios")
+		gPortfolios.Use(apis.RequireRecordAuth("users"))
+		gPortfolios.GET("", app.portfolioListHandler)
+		gPortfolios.POST("", app.portfolioCreateHandler)
+		gPortfolios.GET("/:name", app.portfolioReadHandler)
+		gPortfolios.DELETE("/:name", app.portfolioDeleteHandler)
+		gPortfolios.GET("/:name/risk", app.portfolioRiskHandler)
+		gPortfolios.GET("/:name/orders", app.portfolioOrdersHandler)
+		gPortfolios.POST("/:name/orders", app.portfolioOrderCreateHandler)
+		gPortfolios.DELETE("/:name/orders/:id", app.portfolioOrderCancelHandler)
+
+		gJournal := e.Router.Group("/journal")
+		gJournal.Use(apis.RequireRecordAuth("users"))
+		gJournal.GET("", app.journalReadHandler)
+		gJournal.GET("/risk", app.journalRiskHandler)
+		gJournal.GET("/trades", app.journalTradesHandler)
+		gJournal.POST("/trades", app.journalTradeCreateHandler)
+		gJournal.DELETE("/trades/:id", app.journalTradeDeleteHandler)
+		gJournal.POST("/import", app.journalImportHandler)
+
+		gSweeps := e.Router.Group("/sweeps")
+		gSweeps.Use(apis.RequireRecordAuth("users"))
+		gSweeps.GET("", app.sweepListHandler)
+		gSweeps.POST("", app.sweepCreateHandler)
+		gSweeps.GET("/:name", app.sweepReadHandler)
+		gSweeps.POST("/:name/resume", app.sweepResumeHandler)
+		gSweeps.DELETE("/:name", app.sweepDeleteHandler)
 
 		e.Router.GET("/sector/:sector", app.sectorReadHandler, apis.RequireRecordAuth("users"))
+		e.Router.GET("/sectors", app.sectorStatsHandler, apis.RequireRecordAuth("users"))
 
 		e.Router.GET("/random/:num", app.randomStocksHandler, apis.RequireRecordAuth("users"))
 
@@ -95,6 +177,16 @@ func main() {
 		return nil
 	})
 
+	// ----------------- Sweep ----------------------
+	app.pb.OnBeforeServe().Add(func(_ *core.ServeEvent) error {
+		go func() {
+			if err := app.command.ResumeSweeps(); err != nil {
+				app.pb.Logger().Error("ResumeSweeps", "error", err.Error())
+			}
+		}()
+		return nil
+	})
+
 	// ----------------- Cron ----------------------
 	app.pb.OnBeforeServe().Add(func(_ *core.ServeEvent) error {
 		if isDevMode {
@@ -118,6 +210,13 @@ func main() {
 		}
 		app.pb.Logger().Info("cron", "messge", "cronDailyScreening registered")
 
+		// Every week Mon-Fri at 11:30 UTC (19:30 Beijing Time)
+		err = scheduler.Add("dailyscore", "30 11 * * 1-5", app.cronDailyScoring)
+		if err != nil {
+			return fmt.Errorf("error in adding cron job `cronDailyScoring`: %w", err)
+		}
+		app.pb.Logger().Info("cron", "messge", "cronDailyScoring registered")
+
 		// Every week Fri at 12:00 UTC (20:00 Beijing Time)
 		err = scheduler.Add("weeklystocks", "0 12 * * 5", app.cronWeeklyStocksUpdate)
 		if err != nil {
@@ -125,6 +224,27 @@ func main() {
 		}
 		app.pb.Logger().Info("cron", "messge", "cronWeeklyStocksUpdate registered")
 
+		// Every week Fri at 12:30 UTC (20:30 Beijing Time)
+		err = scheduler.Add("weeklyrisk", "30 12 * * 5", app.cronWeeklyRisk)
+		if err != nil {
+			return fmt.Errorf("error in adding cron job `cronWeeklyRisk`: %w", err)
+		}
+		app.pb.Logger().Info("cron", "messge", "cronWeeklyRisk registered")
+
+		app.scheduler = scheduler
+
+		// Saved screens with own schedule, left unscheduled rather than failing startup,
+		// e.g. before the collection is created.
+		definitions, err := app.query.GetScreenDefinitions()
+		if err != nil {
+			app.pb.Logger().Error("cron", "error", fmt.Sprintf("error in reading screen definitions: %s", err.Error()))
+		}
+		for _, definition := range definitions {
+			if err := app.scheduleScreen(definition); err != nil {
+				app.pb.Logger().Error("cron", "error", err.Error(), "screen", definition.Name)
+			}
+		}
+
 		scheduler.Start()
 
 		return nil
@@ -0,0 +1,59 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"example.com/stocker-back/internal/backtest"
+	"example.com/stocker-back/internal/common"
+)
+
+//go:embed templates/report.md.tmpl
+var reportMarkdown string
+
+//go:embed templates/report.html.tmpl
+var reportHTML string
+
+// reportFuncs format numbers of report templates.
+var reportFuncs = map[string]any{
+	"pct": func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+	"num": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"day": common.Day,
+	"sign": func(v float64) string {
+		if v < 0.0 {
+			return "neg"
+		}
+		return "pos"
+	},
+}
+
+// writeReport renders a backtest report to path, as HTML for .html/.htm and as Markdown otherwise.
+func writeReport(path string, result backtest.Result, report backtest.Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data := struct {
+		Result backtest.Result
+		Report backtest.Report
+	}{
+		Result: result,
+		Report: report,
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		tmpl := htmltemplate.Must(htmltemplate.New("report").Funcs(reportFuncs).Parse(reportHTML))
+		return tmpl.Execute(f, data)
+	default:
+		tmpl := texttemplate.Must(texttemplate.New("report").Funcs(reportFuncs).Parse(reportMarkdown))
+		return tmpl.Execute(f, data)
+	}
+}
@@ -1,13 +1,30 @@
 package main
 
 import (
-	apieastmoney "example.com/stocker-back/internal/infra/api_eastmoney"
-	"github.com/labstack/echo/v5"
-
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+
+	"example.com/stocker-back/internal/backtest"
+	apieastmoney "example.com/stocker-back/internal/infra/api_eastmoney"
+	"example.com/stocker-back/internal/journal"
+	"example.com/stocker-back/internal/portfolio"
+	"example.com/stocker-back/internal/risk"
+	"example.com/stocker-back/internal/screener"
+	"github.com/labstack/echo/v5"
 )
 
+// defaultPatternDays is the number of recent trading days scanned for patterns and events.
+const defaultPatternDays = 10
+
+// defaultDivergenceDays is the number of recent trading days a divergence must end within.
+const defaultDivergenceDays = 20
+
+// maxBacktestTickers bounds the tickers a backtest request replays, as each loads its
+// full daily history within the request; whole-market backtests run from the CLI.
+const maxBacktestTickers = 50
+
 // stockSearchHandler is controller handling stock search of single ticker.
 func (app *Application) stockSearchHandler(c echo.Context) error {
 	ticker := c.PathParam("ticker")
@@ -20,6 +37,81 @@ func (app *Application) stockSearchHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, ResponseData(stock))
 }
 
+// stockPatternsHandler is controller handling retrieval of recent candlestick patterns of single ticker.
+func (app *Application) stockPatternsHandler(c echo.Context) error {
+	ticker := c.PathParam("ticker")
+
+	days := defaultPatternDays
+	if daysStr := c.QueryParam("days"); daysStr != "" {
+		daysInt, err := strconv.Atoi(daysStr)
+		if err != nil || daysInt <= 0 {
+			return c.JSON(http.StatusOK, ResponseErr("invalid days"))
+		}
+		days = daysInt
+	}
+
+	patterns, err := app.query.GetPatternsByTicker(ticker, days)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(patterns))
+}
+
+// stockDivergencesHandler is controller handling retrieval of recent divergences of single ticker.
+func (app *Application) stockDivergencesHandler(c echo.Context) error {
+	ticker := c.PathParam("ticker")
+
+	days := defaultDivergenceDays
+	if daysStr := c.QueryParam("days"); daysStr != "" {
+		daysInt, err := strconv.Atoi(daysStr)
+		if err != nil || daysInt <= 0 {
+			return c.JSON(http.StatusOK, ResponseErr("invalid days"))
+		}
+		days = daysInt
+	}
+
+	divergences, err := app.query.GetDivergencesByTicker(ticker, days)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(divergences))
+}
+
+// stockLevelsHandler is controller handling retrieval of support/resistance levels of single ticker.
+func (app *Application) stockLevelsHandler(c echo.Context) error {
+	ticker := c.PathParam("ticker")
+
+	levels, err := app.query.GetLevelsByTicker(ticker)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(levels))
+}
+
+// stockEventsHandler is controller handling retrieval of recent crossover and breakout events of single ticker.
+func (app *Application) stockEventsHandler(c echo.Context) error {
+	ticker := c.PathParam("ticker")
+
+	days := defaultPatternDays
+	if daysStr := c.QueryParam("days"); daysStr != "" {
+		daysInt, err := strconv.Atoi(daysStr)
+		if err != nil || daysInt <= 0 {
+			return c.JSON(http.StatusOK, ResponseErr("invalid days"))
+		}
+		days = daysInt
+	}
+
+	events, err := app.query.GetEventsByTicker(ticker, days)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(events))
+}
+
 // stockCreateHandler is controller handling stock creation of single ticker.
 func (app *Application) stockCreateHandler(c echo.Context) error {
 	ticker := c.PathParam("ticker")
@@ -58,8 +150,13 @@ func (app *Application) screenUpdateHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, ResponseOk())
 }
 
-// screenReadHandler is controller handling retrieval of daily screens.
+// screenReadHandler is controller handling retrieval of daily screens, with trades
+// planned on them if ?capital= is given.
 func (app *Application) screenReadHandler(c echo.Context) error {
+	if c.QueryParam("capital") != "" {
+		return app.screenPlanHandler(c, "")
+	}
+
 	data, err := app.query.GetScreens()
 	if err != nil {
 		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
@@ -68,6 +165,483 @@ func (app *Application) screenReadHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, ResponseData(data))
 }
 
+// screenReadByNameHandler is controller handling retrieval of hits of a saved screen,
+// with trades planned on them if ?capital= is given.
+func (app *Application) screenReadByNameHandler(c echo.Context) error {
+	name := c.PathParam("name")
+	if c.QueryParam("capital") != "" {
+		return app.screenPlanHandler(c, name)
+	}
+
+	data, err := app.query.GetScreensByName(name)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(data))
+}
+
+// screenPlanHandler is controller handling hits of the screen of name, the default
+// screen if empty, with a trade planned on each for an account of ?capital=, risking
+// ?riskpct= percent per trade with ?stop= atr or support, see screener.PlanParams.
+func (app *Application) screenPlanHandler(c echo.Context, name string) error {
+	params := screener.DefaultPlanParams()
+	if stop := c.QueryParam("stop"); stop != "" {
+		params.Stop = screener.StopMethod(stop)
+	}
+	for param, target := range map[string]*float64{
+		"capital":        &params.Capital,
+		"riskpct":        &params.RiskPct,
+		"atrmultiple":    &params.ATRMultiple,
+		"supportbuffer":  &params.SupportBuffer,
+		"targetmultiple": &params.TargetMultiple,
+	} {
+		valueStr := c.QueryParam(param)
+		if valueStr == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return c.JSON(http.StatusOK, ResponseErr("invalid "+param))
+		}
+		*target = value
+	}
+
+	data, err := app.query.GetScreensPlanned(name, params)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(data))
+}
+
+// screenDefinitionListHandler is controller handling retrieval of all saved screens.
+func (app *Application) screenDefinitionListHandler(c echo.Context) error {
+	definitions, err := app.query.GetScreenDefinitions()
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(definitions))
+}
+
+// screenDefinitionCreateHandler is controller handling creation of a saved screen.
+func (app *Application) screenDefinitionCreateHandler(c echo.Context) error {
+	var definition screener.Definition
+	if err := c.Bind(&definition); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	if err := app.command.CreateScreenDefinition(definition); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	if err := app.scheduleScreen(definition); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// screenDefinitionUpdateHandler is controller handling update of a saved screen.
+func (app *Application) screenDefinitionUpdateHandler(c echo.Context) error {
+	var definition screener.Definition
+	if err := c.Bind(&definition); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	definition.Name = c.PathParam("name")
+
+	if err := app.command.UpdateScreenDefinition(definition); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	if err := app.scheduleScreen(definition); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// screenDefinitionDeleteHandler is controller handling deletion of a saved screen and its hits.
+func (app *Application) screenDefinitionDeleteHandler(c echo.Context) error {
+	name := c.PathParam("name")
+
+	if err := app.command.DeleteScreenDefinition(name); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	app.unscheduleScreen(name)
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// screenRunHandler is controller handling an immediate run of a saved screen.
+func (app *Application) screenRunHandler(c echo.Context) error {
+	name := c.PathParam("name")
+
+	if err := app.command.RunScreen(name); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// screenHistoryHandler is controller handling retrieval of hit history of a ticker,
+// in the saved screen given by query param `name` or the default daily screen.
+func (app *Application) screenHistoryHandler(c echo.Context) error {
+	ticker := c.PathParam("ticker")
+	name := c.QueryParam("name")
+
+	history, err := app.query.GetScreenHistoryByTicker(name, ticker)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(history))
+}
+
+// screenDiffHandler is controller handling retrieval of tickers entering and leaving a screen,
+// given query params `name` (default daily screen if empty) and `date` (latest run if empty).
+func (app *Application) screenDiffHandler(c echo.Context) error {
+	diff, err := app.query.GetScreenDiff(c.QueryParam("name"), c.QueryParam("date"))
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(diff))
+}
+
+// rankingReadHandler is controller handling retrieval of the latest composite score
+// ranking, optionally of `sector` and limited to the best `limit`.
+func (app *Application) rankingReadHandler(c echo.Context) error {
+	limit := 0
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		limitInt, err := strconv.Atoi(limitStr)
+		if err != nil || limitInt <= 0 {
+			return c.JSON(http.StatusOK, ResponseErr("invalid limit"))
+		}
+		limit = limitInt
+	}
+
+	data, err := app.query.GetRanking(c.QueryParam("sector"), limit)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(data))
+}
+
+// backtestHandler is controller handling a backtest of the rules posted.
+func (app *Application) backtestHandler(c echo.Context) error {
+	var config backtest.Config
+	if err := c.Bind(&config); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	if err := validateBacktestTickers(config); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	result, err := app.query.RunBacktest(config)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(result))
+}
+
+// backtestReportHandler is controller handling performance analytics of a backtest of
+// the rules posted, against the index of `benchmark` ticker if given.
+func (app *Application) backtestReportHandler(c echo.Context) error {
+	var config backtest.Config
+	if err := c.Bind(&config); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	if err := validateBacktestTickers(config); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	_, report, err := app.query.ReportBacktest(config, c.QueryParam("benchmark"))
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(report))
+}
+
+// validateBacktestTickers checks a backtest request names between 1 and
+// maxBacktestTickers tickers.
+func validateBacktestTickers(config backtest.Config) error {
+	if len(config.Tickers) == 0 || len(config.Tickers) > maxBacktestTickers {
+		return fmt.Errorf("%w: tickers must list 1 to %d tickers, run the whole market from the CLI",
+			backtest.ErrInvalidConfig, maxBacktestTickers)
+	}
+	return nil
+}
+
+// sweepListHandler is controller getting all parameter sweeps.
+func (app *Application) sweepListHandler(c echo.Context) error {
+	sweeps, err := app.query.GetSweeps()
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(sweeps))
+}
+
+// sweepCreateHandler is controller handling a parameter sweep posted, run in background.
+func (app *Application) sweepCreateHandler(c echo.Context) error {
+	var sweep backtest.Sweep
+	if err := c.Bind(&sweep); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	if err := app.command.StartSweep(sweep); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	go app.runSweep(sweep.Name)
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// sweepReadHandler is controller handling progress and ranked parameter sets of a sweep.
+func (app *Application) sweepReadHandler(c echo.Context) error {
+	result, err := app.query.GetSweepResult(c.PathParam("name"))
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(result))
+}
+
+// sweepResumeHandler is controller handling resumption of a stopped or failed sweep.
+func (app *Application) sweepResumeHandler(c echo.Context) error {
+	name := c.PathParam("name")
+
+	if _, err := app.query.GetSweepResult(name); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	go app.runSweep(name)
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// sweepDeleteHandler is controller handling deletion of a sweep and its runs.
+func (app *Application) sweepDeleteHandler(c echo.Context) error {
+	if err := app.command.DeleteSweep(c.PathParam("name")); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// runSweep runs the sweep of name, logging failure.
+func (app *Application) runSweep(name string) {
+	if err := app.command.RunSweep(name); err != nil {
+		app.pb.Logger().Error("runSweep", "error", err.Error(), "name", name)
+	}
+}
+
+// portfolioListHandler is controller getting all paper-trading accounts.
+func (app *Application) portfolioListHandler(c echo.Context) error {
+	accounts, err := app.query.GetAccounts()
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(accounts))
+}
+
+// portfolioCreateHandler is controller handling creation of a paper-trading account.
+func (app *Application) portfolioCreateHandler(c echo.Context) error {
+	var account portfolio.Account
+	if err := c.Bind(&account); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	if err := app.command.CreateAccount(account); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// portfolioReadHandler is controller handling holdings and P&L of an account.
+func (app *Application) portfolioReadHandler(c echo.Context) error {
+	valuation, err := app.query.GetPortfolio(c.PathParam("name"))
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(valuation))
+}
+
+// portfolioDeleteHandler is controller handling deletion of an account with its history.
+func (app *Application) portfolioDeleteHandler(c echo.Context) error {
+	if err := app.command.DeleteAccount(c.PathParam("name")); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// portfolioOrdersHandler is controller handling order history of an account.
+func (app *Application) portfolioOrdersHandler(c echo.Context) error {
+	orders, err := app.query.GetOrders(c.PathParam("name"))
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(orders))
+}
+
+// portfolioOrderCreateHandler is controller handling a paper order of an account.
+func (app *Application) portfolioOrderCreateHandler(c echo.Context) error {
+	var order portfolio.Order
+	if err := c.Bind(&order); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	order.Account, order.Source = c.PathParam("name"), portfolio.SourceManual
+
+	order, err := app.command.PlaceOrder(order)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(order))
+}
+
+// portfolioOrderCancelHandler is controller handling cancellation of a pending order.
+func (app *Application) portfolioOrderCancelHandler(c echo.Context) error {
+	if err := app.command.CancelOrder(c.PathParam("name"), c.PathParam("id")); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// portfolioRiskHandler is controller handling the risk of the holdings of an account.
+func (app *Application) portfolioRiskHandler(c echo.Context) error {
+	params, err := riskParams(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	report, err := app.query.GetPortfolioRisk(c.PathParam("name"), params)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(report))
+}
+
+// journalReadHandler is controller handling positions, realized gains and holding
+// statistics of the trade journal, replayed with ?method= fifo (default) or average.
+func (app *Application) journalReadHandler(c echo.Context) error {
+	book, err := app.query.GetJournal(journal.Method(c.QueryParam("method")))
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(book))
+}
+
+// journalRiskHandler is controller handling the risk of the open positions of the journal.
+func (app *Application) journalRiskHandler(c echo.Context) error {
+	params, err := riskParams(c)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	report, err := app.query.GetJournalRisk(params)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(report))
+}
+
+// riskParams reads ?benchmark=, ?lookback= and ?confidence= of a risk request, the
+// defaults of risk.DefaultParams for those left out. The benchmark is left empty
+// unless given, so that only one asked for must have daily data.
+func riskParams(c echo.Context) (risk.Params, error) {
+	params := risk.DefaultParams()
+	params.Benchmark = c.QueryParam("benchmark")
+	if lookbackStr := c.QueryParam("lookback"); lookbackStr != "" {
+		lookback, err := strconv.Atoi(lookbackStr)
+		if err != nil {
+			return risk.Params{}, err
+		}
+		params.Lookback = lookback
+	}
+	if confidenceStr := c.QueryParam("confidence"); confidenceStr != "" {
+		confidence, err := strconv.ParseFloat(confidenceStr, 64)
+		if err != nil {
+			return risk.Params{}, err
+		}
+		params.Confidence = confidence
+	}
+
+	return params, params.WithDefaults().Validate()
+}
+
+// journalTradesHandler is controller getting journal trades, of ?ticker= if given.
+func (app *Application) journalTradesHandler(c echo.Context) error {
+	trades, err := app.query.GetJournalTrades(c.QueryParam("ticker"))
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(trades))
+}
+
+// journalTradeCreateHandler is controller handling a trade recorded in the journal.
+func (app *Application) journalTradeCreateHandler(c echo.Context) error {
+	var trade journal.Trade
+	if err := c.Bind(&trade); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	trade, err := app.command.RecordTrade(trade)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(trade))
+}
+
+// journalTradeDeleteHandler is controller handling deletion of a journal trade.
+func (app *Application) journalTradeDeleteHandler(c echo.Context) error {
+	if err := app.command.DeleteTrade(c.PathParam("id")); err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseOk())
+}
+
+// journalImportHandler is controller handling a broker CSV export uploaded as form file
+// "file", in the columns of form value "format" as JSON over the default format.
+func (app *Application) journalImportHandler(c echo.Context) error {
+	format := journal.DefaultCSVFormat()
+	if formatJSON := c.FormValue("format"); formatJSON != "" {
+		if err := json.Unmarshal([]byte(formatJSON), &format); err != nil {
+			return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+		}
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+	defer file.Close()
+
+	result, err := app.command.ImportTrades(file, format)
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(result))
+}
+
 // trackingSearchHandler is controller getting all trackings.
 func (app *Application) trackingSearchHandler(c echo.Context) error {
 	data, err := app.query.GetTrackings()
@@ -112,6 +686,16 @@ func (app *Application) sectorReadHandler(c echo.Context) error {
 	return c.JSON(http.StatusOK, ResponseData(stocks))
 }
 
+// sectorStatsHandler is controller handling retrieval of aggregates of every sector.
+func (app *Application) sectorStatsHandler(c echo.Context) error {
+	stats, err := app.query.GetSectorStats()
+	if err != nil {
+		return c.JSON(http.StatusOK, ResponseErr(err.Error()))
+	}
+
+	return c.JSON(http.StatusOK, ResponseData(stats))
+}
+
 // randomStocksHandler is controller handling retrieval of random stocks given number.
 func (app *Application) randomStocksHandler(c echo.Context) error {
 	numStr := c.PathParam("num")
@@ -0,0 +1,298 @@
+//nolint:gomnd //ignore
+package backtest
+
+import (
+	"errors"
+	"fmt"
+
+	"example.com/stocker-back/internal/market"
+	"example.com/stocker-back/internal/screener"
+	"example.com/stocker-back/internal/stock"
+)
+
+var ErrInvalidConfig = errors.New("invalid backtest config")
+
+// Exit holds the exit conditions of a position; zero values turn a condition off.
+// StopLoss and TakeProfit are percentages from the entry price, HoldDays counts
+// trading days including the entry day, and Rule exits on the next open once it
+// matches at a close.
+type Exit struct {
+	HoldDays   int            `json:"holddays"`
+	StopLoss   float64        `json:"stoploss"`
+	TakeProfit float64        `json:"takeprofit"`
+	Rule       *screener.Rule `json:"rule,omitempty"`
+}
+
+// Config is the setup of a backtest: the entry rule, exits, the date range entries are
+// taken within (inclusive, "2006-01-02", empty for unbounded), the tickers to replay
+// (all if empty), the portfolio each entry takes an equal slot of, and the market
+// rules, A-share if unset.
+type Config struct {
+	Rule         screener.Rule  `json:"rule"`
+	Exit         Exit           `json:"exit"`
+	Start        string         `json:"start"`
+	End          string         `json:"end"`
+	Tickers      []string       `json:"tickers"`
+	Capital      float64        `json:"capital"`
+	MaxPositions int            `json:"maxpositions"`
+	Market       *market.Market `json:"market,omitempty"`
+}
+
+// WithDefaults fills unset capital and slots with 1,000,000 and 10 positions, and
+// unset market with market.DefaultMarket.
+func (c Config) WithDefaults() Config {
+	if c.Capital == 0.0 {
+		c.Capital = 1_000_000.0
+	}
+	if c.MaxPositions == 0 {
+		c.MaxPositions = 10
+	}
+	if c.Market == nil {
+		defaults := market.DefaultMarket()
+		c.Market = &defaults
+	}
+	return c
+}
+
+// Validate checks rules, exits, range and portfolio are usable.
+func (c Config) Validate() error {
+	if err := c.Rule.Validate(); err != nil {
+		return err
+	}
+	if c.Exit.Rule != nil {
+		if err := c.Exit.Rule.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Rule.NeedsSector() || (c.Exit.Rule != nil && c.Exit.Rule.NeedsSector()) {
+		return fmt.Errorf("%w: sector fields are not supported", ErrInvalidConfig)
+	}
+	if c.Exit.HoldDays < 0 || c.Exit.StopLoss < 0.0 || c.Exit.TakeProfit < 0.0 {
+		return fmt.Errorf("%w: exits must not be negative", ErrInvalidConfig)
+	}
+	if c.Start != "" && c.End != "" && c.Start > c.End {
+		return fmt.Errorf("%w: start after end", ErrInvalidConfig)
+	}
+	if c.Capital <= 0.0 || c.MaxPositions <= 0 {
+		return fmt.Errorf("%w: capital and maxpositions must be positive", ErrInvalidConfig)
+	}
+	if c.Market != nil {
+		if err := c.Market.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+	return nil
+}
+
+// History is the stock and its daily data in ascending date replayed by a backtest.
+type History struct {
+	Stock stock.Stock
+	Daily []stock.DailyData
+}
+
+// ExitReason tells why a position was closed.
+type ExitReason string
+
+const (
+	ExitStopLoss   ExitReason = "stoploss"
+	ExitTakeProfit ExitReason = "takeprofit"
+	ExitHold       ExitReason = "hold"
+	ExitRule       ExitReason = "rule"
+	ExitEnd        ExitReason = "end"
+)
+
+// Trade is valueobject of a closed position. Fees are those of both sides; Profit and
+// Return, in percent of the entry cost, are net of them. HoldDays counts trading days
+// including entry and exit day.
+type Trade struct {
+	Ticker     string     `json:"ticker"`
+	EntryDate  string     `json:"entrydate"`
+	EntryPrice float64    `json:"entryprice"`
+	ExitDate   string     `json:"exitdate"`
+	ExitPrice  float64    `json:"exitprice"`
+	Shares     float64    `json:"shares"`
+	Return     float64    `json:"return"`
+	Fees       float64    `json:"fees"`
+	Profit     float64    `json:"profit"`
+	HoldDays   int        `json:"holddays"`
+	Reason     ExitReason `json:"reason"`
+}
+
+// EquityPoint is the portfolio value at the close of Date, Invested being the part in positions.
+type EquityPoint struct {
+	Date     string  `json:"date"`
+	Equity   float64 `json:"equity"`
+	Invested float64 `json:"invested"`
+}
+
+// Summary aggregates trades and the equity curve. Returns and drawdown are percentages.
+type Summary struct {
+	Trades      int     `json:"trades"`
+	Wins        int     `json:"wins"`
+	WinRate     float64 `json:"winrate"`
+	AvgReturn   float64 `json:"avgreturn"`
+	TotalReturn float64 `json:"totalreturn"`
+	AvgHoldDays float64 `json:"avgholddays"`
+	MaxDrawdown float64 `json:"maxdrawdown"`
+}
+
+// Result is the outcome of a backtest.
+type Result struct {
+	Config  Config        `json:"config"`
+	Summary Summary       `json:"summary"`
+	Trades  []Trade       `json:"trades"`
+	Equity  []EquityPoint `json:"equity"`
+}
+
+// MonthlyReturn is the percentage return of the portfolio over Month, "2006-01".
+type MonthlyReturn struct {
+	Month  string  `json:"month"`
+	Return float64 `json:"return"`
+}
+
+// Benchmark compares daily returns of the portfolio with those of an index over the
+// days both traded. Return is the total of the index in percent; Alpha is annualised
+// in percent.
+type Benchmark struct {
+	Ticker      string  `json:"ticker"`
+	Return      float64 `json:"return"`
+	Alpha       float64 `json:"alpha"`
+	Beta        float64 `json:"beta"`
+	Correlation float64 `json:"correlation"`
+}
+
+// Report is the performance analytics of a backtest. Percentages are CAGR, Volatility,
+// MaxDrawdown, Exposure (mean share of equity invested) and monthly returns; Turnover
+// is the annualised traded value over mean equity; MaxDrawdownDays counts trading days
+// from the peak until it was regained, or until the end.
+type Report struct {
+	Start           string          `json:"start"`
+	End             string          `json:"end"`
+	Days            int             `json:"days"`
+	Summary         Summary         `json:"summary"`
+	CAGR            float64         `json:"cagr"`
+	Volatility      float64         `json:"volatility"`
+	Sharpe          float64         `json:"sharpe"`
+	Sortino         float64         `json:"sortino"`
+	Calmar          float64         `json:"calmar"`
+	MaxDrawdown     float64         `json:"maxdrawdown"`
+	MaxDrawdownDays int             `json:"maxdrawdowndays"`
+	Exposure        float64         `json:"exposure"`
+	Turnover        float64         `json:"turnover"`
+	Monthly         []MonthlyReturn `json:"monthly"`
+	Benchmark       *Benchmark      `json:"benchmark"`
+}
+
+// Metric names a Report figure sweeps rank parameter sets by.
+type Metric string
+
+const (
+	MetricTotalReturn Metric = "totalreturn"
+	MetricCAGR        Metric = "cagr"
+	MetricSharpe      Metric = "sharpe"
+	MetricSortino     Metric = "sortino"
+	MetricCalmar      Metric = "calmar"
+	MetricWinRate     Metric = "winrate"
+	MetricAvgReturn   Metric = "avgreturn"
+	MetricMaxDrawdown Metric = "maxdrawdown"
+)
+
+// Param is a swept parameter: Path addresses a number in the JSON of Config by keys
+// and array indexes joined with dots, e.g. "rule.value", "rule.rules.0.value" or
+// "exit.holddays".
+type Param struct {
+	Path   string    `json:"path"`
+	Values []float64 `json:"values"`
+}
+
+// WalkForward splits the trading dates of the sweep into Folds+InSample equal blocks;
+// fold i is in-sample over InSample blocks from block i and out-of-sample over the
+// block right after them.
+type WalkForward struct {
+	Folds    int `json:"folds"`
+	InSample int `json:"insample"`
+}
+
+// SweepStatus is the progress of a sweep.
+type SweepStatus string
+
+const (
+	SweepRunning SweepStatus = "running"
+	SweepDone    SweepStatus = "done"
+	SweepFailed  SweepStatus = "failed"
+)
+
+// Sweep is entity of a parameter sweep: Base is backtested over the grid of all
+// combinations of Params, on Workers concurrent runs, with walk-forward splits if set.
+type Sweep struct {
+	Name        string       `json:"name"`
+	Base        Config       `json:"base"`
+	Params      []Param      `json:"params"`
+	Metric      Metric       `json:"metric"`
+	Workers     int          `json:"workers"`
+	WalkForward *WalkForward `json:"walkforward,omitempty"`
+	Status      SweepStatus  `json:"status"`
+	Error       string       `json:"error"`
+}
+
+// Sample tells which part of the dates a sweep run covers.
+type Sample string
+
+const (
+	SampleFull Sample = "full"
+	SampleIn   Sample = "in"
+	SampleOut  Sample = "out"
+)
+
+// SweepRun is valueobject of one backtest of a sweep: parameter set Set over Sample of
+// Fold, -1 for the full range.
+type SweepRun struct {
+	Set     int                `json:"set"`
+	Params  map[string]float64 `json:"params"`
+	Fold    int                `json:"fold"`
+	Sample  Sample             `json:"sample"`
+	Start   string             `json:"start"`
+	End     string             `json:"end"`
+	Metric  float64            `json:"metric"`
+	Summary Summary            `json:"summary"`
+}
+
+// SweepSet is the outcome of a parameter set. Without walk-forward Full is its metric;
+// with it InSample and OutOfSample are means over folds and Degradation is the
+// percentage the metric falls out of sample, a sign of overfitting when large.
+type SweepSet struct {
+	Set         int                `json:"set"`
+	Rank        int                `json:"rank"`
+	Params      map[string]float64 `json:"params"`
+	Full        float64            `json:"full"`
+	InSample    float64            `json:"insample"`
+	OutOfSample float64            `json:"outofsample"`
+	Degradation float64            `json:"degradation"`
+}
+
+// SweepFold is a walk-forward fold: the set best in sample and how it did out of sample.
+type SweepFold struct {
+	Fold        int     `json:"fold"`
+	InStart     string  `json:"instart"`
+	InEnd       string  `json:"inend"`
+	OutStart    string  `json:"outstart"`
+	OutEnd      string  `json:"outend"`
+	Best        int     `json:"best"`
+	InSample    float64 `json:"insample"`
+	OutOfSample float64 `json:"outofsample"`
+}
+
+// SweepResult ranks the parameter sets of a sweep, by OutOfSample with walk-forward and
+// Full otherwise. Efficiency is the mean out-of-sample over mean in-sample metric of
+// the sets picked per fold, in percent.
+type SweepResult struct {
+	Name       string      `json:"name"`
+	Metric     Metric      `json:"metric"`
+	Status     SweepStatus `json:"status"`
+	Error      string      `json:"error"`
+	Total      int         `json:"total"`
+	Done       int         `json:"done"`
+	Sets       []SweepSet  `json:"sets"`
+	Folds      []SweepFold `json:"folds"`
+	Efficiency float64     `json:"efficiency"`
+}
@@ -0,0 +1,13 @@
+package backtest
+
+type Repository interface {
+	GetSweeps() ([]Sweep, error)
+	GetSweepByName(name string) (Sweep, error)
+	// SaveSweep creates the sweep or updates the one of the same name.
+	SaveSweep(sweep Sweep) error
+	// DeleteSweepByName deletes the sweep along with its runs.
+	DeleteSweepByName(name string) error
+
+	GetSweepRuns(name string) ([]SweepRun, error)
+	SaveSweepRun(name string, run SweepRun) error
+}
@@ -0,0 +1,381 @@
+package backtest
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"example.com/stocker-back/internal/common"
+	"example.com/stocker-back/internal/screener"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// replay is a ticker prepared for replaying: candles with the indicator state of each,
+// and the candle index of every date.
+type replay struct {
+	stock      stock.Stock
+	daily      []stock.DailyData
+	candles    []stock.OHLC
+	indicators []stock.Indicators
+	index      map[string]int
+}
+
+// position is an open holding.
+type position struct {
+	ticker     string
+	entryDate  string
+	entryIdx   int
+	entryPrice float64
+	entryFees  float64
+	shares     float64
+	lastIdx    int
+	lastDate   string
+	lastClose  float64
+}
+
+// Run replays histories day by day. A ticker matching the entry rule at a close within
+// the date range is bought at the next open with an equal slot of the equity, lowest J
+// first when signals outnumber free slots; it is held until an exit condition fires.
+// Stop-loss and take-profit fill at their price intraday, or at the open when it gaps
+// past them, stop-loss first when both are touched; holding period exits at the close;
+// the exit rule at the next open. Positions still open at the end close at their last close.
+//
+// Fills follow config.Market: no sell on the entry day under T+1, whole lots, no buy
+// at limit-up nor sell at limit-down, in which case the exit is retried the next day
+// while it still fires, and fees charged on both sides.
+//
+// Fundamentals are those of History.Stock throughout, so rules on them look ahead.
+func Run(config Config, histories []History) (Result, error) {
+	config = config.WithDefaults()
+	if err := config.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	return RunOn(config, NewUniverse(histories))
+}
+
+// Universe is histories prepared for replaying, shared read-only by any number of runs.
+type Universe struct {
+	replays map[string]*replay
+	tickers []string
+}
+
+// NewUniverse computes indicators of histories once; tickers whose history is too
+// short for indicators are left out.
+func NewUniverse(histories []History) *Universe {
+	replays := make(map[string]*replay, len(histories))
+	for _, h := range histories {
+		candles := stock.DailyData2OHLC(h.Daily)
+		indicators, err := stock.ComputeIndicators(h.Stock.Ticker, candles)
+		if err != nil {
+			continue
+		}
+		index := make(map[string]int, len(candles))
+		for idx, candle := range candles {
+			index[candle.Date] = idx
+		}
+		replays[h.Stock.Ticker] = &replay{
+			stock:      h.Stock,
+			daily:      h.Daily,
+			candles:    candles,
+			indicators: indicators,
+			index:      index,
+		}
+	}
+
+	tickers := lo.Keys(replays)
+	slices.Sort(tickers)
+
+	return &Universe{
+		replays: replays,
+		tickers: tickers,
+	}
+}
+
+// Dates returns the trading dates of the universe between start and end inclusive,
+// "2006-01-02" or empty for unbounded, in ascending order.
+func (u *Universe) Dates(start, end string) []string {
+	dates := make([]string, 0)
+	for _, r := range u.replays {
+		for _, candle := range r.candles {
+			if (start == "" || common.Day(candle.Date) >= start) && (end == "" || common.Day(candle.Date) <= end) {
+				dates = append(dates, candle.Date)
+			}
+		}
+	}
+	slices.Sort(dates)
+	return slices.Compact(dates)
+}
+
+// RunOn is Run over a prepared universe.
+func RunOn(config Config, universe *Universe) (Result, error) {
+	config = config.WithDefaults()
+	if err := config.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	replays, tickers := universe.replays, universe.tickers
+	if len(config.Tickers) > 0 {
+		tickers = lo.Filter(tickers, func(ticker string, _ int) bool {
+			return slices.Contains(config.Tickers, ticker)
+		})
+	}
+	dates := universe.Dates(config.Start, config.End)
+	if len(config.Tickers) > 0 {
+		dates = lo.Filter(dates, func(date string, _ int) bool {
+			return lo.SomeBy(tickers, func(ticker string) bool {
+				_, ok := replays[ticker].index[date]
+				return ok
+			})
+		})
+	}
+
+	market := *config.Market
+	cash := config.Capital
+	positions := make(map[string]*position)
+	pendingEntries := make([]string, 0)
+	pendingExits := make(map[string]bool)
+	trades := make([]Trade, 0)
+	equity := make([]EquityPoint, 0, len(dates))
+
+	// closePosition sells p at price unless blocked at limit-down, telling whether it did.
+	closePosition := func(p *position, date string, idx int, price float64, reason ExitReason) bool {
+		r := replays[p.ticker]
+		if reason != ExitEnd && idx > 0 && !market.CanSell(r.stock, r.candles[idx-1].Close, price) {
+			return false
+		}
+
+		value := p.shares * price
+		sellFees := market.SellFees(value)
+		cost := p.shares*p.entryPrice + p.entryFees
+		profit := value - sellFees - cost
+		cash += value - sellFees
+		trades = append(trades, Trade{
+			Ticker:     p.ticker,
+			EntryDate:  p.entryDate,
+			EntryPrice: p.entryPrice,
+			ExitDate:   date,
+			ExitPrice:  price,
+			Shares:     p.shares,
+			Return:     100.0 * profit / cost,
+			Fees:       p.entryFees + sellFees,
+			Profit:     profit,
+			HoldDays:   idx - p.entryIdx + 1,
+			Reason:     reason,
+		})
+		delete(positions, p.ticker)
+		delete(pendingExits, p.ticker)
+		return true
+	}
+	investedNow := func() float64 {
+		total := 0.0
+		for _, p := range positions {
+			total += p.shares * p.lastClose
+		}
+		return total
+	}
+
+	for _, date := range dates {
+		// Orders placed at the last close fill at this open.
+		for _, ticker := range sortedKeys(pendingExits) {
+			r, p := replays[ticker], positions[ticker]
+			if idx, ok := r.index[date]; ok && p != nil {
+				closePosition(p, date, idx, r.candles[idx].Open, ExitRule)
+			}
+		}
+		slot := (cash + investedNow()) / float64(config.MaxPositions)
+		for _, ticker := range pendingEntries {
+			r := replays[ticker]
+			idx, ok := r.index[date]
+			if !ok || r.candles[idx].Open <= 0.0 {
+				continue
+			}
+			price := r.candles[idx].Open
+			if idx > 0 && !market.CanBuy(r.stock, r.candles[idx-1].Close, price) {
+				continue
+			}
+			shares := market.Shares(min(slot, cash), price)
+			if shares <= 0.0 {
+				continue
+			}
+			fees := market.BuyFees(shares * price)
+			cash -= shares*price + fees
+			positions[ticker] = &position{
+				ticker:     ticker,
+				entryDate:  date,
+				entryIdx:   idx,
+				entryPrice: price,
+				entryFees:  fees,
+				shares:     shares,
+				lastIdx:    idx,
+				lastDate:   date,
+				lastClose:  price,
+			}
+		}
+		pendingEntries = pendingEntries[:0]
+
+		// Exits during and at the close of the day.
+		for _, ticker := range sortedKeys(positions) {
+			r, p := replays[ticker], positions[ticker]
+			idx, ok := r.index[date]
+			if !ok {
+				continue
+			}
+			candle := r.candles[idx]
+			p.lastIdx, p.lastDate, p.lastClose = idx, date, candle.Close
+
+			if !market.T1 || idx > p.entryIdx {
+				if price, ok := stopPrice(config.Exit, p.entryPrice, candle); ok && closePosition(p, date, idx, price, ExitStopLoss) {
+					continue
+				}
+				if price, ok := takeProfitPrice(config.Exit, p.entryPrice, candle); ok && closePosition(p, date, idx, price, ExitTakeProfit) {
+					continue
+				}
+				if config.Exit.HoldDays > 0 && idx-p.entryIdx+1 >= config.Exit.HoldDays && closePosition(p, date, idx, candle.Close, ExitHold) {
+					continue
+				}
+			}
+			// An exit blocked at limit-down is retried while the rule keeps matching.
+			if config.Exit.Rule != nil {
+				if r.matches(*config.Exit.Rule, idx) {
+					pendingExits[ticker] = true
+				} else {
+					delete(pendingExits, ticker)
+				}
+			}
+		}
+
+		invested := investedNow()
+		equity = append(equity, EquityPoint{Date: date, Equity: cash + invested, Invested: invested})
+
+		// Entries signalled at the close.
+		type signal struct {
+			ticker string
+			j      float64
+		}
+		signals := make([]signal, 0)
+		for _, ticker := range tickers {
+			r := replays[ticker]
+			idx, ok := r.index[date]
+			if !ok || positions[ticker] != nil || !r.indicators[idx].ValidKDJ() {
+				continue
+			}
+			if r.matches(config.Rule, idx) {
+				signals = append(signals, signal{ticker: ticker, j: r.indicators[idx].J})
+			}
+		}
+		slices.SortStableFunc(signals, func(a, b signal) int {
+			return cmp.Compare(a.j, b.j)
+		})
+		free := config.MaxPositions - len(positions)
+		for _, s := range signals[:min(len(signals), max(free, 0))] {
+			pendingEntries = append(pendingEntries, s.ticker)
+		}
+	}
+
+	for _, ticker := range sortedKeys(positions) {
+		p := positions[ticker]
+		closePosition(p, p.lastDate, p.lastIdx, p.lastClose, ExitEnd)
+	}
+	slices.SortStableFunc(trades, func(a, b Trade) int {
+		if c := cmp.Compare(a.EntryDate, b.EntryDate); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Ticker, b.Ticker)
+	})
+
+	return Result{
+		Config:  config,
+		Summary: Summarise(config.Capital, trades, equity),
+		Trades:  trades,
+		Equity:  equity,
+	}, nil
+}
+
+// Summarise aggregates trades and the equity curve of a backtest started with capital.
+func Summarise(capital float64, trades []Trade, equity []EquityPoint) Summary {
+	summary := Summary{
+		Trades:      len(trades),
+		Wins:        0,
+		WinRate:     0.0,
+		AvgReturn:   0.0,
+		TotalReturn: 0.0,
+		AvgHoldDays: 0.0,
+		MaxDrawdown: 0.0,
+	}
+
+	if len(trades) > 0 {
+		summary.Wins = lo.CountBy(trades, func(t Trade) bool { return t.Return > 0.0 })
+		summary.WinRate = 100.0 * float64(summary.Wins) / float64(len(trades))
+		summary.AvgReturn = lo.SumBy(trades, func(t Trade) float64 { return t.Return }) / float64(len(trades))
+		summary.AvgHoldDays = float64(lo.SumBy(trades, func(t Trade) int { return t.HoldDays })) / float64(len(trades))
+	}
+
+	if len(equity) > 0 {
+		summary.TotalReturn = 100.0 * (equity[len(equity)-1].Equity/capital - 1.0)
+		summary.MaxDrawdown = maxDrawdown(capital, equity)
+	}
+
+	return summary
+}
+
+// maxDrawdown is the largest percentage fall of equity from a previous peak.
+func maxDrawdown(capital float64, equity []EquityPoint) float64 {
+	peak, drawdown := capital, 0.0
+	for _, point := range equity {
+		peak = math.Max(peak, point.Equity)
+		drawdown = math.Max(drawdown, 100.0*(peak-point.Equity)/peak)
+	}
+	return drawdown
+}
+
+// stopPrice returns the fill of the stop-loss when candle touches it.
+func stopPrice(exit Exit, entryPrice float64, candle stock.OHLC) (float64, bool) {
+	if exit.StopLoss <= 0.0 {
+		return 0.0, false
+	}
+	price := entryPrice * (1.0 - exit.StopLoss/100.0)
+	if candle.Low > price {
+		return 0.0, false
+	}
+	return math.Min(candle.Open, price), true
+}
+
+// takeProfitPrice returns the fill of the take-profit when candle touches it.
+func takeProfitPrice(exit Exit, entryPrice float64, candle stock.OHLC) (float64, bool) {
+	if exit.TakeProfit <= 0.0 {
+		return 0.0, false
+	}
+	price := entryPrice * (1.0 + exit.TakeProfit/100.0)
+	if candle.High < price {
+		return 0.0, false
+	}
+	return math.Max(candle.Open, price), true
+}
+
+// matches evaluates rule at the close of candle idx as the daily screen would,
+// with candles of the last stock.IndicatorsLookback days for candle criteria and
+// technical fields.
+func (r *replay) matches(rule screener.Rule, idx int) bool {
+	facts, err := screener.NewFacts(r.stock, r.indicators[idx], r.daily[idx])
+	if err != nil {
+		return false
+	}
+	start := max(0, idx+1-stock.IndicatorsLookback)
+	if rule.NeedsCandles() {
+		facts.Candles = r.candles[start : idx+1]
+	}
+	if rule.NeedsTechnicals() {
+		facts.SetTechnicals(stock.DailyData2OHLCV(r.daily[start : idx+1]))
+	}
+
+	ok, err := rule.Eval(facts)
+	return err == nil && ok
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := lo.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
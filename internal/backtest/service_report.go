@@ -0,0 +1,203 @@
+//nolint:gomnd //ignore
+package backtest
+
+import (
+	"math"
+
+	"example.com/stocker-back/internal/common"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// ReportParams holds the annual risk-free rate in percent and trading days per year.
+type ReportParams struct {
+	RiskFree    float64 `json:"riskfree"`
+	TradingDays int     `json:"tradingdays"`
+}
+
+// DefaultReportParams returns a zero risk-free rate and 252 trading days.
+func DefaultReportParams() ReportParams {
+	return ReportParams{
+		RiskFree:    0.0,
+		TradingDays: 252,
+	}
+}
+
+// ComputeReport computes performance analytics of result, against the daily data of a
+// benchmark index in ascending date if given.
+func ComputeReport(result Result, benchmark []stock.DailyData, params ReportParams) Report {
+	capital := result.Config.WithDefaults().Capital
+	equity := result.Equity
+
+	report := Report{
+		Start:           "",
+		End:             "",
+		Days:            len(equity),
+		Summary:         result.Summary,
+		CAGR:            0.0,
+		Volatility:      0.0,
+		Sharpe:          0.0,
+		Sortino:         0.0,
+		Calmar:          0.0,
+		MaxDrawdown:     maxDrawdown(capital, equity),
+		MaxDrawdownDays: maxDrawdownDays(capital, equity),
+		Exposure:        0.0,
+		Turnover:        0.0,
+		Monthly:         monthlyReturns(capital, equity),
+		Benchmark:       nil,
+	}
+	if len(equity) == 0 {
+		return report
+	}
+	report.Start, report.End = common.Day(equity[0].Date), common.Day(equity[len(equity)-1].Date)
+
+	years := float64(len(equity)) / float64(params.TradingDays)
+	growth := equity[len(equity)-1].Equity / capital
+	report.CAGR = 100.0 * (math.Pow(growth, 1.0/years) - 1.0)
+
+	returns := dailyReturns(capital, equity)
+	riskFree := params.RiskFree / 100.0 / float64(params.TradingDays)
+	mean := lo.Sum(returns) / float64(len(returns))
+	std := stddev(returns)
+	downside := math.Sqrt(lo.SumBy(returns, func(r float64) float64 {
+		return math.Pow(math.Min(r-riskFree, 0.0), 2)
+	}) / float64(len(returns)))
+	annual := math.Sqrt(float64(params.TradingDays))
+
+	report.Volatility = 100.0 * std * annual
+	if std > 0.0 {
+		report.Sharpe = (mean - riskFree) / std * annual
+	}
+	if downside > 0.0 {
+		report.Sortino = (mean - riskFree) / downside * annual
+	}
+	if report.MaxDrawdown > 0.0 {
+		report.Calmar = report.CAGR / report.MaxDrawdown
+	}
+
+	report.Exposure = 100.0 * lo.SumBy(equity, func(p EquityPoint) float64 {
+		return p.Invested / p.Equity
+	}) / float64(len(equity))
+
+	traded := lo.SumBy(result.Trades, func(t Trade) float64 {
+		return t.Shares * (t.EntryPrice + t.ExitPrice)
+	})
+	meanEquity := lo.SumBy(equity, func(p EquityPoint) float64 { return p.Equity }) / float64(len(equity))
+	report.Turnover = traded / 2.0 / meanEquity / years
+
+	if len(benchmark) > 0 {
+		report.Benchmark = compareBenchmark(capital, equity, benchmark, params)
+	}
+
+	return report
+}
+
+// dailyReturns are the returns of equity day over day, the first over capital.
+func dailyReturns(capital float64, equity []EquityPoint) []float64 {
+	returns := make([]float64, len(equity))
+	prev := capital
+	for idx, point := range equity {
+		returns[idx] = point.Equity/prev - 1.0
+		prev = point.Equity
+	}
+	return returns
+}
+
+// maxDrawdownDays is the longest run of days equity spent below a previous peak.
+func maxDrawdownDays(capital float64, equity []EquityPoint) int {
+	peak, longest, current := capital, 0, 0
+	for _, point := range equity {
+		if point.Equity >= peak {
+			peak, current = point.Equity, 0
+			continue
+		}
+		current++
+		longest = max(longest, current)
+	}
+	return longest
+}
+
+// monthlyReturns compounds equity by calendar month.
+func monthlyReturns(capital float64, equity []EquityPoint) []MonthlyReturn {
+	output := make([]MonthlyReturn, 0)
+	prev := capital
+	for idx, point := range equity {
+		month := common.Day(point.Date)[:len("2006-01")]
+		if idx+1 < len(equity) && common.Day(equity[idx+1].Date)[:len("2006-01")] == month {
+			continue
+		}
+		output = append(output, MonthlyReturn{Month: month, Return: 100.0 * (point.Equity/prev - 1.0)})
+		prev = point.Equity
+	}
+	return output
+}
+
+// compareBenchmark regresses daily returns of equity on those of the benchmark over
+// the days both have a return.
+func compareBenchmark(capital float64, equity []EquityPoint, benchmark []stock.DailyData, params ReportParams) *Benchmark {
+	closes := make(map[string]float64, len(benchmark))
+	for _, d := range benchmark {
+		closes[d.Date] = d.Close
+	}
+
+	returns := dailyReturns(capital, equity)
+	portfolio := make([]float64, 0, len(equity))
+	index := make([]float64, 0, len(equity))
+	first, last := 0.0, 0.0
+	for idx := 1; idx < len(equity); idx++ {
+		prev, okPrev := closes[equity[idx-1].Date]
+		curr, ok := closes[equity[idx].Date]
+		if !okPrev || !ok || prev == 0.0 {
+			continue
+		}
+		if first == 0.0 {
+			first = prev
+		}
+		last = curr
+		portfolio = append(portfolio, returns[idx])
+		index = append(index, curr/prev-1.0)
+	}
+
+	output := &Benchmark{
+		Ticker:      benchmark[0].Ticker,
+		Return:      0.0,
+		Alpha:       0.0,
+		Beta:        0.0,
+		Correlation: 0.0,
+	}
+	if len(index) < 2 {
+		return output
+	}
+	output.Return = 100.0 * (last/first - 1.0)
+
+	meanP := lo.Sum(portfolio) / float64(len(portfolio))
+	meanI := lo.Sum(index) / float64(len(index))
+	covariance := 0.0
+	for idx := range index {
+		covariance += (portfolio[idx] - meanP) * (index[idx] - meanI)
+	}
+	covariance /= float64(len(index))
+
+	stdP, stdI := stddev(portfolio), stddev(index)
+	if stdI > 0.0 {
+		output.Beta = covariance / (stdI * stdI)
+	}
+	if stdP > 0.0 && stdI > 0.0 {
+		output.Correlation = covariance / (stdP * stdI)
+	}
+	riskFree := params.RiskFree / 100.0 / float64(params.TradingDays)
+	output.Alpha = 100.0 * float64(params.TradingDays) * ((meanP - riskFree) - output.Beta*(meanI-riskFree))
+
+	return output
+}
+
+// stddev is the population standard deviation of values.
+func stddev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	mean := lo.Sum(values) / float64(len(values))
+	return math.Sqrt(lo.SumBy(values, func(v float64) float64 {
+		return (v - mean) * (v - mean)
+	}) / float64(len(values)))
+}
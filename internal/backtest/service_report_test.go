@@ -0,0 +1,79 @@
+//nolint:testpackage,lll //ignore
+package backtest
+
+import (
+	"math"
+	"testing"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeReport(t *testing.T) {
+	equity := []EquityPoint{
+		{Date: "2024-01-30 00:00:00.000Z", Equity: 110, Invested: 50},
+		{Date: "2024-01-31 00:00:00.000Z", Equity: 99, Invested: 50},
+		{Date: "2024-02-01 00:00:00.000Z", Equity: 104.5, Invested: 0},
+		{Date: "2024-02-02 00:00:00.000Z", Equity: 121, Invested: 0},
+	}
+	trades := []Trade{{Ticker: "a", Shares: 1, EntryPrice: 10, ExitPrice: 12, Return: 20, HoldDays: 2}} //nolint:exhaustruct
+	result := Result{
+		Config:  Config{Capital: 100, Market: genericMarket()}, //nolint:exhaustruct
+		Summary: Summarise(100, trades, equity),
+		Trades:  trades,
+		Equity:  equity,
+	}
+
+	report := ComputeReport(result, nil, DefaultReportParams())
+	assert.Equal(t, "2024-01-30", report.Start)
+	assert.Equal(t, "2024-02-02", report.End)
+	assert.Equal(t, 4, report.Days)
+	assert.InDelta(t, 100.0*(math.Pow(1.21, 252.0/4.0)-1.0), report.CAGR, 1e-6)
+	assert.InDelta(t, 10.0, report.MaxDrawdown, 1e-9)
+	assert.Equal(t, 2, report.MaxDrawdownDays)
+	assert.InDelta(t, report.CAGR/10.0, report.Calmar, 1e-9)
+	assert.InDelta(t, 100.0*(50.0/110.0+50.0/99.0)/4.0, report.Exposure, 1e-9)
+	assert.InDelta(t, 11.0/108.625/(4.0/252.0), report.Turnover, 1e-9)
+	assert.Greater(t, report.Sharpe, 0.0)
+	assert.Greater(t, report.Sortino, report.Sharpe)
+	assert.Nil(t, report.Benchmark)
+
+	if assert.Len(t, report.Monthly, 2) {
+		assert.Equal(t, "2024-01", report.Monthly[0].Month)
+		assert.InDelta(t, -1.0, report.Monthly[0].Return, 1e-9)
+		assert.Equal(t, "2024-02", report.Monthly[1].Month)
+		assert.InDelta(t, 100.0*(121.0/99.0-1.0), report.Monthly[1].Return, 1e-9)
+	}
+}
+
+func TestComputeReportBenchmark(t *testing.T) {
+	equity := []EquityPoint{
+		{Date: "2024-01-30 00:00:00.000Z", Equity: 110, Invested: 110},
+		{Date: "2024-01-31 00:00:00.000Z", Equity: 99, Invested: 99},
+		{Date: "2024-02-01 00:00:00.000Z", Equity: 104.5, Invested: 104.5},
+		{Date: "2024-02-02 00:00:00.000Z", Equity: 121, Invested: 121},
+	}
+	result := Result{Config: Config{Capital: 100}, Summary: Summary{}, Trades: nil, Equity: equity} //nolint:exhaustruct
+
+	// The index moves half as much as the portfolio every day.
+	benchmark := make([]stock.DailyData, len(equity))
+	closes := 10.0
+	for idx, point := range equity {
+		if idx > 0 {
+			closes *= 1.0 + (point.Equity/equity[idx-1].Equity-1.0)/2.0
+		}
+		benchmark[idx] = stock.NewEmptyDailyData()
+		benchmark[idx].Ticker = "1.000300"
+		benchmark[idx].Date = point.Date
+		benchmark[idx].Close = closes
+	}
+
+	report := ComputeReport(result, benchmark, DefaultReportParams())
+	if assert.NotNil(t, report.Benchmark) {
+		assert.Equal(t, "1.000300", report.Benchmark.Ticker)
+		assert.InDelta(t, 2.0, report.Benchmark.Beta, 1e-9)
+		assert.InDelta(t, 1.0, report.Benchmark.Correlation, 1e-9)
+		assert.InDelta(t, 0.0, report.Benchmark.Alpha, 1e-9)
+		assert.InDelta(t, 100.0*(closes/10.0-1.0), report.Benchmark.Return, 1e-9)
+	}
+}
@@ -0,0 +1,444 @@
+package backtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"example.com/stocker-back/internal/common"
+	"github.com/samber/lo"
+)
+
+// WithDefaults fills unset metric with sharpe and unset workers with the number of CPUs.
+func (s Sweep) WithDefaults() Sweep {
+	if s.Metric == "" {
+		s.Metric = MetricSharpe
+	}
+	if s.Workers == 0 {
+		s.Workers = runtime.NumCPU()
+	}
+	return s
+}
+
+// Validate checks the sweep is named, its metric known, and every parameter set
+// applies to Base as a valid config.
+func (s Sweep) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("%w: sweep name is required", ErrInvalidConfig)
+	}
+	if !slices.Contains(metrics, s.Metric) {
+		return fmt.Errorf("%w: unknown metric %q", ErrInvalidConfig, s.Metric)
+	}
+	if s.Workers < 0 {
+		return fmt.Errorf("%w: workers must not be negative", ErrInvalidConfig)
+	}
+	if len(s.Params) == 0 {
+		return fmt.Errorf("%w: no parameter to sweep", ErrInvalidConfig)
+	}
+	for _, p := range s.Params {
+		if p.Path == "" || len(p.Values) == 0 {
+			return fmt.Errorf("%w: parameter needs path and values", ErrInvalidConfig)
+		}
+	}
+	if wf := s.WalkForward; wf != nil && (wf.Folds <= 0 || wf.InSample <= 0) {
+		return fmt.Errorf("%w: walkforward folds and insample must be positive", ErrInvalidConfig)
+	}
+
+	for _, params := range s.Grid() {
+		config, err := ApplyParams(s.Base, params)
+		if err != nil {
+			return err
+		}
+		if err := config.WithDefaults().Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SameSpec tells whether s and other sweep the same parameters over the same base and
+// rank them by the same metric, so that the runs of one are those of the other.
+func (s Sweep) SameSpec(other Sweep) bool {
+	spec := func(sweep Sweep) string {
+		sweep = sweep.WithDefaults()
+		data, err := json.Marshal([]any{sweep.Base, sweep.Params, sweep.Metric, sweep.WalkForward})
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+	a, b := spec(s), spec(other)
+	return a != "" && a == b
+}
+
+// Grid returns every combination of parameter values, the last parameter varying fastest.
+func (s Sweep) Grid() []map[string]float64 {
+	grid := []map[string]float64{{}}
+	for _, p := range s.Params {
+		next := make([]map[string]float64, 0, len(grid)*len(p.Values))
+		for _, params := range grid {
+			for _, value := range p.Values {
+				set := make(map[string]float64, len(params)+1)
+				for k, v := range params {
+					set[k] = v
+				}
+				set[p.Path] = value
+				next = append(next, set)
+			}
+		}
+		grid = next
+	}
+	return grid
+}
+
+// Total returns the number of runs of the sweep: one over the full range per
+// parameter set, plus one in and one out of sample per fold with walk-forward.
+func (s Sweep) Total() int {
+	perSet := 1
+	if s.WalkForward != nil {
+		perSet += 2 * s.WalkForward.Folds
+	}
+	return len(s.Grid()) * perSet
+}
+
+// Key identifies the run within its sweep.
+func (r SweepRun) Key() string {
+	return fmt.Sprintf("%d/%d/%s", r.Set, r.Fold, r.Sample)
+}
+
+// ApplyParams returns config with the number at each path set to its value, see Param.
+func ApplyParams(config Config, params map[string]float64) (Config, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return Config{}, err
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return Config{}, err
+	}
+
+	for _, path := range sortedKeys(params) {
+		if err := setPath(tree, strings.Split(path, "."), params[path]); err != nil {
+			return Config{}, fmt.Errorf("%w: parameter %q: %w", ErrInvalidConfig, path, err)
+		}
+	}
+
+	data, err = json.Marshal(tree)
+	if err != nil {
+		return Config{}, err
+	}
+	var output Config
+	if err := json.Unmarshal(data, &output); err != nil {
+		return Config{}, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return output, nil
+}
+
+// setPath sets value at keys of node; the last key may be absent as zero values are
+// omitted from JSON, the ones before must exist.
+func setPath(node any, keys []string, value float64) error {
+	key, last := keys[0], len(keys) == 1
+
+	switch n := node.(type) {
+	case map[string]any:
+		if last {
+			n[key] = value
+			return nil
+		}
+		child, ok := n[key]
+		if !ok || child == nil {
+			return fmt.Errorf("no %q", key)
+		}
+		return setPath(child, keys[1:], value)
+	case []any:
+		idx, err := strconv.Atoi(key)
+		if err != nil || idx < 0 || idx >= len(n) {
+			return fmt.Errorf("no index %q", key)
+		}
+		if last {
+			n[idx] = value
+			return nil
+		}
+		return setPath(n[idx], keys[1:], value)
+	default:
+		return fmt.Errorf("%q is not an object nor an array", key)
+	}
+}
+
+// Plan returns the runs of the sweep over universe, metrics left to fill. Walk-forward
+// splits the trading dates within the range of Base.
+func (s Sweep) Plan(universe *Universe) ([]SweepRun, error) {
+	type span struct {
+		fold       int
+		sample     Sample
+		start, end string
+	}
+	spans := []span{{fold: -1, sample: SampleFull, start: s.Base.Start, end: s.Base.End}}
+
+	if wf := s.WalkForward; wf != nil {
+		dates := universe.Dates(s.Base.Start, s.Base.End)
+		size := len(dates) / (wf.Folds + wf.InSample)
+		if size == 0 {
+			return nil, fmt.Errorf("%w: %d dates are too few for walkforward", ErrInvalidConfig, len(dates))
+		}
+		for fold := range wf.Folds {
+			in := dates[fold*size : (fold+wf.InSample)*size]
+			out := dates[(fold+wf.InSample)*size : (fold+wf.InSample+1)*size]
+			spans = append(spans,
+				span{fold: fold, sample: SampleIn, start: common.Day(in[0]), end: common.Day(in[len(in)-1])},
+				span{fold: fold, sample: SampleOut, start: common.Day(out[0]), end: common.Day(out[len(out)-1])},
+			)
+		}
+	}
+
+	runs := make([]SweepRun, 0, s.Total())
+	for set, params := range s.Grid() {
+		for _, sp := range spans {
+			runs = append(runs, SweepRun{
+				Set:     set,
+				Params:  params,
+				Fold:    sp.fold,
+				Sample:  sp.sample,
+				Start:   sp.start,
+				End:     sp.end,
+				Metric:  0.0,
+				Summary: Summary{}, //nolint:exhaustruct
+			})
+		}
+	}
+	return runs, nil
+}
+
+// RunSweep backtests the runs of sweep not in done, by key, on sweep.Workers concurrent
+// workers over universe. onRun receives each finished run, one at a time; the sweep
+// stops at the first error of a run or of onRun.
+func RunSweep(sweep Sweep, universe *Universe, done map[string]bool, onRun func(SweepRun) error) error {
+	sweep = sweep.WithDefaults()
+	if err := sweep.Validate(); err != nil {
+		return err
+	}
+	runs, err := sweep.Plan(universe)
+	if err != nil {
+		return err
+	}
+	runs = lo.Filter(runs, func(run SweepRun, _ int) bool {
+		return !done[run.Key()]
+	})
+
+	type outcome struct {
+		run SweepRun
+		err error
+	}
+	jobs := make(chan SweepRun)
+	outcomes := make(chan outcome)
+	stop := make(chan struct{})
+
+	go func() {
+		defer close(jobs)
+		for _, run := range runs {
+			select {
+			case jobs <- run:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range min(sweep.Workers, max(len(runs), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range jobs {
+				run, err := runSweepRun(sweep, universe, run)
+				outcomes <- outcome{run: run, err: err}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	var first error
+	for o := range outcomes {
+		if first != nil {
+			continue
+		}
+		if o.err == nil {
+			o.err = onRun(o.run)
+		}
+		if o.err != nil {
+			first = o.err
+			close(stop)
+		}
+	}
+	return first
+}
+
+func runSweepRun(sweep Sweep, universe *Universe, run SweepRun) (SweepRun, error) {
+	config, err := ApplyParams(sweep.Base, run.Params)
+	if err != nil {
+		return run, err
+	}
+	config.Start, config.End = run.Start, run.End
+
+	result, err := RunOn(config, universe)
+	if err != nil {
+		return run, err
+	}
+	run.Summary = result.Summary
+	run.Metric = metricOf(sweep.Metric, ComputeReport(result, nil, DefaultReportParams()))
+	return run, nil
+}
+
+var metrics = []Metric{
+	MetricTotalReturn, MetricCAGR, MetricSharpe, MetricSortino,
+	MetricCalmar, MetricWinRate, MetricAvgReturn, MetricMaxDrawdown,
+}
+
+func metricOf(metric Metric, report Report) float64 {
+	switch metric {
+	case MetricTotalReturn:
+		return report.Summary.TotalReturn
+	case MetricCAGR:
+		return report.CAGR
+	case MetricSharpe:
+		return report.Sharpe
+	case MetricSortino:
+		return report.Sortino
+	case MetricCalmar:
+		return report.Calmar
+	case MetricWinRate:
+		return report.Summary.WinRate
+	case MetricAvgReturn:
+		return report.Summary.AvgReturn
+	case MetricMaxDrawdown:
+		return report.MaxDrawdown
+	default:
+		return 0.0
+	}
+}
+
+// better compares metric values a and b, negative when a is better.
+func better(metric Metric, a, b float64) int {
+	if metric == MetricMaxDrawdown {
+		return cmp.Compare(a, b)
+	}
+	return cmp.Compare(b, a)
+}
+
+// RankSweep aggregates the finished runs of sweep into ranked parameter sets and, with
+// walk-forward, folds. Sets without a run yet are left out.
+func RankSweep(sweep Sweep, runs []SweepRun) SweepResult {
+	sweep = sweep.WithDefaults()
+	result := SweepResult{
+		Name:       sweep.Name,
+		Metric:     sweep.Metric,
+		Status:     sweep.Status,
+		Error:      sweep.Error,
+		Total:      sweep.Total(),
+		Done:       len(runs),
+		Sets:       make([]SweepSet, 0),
+		Folds:      make([]SweepFold, 0),
+		Efficiency: 0.0,
+	}
+
+	bySet := lo.GroupBy(runs, func(run SweepRun) int { return run.Set })
+	for set, grid := range sweep.Grid() {
+		setRuns, ok := bySet[set]
+		if !ok {
+			continue
+		}
+		ss := SweepSet{
+			Set:         set,
+			Rank:        0,
+			Params:      grid,
+			Full:        0.0,
+			InSample:    meanMetric(setRuns, SampleIn),
+			OutOfSample: meanMetric(setRuns, SampleOut),
+			Degradation: 0.0,
+		}
+		if full, ok := lo.Find(setRuns, func(run SweepRun) bool { return run.Sample == SampleFull }); ok {
+			ss.Full = full.Metric
+		}
+		if ss.InSample != 0.0 {
+			ss.Degradation = 100.0 * (ss.InSample - ss.OutOfSample) / math.Abs(ss.InSample)
+			if sweep.Metric == MetricMaxDrawdown {
+				ss.Degradation = -ss.Degradation
+			}
+		}
+		result.Sets = append(result.Sets, ss)
+	}
+
+	slices.SortStableFunc(result.Sets, func(a, b SweepSet) int {
+		if sweep.WalkForward != nil {
+			return better(sweep.Metric, a.OutOfSample, b.OutOfSample)
+		}
+		return better(sweep.Metric, a.Full, b.Full)
+	})
+	for idx := range result.Sets {
+		result.Sets[idx].Rank = idx + 1
+	}
+
+	if sweep.WalkForward == nil {
+		return result
+	}
+
+	byFold := lo.GroupBy(lo.Filter(runs, func(run SweepRun, _ int) bool {
+		return run.Fold >= 0
+	}), func(run SweepRun) int { return run.Fold })
+	for fold := range sweep.WalkForward.Folds {
+		ins := lo.Filter(byFold[fold], func(run SweepRun, _ int) bool { return run.Sample == SampleIn })
+		if len(ins) == 0 {
+			continue
+		}
+		slices.SortStableFunc(ins, func(a, b SweepRun) int {
+			if c := better(sweep.Metric, a.Metric, b.Metric); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.Set, b.Set)
+		})
+		best := ins[0]
+		out, ok := lo.Find(byFold[fold], func(run SweepRun) bool {
+			return run.Sample == SampleOut && run.Set == best.Set
+		})
+		if !ok {
+			continue
+		}
+		result.Folds = append(result.Folds, SweepFold{
+			Fold:        fold,
+			InStart:     best.Start,
+			InEnd:       best.End,
+			OutStart:    out.Start,
+			OutEnd:      out.End,
+			Best:        best.Set,
+			InSample:    best.Metric,
+			OutOfSample: out.Metric,
+		})
+	}
+
+	if len(result.Folds) > 0 {
+		in := lo.SumBy(result.Folds, func(f SweepFold) float64 { return f.InSample })
+		out := lo.SumBy(result.Folds, func(f SweepFold) float64 { return f.OutOfSample })
+		if in != 0.0 {
+			result.Efficiency = 100.0 * out / in
+		}
+	}
+
+	return result
+}
+
+func meanMetric(runs []SweepRun, sample Sample) float64 {
+	runs = lo.Filter(runs, func(run SweepRun, _ int) bool { return run.Sample == sample })
+	if len(runs) == 0 {
+		return 0.0
+	}
+	return lo.SumBy(runs, func(run SweepRun) float64 { return run.Metric }) / float64(len(runs))
+}
@@ -0,0 +1,130 @@
+//nolint:testpackage,lll //ignore
+package backtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sweepOf sweeps the breakout threshold and holding days of a single-slot backtest.
+func sweepOf(wf *WalkForward) Sweep {
+	return Sweep{ //nolint:exhaustruct
+		Name: "breakout",
+		Base: Config{Rule: breakoutRule(), Exit: Exit{HoldDays: 2}, Capital: 1000, MaxPositions: 1, Market: genericMarket()}, //nolint:exhaustruct
+		Params: []Param{
+			{Path: "rule.value", Values: []float64{11, 100}},
+			{Path: "exit.holddays", Values: []float64{2, 3}},
+		},
+		Metric:      MetricTotalReturn,
+		Workers:     3,
+		WalkForward: wf,
+	}
+}
+
+// trendRows returns n rows rising by 0.1 from 11.
+func trendRows(n int) [][4]float64 {
+	rows := make([][4]float64, n)
+	for idx := range rows {
+		o := 11.0 + 0.1*float64(idx)
+		rows[idx] = [4]float64{o, o + 0.3, o - 0.1, o + 0.1}
+	}
+	return rows
+}
+
+func TestApplyParams(t *testing.T) {
+	config := Config{Rule: breakoutRule(), Exit: Exit{}} //nolint:exhaustruct
+
+	got, err := ApplyParams(config, map[string]float64{"rule.value": 12.5, "exit.holddays": 4})
+	assert.NoError(t, err)
+	assert.InDelta(t, 12.5, got.Rule.Value, 1e-9)
+	assert.Equal(t, 4, got.Exit.HoldDays)
+	// The config passed in is left as is.
+	assert.InDelta(t, 11.0, config.Rule.Value, 1e-9)
+
+	_, err = ApplyParams(config, map[string]float64{"rule.rules.0.value": 1})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	_, err = ApplyParams(config, map[string]float64{"exit.holddays": 1.5})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestSweepGrid(t *testing.T) {
+	sweep := sweepOf(nil)
+	assert.Equal(t, []map[string]float64{
+		{"rule.value": 11, "exit.holddays": 2},
+		{"rule.value": 11, "exit.holddays": 3},
+		{"rule.value": 100, "exit.holddays": 2},
+		{"rule.value": 100, "exit.holddays": 3},
+	}, sweep.Grid())
+	assert.Equal(t, 4, sweep.Total())
+	assert.Equal(t, 4*(1+2*2), sweepOf(&WalkForward{Folds: 2, InSample: 2}).Total())
+
+	sweep.Metric = "profit"
+	assert.ErrorIs(t, sweep.Validate(), ErrInvalidConfig)
+}
+
+func TestSweepSameSpec(t *testing.T) {
+	sweep := sweepOf(nil)
+	other := sweepOf(nil)
+	other.Workers, other.Status = 1, SweepDone
+	assert.True(t, sweep.SameSpec(other))
+
+	other.Params[1].Values = []float64{2, 4}
+	assert.False(t, sweep.SameSpec(other))
+	assert.False(t, sweep.SameSpec(sweepOf(&WalkForward{Folds: 2, InSample: 2})))
+}
+
+func TestRunSweep(t *testing.T) {
+	universe := NewUniverse([]History{historyFrom("a", trendRows(40))})
+	sweep := sweepOf(&WalkForward{Folds: 2, InSample: 2})
+
+	runs := make([]SweepRun, 0)
+	err := RunSweep(sweep, universe, nil, func(run SweepRun) error {
+		runs = append(runs, run)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, runs, sweep.Total())
+
+	result := RankSweep(sweep, runs)
+	assert.Equal(t, sweep.Total(), result.Done)
+	if assert.Len(t, result.Sets, 4) {
+		// Entering on the uptrend beats never entering.
+		assert.InDelta(t, 11.0, result.Sets[0].Params["rule.value"], 1e-9)
+		assert.Equal(t, 1, result.Sets[0].Rank)
+		assert.Greater(t, result.Sets[0].OutOfSample, 0.0)
+		assert.InDelta(t, 100.0, result.Sets[3].Params["rule.value"], 1e-9)
+		assert.InDelta(t, 0.0, result.Sets[3].Full, 1e-9)
+	}
+	if assert.Len(t, result.Folds, 2) {
+		for _, fold := range result.Folds {
+			assert.Less(t, fold.InEnd, fold.OutStart)
+			assert.InDelta(t, 11.0, sweep.Grid()[fold.Best]["rule.value"], 1e-9)
+		}
+	}
+}
+
+func TestRunSweepResume(t *testing.T) {
+	universe := NewUniverse([]History{historyFrom("a", trendRows(40))})
+	sweep := sweepOf(nil)
+
+	done := map[string]bool{
+		SweepRun{Set: 0, Fold: -1, Sample: SampleFull}.Key(): true, //nolint:exhaustruct
+		SweepRun{Set: 2, Fold: -1, Sample: SampleFull}.Key(): true, //nolint:exhaustruct
+	}
+	sets := make([]int, 0)
+	err := RunSweep(sweep, universe, done, func(run SweepRun) error {
+		sets = append(sets, run.Set)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{1, 3}, sets)
+}
+
+func TestRunSweepTooFewDates(t *testing.T) {
+	universe := NewUniverse([]History{historyFrom("a", trendRows(5))})
+	sweep := sweepOf(&WalkForward{Folds: 30, InSample: 10})
+
+	err := RunSweep(sweep, universe, nil, func(SweepRun) error { return nil })
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
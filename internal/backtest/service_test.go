@@ -0,0 +1,182 @@
+//nolint:testpackage,lll //ignore
+package backtest
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/market"
+	"example.com/stocker-back/internal/screener"
+	"example.com/stocker-back/internal/stock"
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// warmup is the number of flat candles before the scenario, enough for a settled KDJ.
+const warmup = 20
+
+// historyFrom builds the history of ticker from warm-up candles around 10 followed by
+// {open, high, low, close} rows.
+func historyFrom(ticker string, rows [][4]float64) History {
+	s := stock.NewEmptyStock()
+	s.Ticker = ticker
+
+	all := make([][4]float64, 0, warmup+len(rows))
+	for range warmup {
+		all = append(all, [4]float64{10, 10.3, 9.8, 10.1})
+	}
+	all = append(all, rows...)
+
+	daily := make([]stock.DailyData, len(all))
+	for idx, row := range all {
+		daily[idx] = stock.NewEmptyDailyData()
+		daily[idx].Ticker = ticker
+		daily[idx].Date = stocktest.Date(idx)
+		daily[idx].Open, daily[idx].High, daily[idx].Low, daily[idx].Close = row[0], row[1], row[2], row[3]
+	}
+
+	return History{Stock: s, Daily: daily}
+}
+
+// breakoutRule enters when close is above 11.
+func breakoutRule() screener.Rule {
+	return screener.Rule{Op: screener.OpGt, Field: "close", Value: 11} //nolint:exhaustruct
+}
+
+// genericMarket turns every market rule off.
+func genericMarket() *market.Market {
+	return &market.Market{} //nolint:exhaustruct
+}
+
+func TestRunExits(t *testing.T) {
+	tests := []struct {
+		name   string
+		exit   Exit
+		rows   [][4]float64
+		reason ExitReason
+		price  float64
+		hold   int
+	}{
+		{"hold", Exit{HoldDays: 3}, [][4]float64{{11, 12, 11, 12}, {12, 12.5, 11.8, 12}, {12, 12.5, 11.8, 12.2}, {12.2, 12.5, 12, 12.4}}, ExitHold, 12.4, 3},
+		{"stoploss", Exit{StopLoss: 10}, [][4]float64{{11, 12, 11, 12}, {12, 12.5, 11.8, 12}, {11.5, 11.6, 10.5, 10.6}}, ExitStopLoss, 10.8, 2},
+		{"stoploss gap", Exit{StopLoss: 10}, [][4]float64{{11, 12, 11, 12}, {12, 12.5, 11.8, 12}, {10, 10.2, 9.8, 10}}, ExitStopLoss, 10, 2},
+		{"takeprofit", Exit{TakeProfit: 5}, [][4]float64{{11, 12, 11, 12}, {12, 12.5, 11.8, 12}, {12.3, 12.9, 12.2, 12.8}}, ExitTakeProfit, 12.6, 2},
+		{"rule", Exit{Rule: &screener.Rule{Op: screener.OpLt, Field: "close", Value: 11.5}}, [][4]float64{{11, 12, 11, 12}, {12, 12.5, 11.8, 12}, {12, 12, 11, 11.2}, {11.1, 11.5, 11, 11.3}}, ExitRule, 11.1, 3}, //nolint:exhaustruct
+		{"end", Exit{}, [][4]float64{{11, 12, 11, 12}, {12, 12.5, 11.8, 12}, {12, 12.5, 11.8, 12.3}}, ExitEnd, 12.3, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Rule: breakoutRule(), Exit: tt.exit, Capital: 1000, MaxPositions: 1, Market: genericMarket()} //nolint:exhaustruct
+			result, err := Run(config, []History{historyFrom("a", tt.rows)})
+			assert.NoError(t, err)
+			if !assert.Len(t, result.Trades, 1) {
+				return
+			}
+
+			trade := result.Trades[0]
+			// Signalled at the close of the first row, entered at the next open.
+			assert.Equal(t, stocktest.Date(warmup+1), trade.EntryDate)
+			assert.InDelta(t, 12.0, trade.EntryPrice, 1e-9)
+			assert.Equal(t, tt.reason, trade.Reason)
+			assert.InDelta(t, tt.price, trade.ExitPrice, 1e-9)
+			assert.Equal(t, tt.hold, trade.HoldDays)
+			assert.InDelta(t, 100.0*(tt.price-12.0)/12.0, trade.Return, 1e-9)
+			assert.InDelta(t, 100.0*(tt.price-12.0)/12.0, result.Summary.TotalReturn, 1e-9)
+		})
+	}
+}
+
+func TestRunAShare(t *testing.T) {
+	config := Config{Rule: breakoutRule(), Exit: Exit{StopLoss: 5}, Capital: 100000, MaxPositions: 1} //nolint:exhaustruct
+
+	// Stop touched on the entry day is not sold under T+1; whole lots with fees.
+	result, err := Run(config, []History{historyFrom("1.600000", [][4]float64{{11, 12, 11, 12}, {12, 12.2, 11, 12}, {12, 12.1, 11.9, 12}})})
+	assert.NoError(t, err)
+	if assert.Len(t, result.Trades, 1) {
+		trade := result.Trades[0]
+		assert.Equal(t, ExitEnd, trade.Reason)
+		assert.Equal(t, 2, trade.HoldDays)
+		assert.InDelta(t, 8300.0, trade.Shares, 1e-9)
+		assert.InDelta(t, 24.9+0.996+24.9+0.996+49.8, trade.Fees, 1e-9)
+		assert.InDelta(t, -trade.Fees, trade.Profit, 1e-9)
+	}
+
+	// Stop at limit-down is retried the next day.
+	result, err = Run(config, []History{historyFrom("1.600000", [][4]float64{{11, 12, 11, 12}, {12, 12.2, 11.9, 12}, {10.8, 10.8, 10.8, 10.8}, {10.5, 11, 10.4, 10.9}})})
+	assert.NoError(t, err)
+	if assert.Len(t, result.Trades, 1) {
+		assert.Equal(t, ExitStopLoss, result.Trades[0].Reason)
+		assert.Equal(t, stocktest.Date(warmup+3), result.Trades[0].ExitDate)
+		assert.InDelta(t, 10.5, result.Trades[0].ExitPrice, 1e-9)
+	}
+
+	// An exit rule blocked at limit-down is dropped once the rule stops matching.
+	config.Exit = Exit{Rule: &screener.Rule{Op: screener.OpLt, Field: "close", Value: 11.5}} //nolint:exhaustruct
+	result, err = Run(config, []History{historyFrom("1.600000", [][4]float64{{11, 12, 11, 12}, {12, 12.2, 11.3, 11.4}, {10.26, 11.7, 10.26, 11.6}, {11.6, 11.8, 11.5, 11.7}})})
+	assert.NoError(t, err)
+	if assert.Len(t, result.Trades, 1) {
+		assert.Equal(t, ExitEnd, result.Trades[0].Reason)
+		assert.InDelta(t, 11.7, result.Trades[0].ExitPrice, 1e-9)
+	}
+	config.Exit = Exit{StopLoss: 5} //nolint:exhaustruct
+
+	// No buy at limit-up; entered on the next signal.
+	result, err = Run(config, []History{historyFrom("1.600000", [][4]float64{{11, 12, 11, 12}, {13.2, 13.2, 13.2, 13.2}, {13, 13.5, 12.8, 13}})})
+	assert.NoError(t, err)
+	if assert.Len(t, result.Trades, 1) {
+		assert.Equal(t, stocktest.Date(warmup+2), result.Trades[0].EntryDate)
+		assert.InDelta(t, 13.0, result.Trades[0].EntryPrice, 1e-9)
+	}
+}
+
+func TestRunSlots(t *testing.T) {
+	rows := [][4]float64{{11, 12, 11, 12}, {12, 12, 12, 12}, {12, 12, 12, 12}}
+	histories := []History{historyFrom("a", rows), historyFrom("b", rows), historyFrom("c", rows)}
+
+	config := Config{Rule: breakoutRule(), Exit: Exit{HoldDays: 1}, Capital: 1000, MaxPositions: 2, Market: genericMarket()} //nolint:exhaustruct
+	result, err := Run(config, histories)
+	assert.NoError(t, err)
+
+	// Two slots filled on each signal, freed by the one-day hold in between.
+	assert.Equal(t, 4, result.Summary.Trades)
+	assert.Equal(t, stocktest.Date(warmup+1), result.Trades[0].EntryDate)
+	assert.Equal(t, stocktest.Date(warmup+1), result.Trades[1].EntryDate)
+	assert.Equal(t, stocktest.Date(warmup+2), result.Trades[2].EntryDate)
+	assert.InDelta(t, 500.0/12.0, result.Trades[0].Shares, 1e-9)
+
+	config.Tickers = []string{"c"}
+	result, err = Run(config, histories)
+	assert.NoError(t, err)
+	for _, trade := range result.Trades {
+		assert.Equal(t, "c", trade.Ticker)
+	}
+}
+
+func TestRunInvalidConfig(t *testing.T) {
+	_, err := Run(Config{Rule: screener.Rule{Op: "xor"}}, nil) //nolint:exhaustruct
+	assert.ErrorIs(t, err, screener.ErrInvalidRule)
+
+	_, err = Run(Config{Rule: breakoutRule(), Exit: Exit{HoldDays: -1}}, nil) //nolint:exhaustruct
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	_, err = Run(Config{Rule: breakoutRule(), Start: "2024-02-01", End: "2024-01-01"}, nil) //nolint:exhaustruct
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	// Sector aggregates are not replayed.
+	_, err = Run(Config{Rule: screener.Rule{Op: screener.OpGt, Field: "relret20", Value: 5}}, nil) //nolint:exhaustruct
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestSummarise(t *testing.T) {
+	trades := []Trade{{Return: 10, HoldDays: 2}, {Return: -4, HoldDays: 4}} //nolint:exhaustruct
+	equity := []EquityPoint{{Date: "1", Equity: 1100}, {Date: "2", Equity: 880}, {Date: "3", Equity: 1050}}
+
+	summary := Summarise(1000, trades, equity)
+	assert.Equal(t, 2, summary.Trades)
+	assert.Equal(t, 1, summary.Wins)
+	assert.InDelta(t, 50.0, summary.WinRate, 1e-9)
+	assert.InDelta(t, 3.0, summary.AvgReturn, 1e-9)
+	assert.InDelta(t, 3.0, summary.AvgHoldDays, 1e-9)
+	assert.InDelta(t, 5.0, summary.TotalReturn, 1e-9)
+	assert.InDelta(t, 20.0, summary.MaxDrawdown, 1e-9)
+}
@@ -0,0 +1,9 @@
+package common
+
+// Day trims a pocketbase date to its day, e.g. "2006-01-02".
+func Day(date string) string {
+	if len(date) > len("2006-01-02") {
+		return date[:len("2006-01-02")]
+	}
+	return date
+}
@@ -0,0 +1,182 @@
+package infra
+
+import (
+	"slices"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+// RecordIndicators is type of PB database collection `indicators` schema.
+type RecordIndicators struct {
+	Ticker  string `db:"ticker" json:"ticker"`
+	Date    string `db:"date" json:"date"`
+	Candles int    `db:"candles" json:"candles"`
+
+	Sma5   float64 `db:"sma5" json:"sma5"`
+	Sma10  float64 `db:"sma10" json:"sma10"`
+	Sma20  float64 `db:"sma20" json:"sma20"`
+	Sma30  float64 `db:"sma30" json:"sma30"`
+	Sma90  float64 `db:"sma90" json:"sma90"`
+	Sma120 float64 `db:"sma120" json:"sma120"`
+
+	Ema12 float64 `db:"ema12" json:"ema12"`
+	Ema26 float64 `db:"ema26" json:"ema26"`
+	Diff  float64 `db:"diff" json:"diff"`
+	Dea   float64 `db:"dea" json:"dea"`
+	Hist  float64 `db:"hist" json:"hist"`
+
+	Rsi    float64 `db:"rsi" json:"rsi"`
+	RsGain float64 `db:"rsgain" json:"rsgain"`
+	RsLoss float64 `db:"rsloss" json:"rsloss"`
+
+	Rsv float64 `db:"rsv" json:"rsv"`
+	K   float64 `db:"k" json:"k"`
+	D   float64 `db:"d" json:"d"`
+	J   float64 `db:"j" json:"j"`
+}
+
+func (r RecordIndicators) ToModel() stock.Indicators {
+	return stock.Indicators{
+		Ticker:  r.Ticker,
+		Date:    r.Date,
+		Candles: r.Candles,
+		SMA: stock.SMA{
+			Sma5:   r.Sma5,
+			Sma10:  r.Sma10,
+			Sma20:  r.Sma20,
+			Sma30:  r.Sma30,
+			Sma90:  r.Sma90,
+			Sma120: r.Sma120,
+		},
+		MACD: stock.MACD{
+			Ema12: r.Ema12,
+			Ema26: r.Ema26,
+			Diff:  r.Diff,
+			Dea:   r.Dea,
+			Hist:  r.Hist,
+		},
+		RSI: stock.RSI{
+			Rsi:    r.Rsi,
+			RsGain: r.RsGain,
+			RsLoss: r.RsLoss,
+		},
+		KDJ: stock.KDJ{
+			Rsv: r.Rsv,
+			K:   r.K,
+			D:   r.D,
+			J:   r.J,
+		},
+	}
+}
+
+// GetDailyDataByTicker gets the last `limit` daily data of ticker in ascending date, all of them if limit is 0.
+func (repo *StockRepositoryPB) GetDailyDataByTicker(ticker string, limit int) ([]stock.DailyData, error) {
+	var records []RecordDailyData
+
+	query := repo.pb.Dao().DB().
+		Select().
+		From("daily").
+		Where(dbx.NewExp("ticker = {:ticker}", dbx.Params{"ticker": ticker})).
+		OrderBy("date DESC")
+	if limit > 0 {
+		query = query.Limit(int64(limit))
+	}
+	if err := query.All(&records); err != nil {
+		return nil, err
+	}
+
+	output := make([]stock.DailyData, 0, len(records))
+	for _, r := range records {
+		output = append(output, r.ToModel())
+	}
+	slices.Reverse(output)
+
+	return output, nil
+}
+
+// GetDailyDataByTickerSince gets daily data of ticker dated from the day `since` on, in ascending date.
+func (repo *StockRepositoryPB) GetDailyDataByTickerSince(ticker string, since string) ([]stock.DailyData, error) {
+	var records []RecordDailyData
+
+	err := repo.pb.Dao().DB().
+		Select().
+		From("daily").
+		Where(dbx.NewExp("ticker = {:ticker} AND date >= {:since}", dbx.Params{"ticker": ticker, "since": since})).
+		OrderBy("date ASC").
+		All(&records)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]stock.DailyData, 0, len(records))
+	for _, r := range records {
+		output = append(output, r.ToModel())
+	}
+
+	return output, nil
+}
+
+// GetIndicatorsLastAll gets the latest indicators state of every ticker.
+func (repo *StockRepositoryPB) GetIndicatorsLastAll() ([]stock.Indicators, error) {
+	var records []RecordIndicators
+
+	err := repo.pb.Dao().DB().
+		NewQuery(`
+			SELECT i.* FROM indicators i
+			WHERE i.date = (SELECT MAX(date) FROM indicators WHERE ticker = i.ticker)
+		`).
+		All(&records)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]stock.Indicators, 0, len(records))
+	for _, r := range records {
+		output = append(output, r.ToModel())
+	}
+
+	return output, nil
+}
+
+func (repo *StockRepositoryPB) CreateIndicators(indicators []stock.Indicators) error {
+	collection, err := repo.pb.Dao().FindCollectionByNameOrId("indicators")
+	if err != nil {
+		return err
+	}
+
+	err = repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		for _, data := range indicators {
+			recordData, err := data.ToMap()
+			if err != nil {
+				return err
+			}
+			record := models.NewRecord(collection)
+			record.Load(recordData)
+
+			err = txDao.SaveRecord(record)
+			if err != nil {
+				repo.pb.Logger().Error("cannot write to `indicators`", "error", err.Error(), "ticker", data.Ticker)
+				continue
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteIndicatorsAll clears the `indicators` collection before a full rebuild.
+func (repo *StockRepositoryPB) DeleteIndicatorsAll() error {
+	if _, err := repo.pb.Dao().DB().NewQuery("DELETE FROM indicators").Execute(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package infra
+
+import (
+	"cmp"
+	"slices"
+
+	"example.com/stocker-back/internal/journal"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+type JournalRepositoryPB struct {
+	pb *pocketbase.PocketBase
+}
+
+func NewJournalRepositoryPB(pb *pocketbase.PocketBase) *JournalRepositoryPB {
+	return &JournalRepositoryPB{
+		pb: pb,
+	}
+}
+
+type RecordTrade struct {
+	Ticker string  `db:"ticker" json:"ticker"`
+	Date   string  `db:"date" json:"date"`
+	Kind   string  `db:"kind" json:"kind"`
+	Shares float64 `db:"shares" json:"shares"`
+	Price  float64 `db:"price" json:"price"`
+	Fees   float64 `db:"fees" json:"fees"`
+	Amount float64 `db:"amount" json:"amount"`
+	Ref    string  `db:"ref" json:"ref"`
+	Note   string  `db:"note" json:"note"`
+}
+
+func (r RecordTrade) ToMap() map[string]any {
+	return map[string]any{
+		"ticker": r.Ticker,
+		"date":   r.Date,
+		"kind":   r.Kind,
+		"shares": r.Shares,
+		"price":  r.Price,
+		"fees":   r.Fees,
+		"amount": r.Amount,
+		"ref":    r.Ref,
+		"note":   r.Note,
+	}
+}
+
+// convertTradeToRecord is DTO from Trade to PB Record.
+func convertTradeToRecord(trade journal.Trade) RecordTrade {
+	return RecordTrade{
+		Ticker: trade.Ticker,
+		Date:   trade.Date,
+		Kind:   string(trade.Kind),
+		Shares: trade.Shares,
+		Price:  trade.Price,
+		Fees:   trade.Fees,
+		Amount: trade.Amount,
+		Ref:    trade.Ref,
+		Note:   trade.Note,
+	}
+}
+
+func recordToTrade(record *models.Record) journal.Trade {
+	return journal.Trade{
+		ID:     record.Id,
+		Ticker: record.GetString("ticker"),
+		Date:   record.GetString("date"),
+		Kind:   journal.Kind(record.GetString("kind")),
+		Shares: record.GetFloat("shares"),
+		Price:  record.GetFloat("price"),
+		Fees:   record.GetFloat("fees"),
+		Amount: record.GetFloat("amount"),
+		Ref:    record.GetString("ref"),
+		Note:   record.GetString("note"),
+	}
+}
+
+func (repo *JournalRepositoryPB) findTrades(exprs ...dbx.Expression) ([]journal.Trade, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr("journal", exprs...)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]journal.Trade, 0, len(records))
+	for _, record := range records {
+		trades = append(trades, recordToTrade(record))
+	}
+
+	// Trades of the same date keep the order recorded.
+	slices.SortStableFunc(trades, func(a, b journal.Trade) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+
+	return trades, nil
+}
+
+// GetTrades gets all trades in date order.
+func (repo *JournalRepositoryPB) GetTrades() ([]journal.Trade, error) {
+	return repo.findTrades()
+}
+
+// GetTradesByTicker gets trades of ticker in date order.
+func (repo *JournalRepositoryPB) GetTradesByTicker(ticker string) ([]journal.Trade, error) {
+	return repo.findTrades(dbx.HashExp{"ticker": ticker})
+}
+
+// CreateTrades saves new trades at once and returns them with their IDs.
+func (repo *JournalRepositoryPB) CreateTrades(trades []journal.Trade) ([]journal.Trade, error) {
+	collection, err := repo.pb.Dao().FindCollectionByNameOrId("journal")
+	if err != nil {
+		return nil, err
+	}
+
+	created := slices.Clone(trades)
+	err = repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		for idx := range created {
+			record := models.NewRecord(collection)
+			record.Load(convertTradeToRecord(created[idx]).ToMap())
+			if err := txDao.SaveRecord(record); err != nil {
+				return err
+			}
+			created[idx].ID = record.Id
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (repo *JournalRepositoryPB) DeleteTradeByID(id string) error {
+	record, err := repo.pb.Dao().FindRecordById("journal", id)
+	if err != nil {
+		return err
+	}
+
+	return repo.pb.Dao().DeleteRecord(record)
+}
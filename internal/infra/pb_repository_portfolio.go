@@ -0,0 +1,369 @@
+package infra
+
+import (
+	"cmp"
+	"slices"
+
+	"example.com/stocker-back/internal/portfolio"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+type PortfolioRepositoryPB struct {
+	pb *pocketbase.PocketBase
+}
+
+func NewPortfolioRepositoryPB(pb *pocketbase.PocketBase) *PortfolioRepositoryPB {
+	return &PortfolioRepositoryPB{
+		pb: pb,
+	}
+}
+
+func recordToAccount(record *models.Record) (portfolio.Account, error) {
+	account := portfolio.Account{
+		Name:    record.GetString("name"),
+		Capital: record.GetFloat("capital"),
+		Cash:    record.GetFloat("cash"),
+		Market:  nil,
+		Screen:  nil,
+		Slots:   record.GetInt("slots"),
+		Created: record.GetCreated().String(),
+	}
+	if err := record.UnmarshalJSONField("market", &account.Market); err != nil {
+		return portfolio.Account{}, err
+	}
+	if err := record.UnmarshalJSONField("screen", &account.Screen); err != nil {
+		return portfolio.Account{}, err
+	}
+	return account, nil
+}
+
+func accountToMap(account portfolio.Account) map[string]any {
+	return map[string]any{
+		"name":    account.Name,
+		"capital": account.Capital,
+		"cash":    account.Cash,
+		"market":  account.Market,
+		"screen":  account.Screen,
+		"slots":   account.Slots,
+	}
+}
+
+func (repo *PortfolioRepositoryPB) GetAccounts() ([]portfolio.Account, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr("paperaccount")
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]portfolio.Account, 0, len(records))
+	for _, record := range records {
+		account, err := recordToAccount(record)
+		if err != nil {
+			repo.pb.Logger().Error("cannot read `paperaccount`", "error", err.Error(), "name", record.GetString("name"))
+			continue
+		}
+		accounts = append(accounts, account)
+	}
+
+	slices.SortFunc(accounts, func(a, b portfolio.Account) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return accounts, nil
+}
+
+func (repo *PortfolioRepositoryPB) GetAccountByName(name string) (portfolio.Account, error) {
+	record, err := repo.pb.Dao().FindFirstRecordByData("paperaccount", "name", name)
+	if err != nil {
+		return portfolio.Account{}, err
+	}
+
+	return recordToAccount(record)
+}
+
+func (repo *PortfolioRepositoryPB) CreateAccount(account portfolio.Account) error {
+	collection, err := repo.pb.Dao().FindCollectionByNameOrId("paperaccount")
+	if err != nil {
+		return err
+	}
+
+	record := models.NewRecord(collection)
+	record.Load(accountToMap(account))
+
+	return repo.pb.Dao().SaveRecord(record)
+}
+
+// DeleteAccountByName deletes the account along with its orders and positions.
+func (repo *PortfolioRepositoryPB) DeleteAccountByName(name string) error {
+	record, err := repo.pb.Dao().FindFirstRecordByData("paperaccount", "name", name)
+	if err != nil {
+		return err
+	}
+
+	return repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		for _, table := range []string{"paperorder", "paperposition"} {
+			if _, err := txDao.DB().Delete(table, dbx.HashExp{"account": name}).Execute(); err != nil {
+				return err
+			}
+		}
+		return txDao.DeleteRecord(record)
+	})
+}
+
+type RecordPosition struct {
+	Account  string  `db:"account" json:"account"`
+	Ticker   string  `db:"ticker" json:"ticker"`
+	Shares   float64 `db:"shares" json:"shares"`
+	AvgCost  float64 `db:"avgcost" json:"avgcost"`
+	OpenedAt string  `db:"openedat" json:"openedat"`
+	LastBuy  string  `db:"lastbuy" json:"lastbuy"`
+	Realized float64 `db:"realized" json:"realized"`
+}
+
+func (r RecordPosition) ToMap() map[string]any {
+	return map[string]any{
+		"account":  r.Account,
+		"ticker":   r.Ticker,
+		"shares":   r.Shares,
+		"avgcost":  r.AvgCost,
+		"openedat": r.OpenedAt,
+		"lastbuy":  r.LastBuy,
+		"realized": r.Realized,
+	}
+}
+
+func (r RecordPosition) ToModel() portfolio.Position {
+	return portfolio.Position{
+		Account:  r.Account,
+		Ticker:   r.Ticker,
+		Shares:   r.Shares,
+		AvgCost:  r.AvgCost,
+		OpenedAt: r.OpenedAt,
+		LastBuy:  r.LastBuy,
+		Realized: r.Realized,
+	}
+}
+
+func (repo *PortfolioRepositoryPB) GetPositions(account string) ([]portfolio.Position, error) {
+	var records []RecordPosition
+
+	err := repo.pb.Dao().DB().
+		Select("account", "ticker", "shares", "avgcost", "openedat", "lastbuy", "realized").
+		From("paperposition").
+		Where(dbx.HashExp{"account": account}).
+		OrderBy("ticker ASC").
+		All(&records)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]portfolio.Position, 0, len(records))
+	for _, r := range records {
+		positions = append(positions, r.ToModel())
+	}
+
+	return positions, nil
+}
+
+type RecordOrder struct {
+	Account  string  `db:"account" json:"account"`
+	Ticker   string  `db:"ticker" json:"ticker"`
+	Side     string  `db:"side" json:"side"`
+	Shares   float64 `db:"shares" json:"shares"`
+	Amount   float64 `db:"amount" json:"amount"`
+	Source   string  `db:"source" json:"source"`
+	Status   string  `db:"status" json:"status"`
+	Reason   string  `db:"reason" json:"reason"`
+	PlacedAt string  `db:"placedat" json:"placedat"`
+	FilledAt string  `db:"filledat" json:"filledat"`
+	Price    float64 `db:"price" json:"price"`
+	Fees     float64 `db:"fees" json:"fees"`
+	Realized float64 `db:"realized" json:"realized"`
+}
+
+func (r RecordOrder) ToMap() map[string]any {
+	return map[string]any{
+		"account":  r.Account,
+		"ticker":   r.Ticker,
+		"side":     r.Side,
+		"shares":   r.Shares,
+		"amount":   r.Amount,
+		"source":   r.Source,
+		"status":   r.Status,
+		"reason":   r.Reason,
+		"placedat": r.PlacedAt,
+		"filledat": r.FilledAt,
+		"price":    r.Price,
+		"fees":     r.Fees,
+		"realized": r.Realized,
+	}
+}
+
+// convertOrderToRecord is DTO from Order to PB Record.
+func convertOrderToRecord(order portfolio.Order) RecordOrder {
+	return RecordOrder{
+		Account:  order.Account,
+		Ticker:   order.Ticker,
+		Side:     string(order.Side),
+		Shares:   order.Shares,
+		Amount:   order.Amount,
+		Source:   string(order.Source),
+		Status:   string(order.Status),
+		Reason:   order.Reason,
+		PlacedAt: order.PlacedAt,
+		FilledAt: order.FilledAt,
+		Price:    order.Price,
+		Fees:     order.Fees,
+		Realized: order.Realized,
+	}
+}
+
+func recordToOrder(record *models.Record) portfolio.Order {
+	return portfolio.Order{
+		ID:       record.Id,
+		Account:  record.GetString("account"),
+		Ticker:   record.GetString("ticker"),
+		Side:     portfolio.Side(record.GetString("side")),
+		Shares:   record.GetFloat("shares"),
+		Amount:   record.GetFloat("amount"),
+		Source:   portfolio.Source(record.GetString("source")),
+		Status:   portfolio.OrderStatus(record.GetString("status")),
+		Reason:   record.GetString("reason"),
+		PlacedAt: record.GetString("placedat"),
+		FilledAt: record.GetString("filledat"),
+		Price:    record.GetFloat("price"),
+		Fees:     record.GetFloat("fees"),
+		Realized: record.GetFloat("realized"),
+	}
+}
+
+func (repo *PortfolioRepositoryPB) findOrders(where dbx.Expression) ([]portfolio.Order, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr("paperorder", where)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]portfolio.Order, 0, len(records))
+	for _, record := range records {
+		orders = append(orders, recordToOrder(record))
+	}
+
+	slices.SortStableFunc(orders, func(a, b portfolio.Order) int {
+		return cmp.Compare(b.PlacedAt, a.PlacedAt)
+	})
+
+	return orders, nil
+}
+
+// GetOrders gets orders of account, latest placed first.
+func (repo *PortfolioRepositoryPB) GetOrders(account string) ([]portfolio.Order, error) {
+	return repo.findOrders(dbx.HashExp{"account": account})
+}
+
+func (repo *PortfolioRepositoryPB) GetOrdersPending() ([]portfolio.Order, error) {
+	orders, err := repo.findOrders(dbx.HashExp{"status": string(portfolio.OrderPending)})
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill in the order placed.
+	slices.Reverse(orders)
+	return orders, nil
+}
+
+func (repo *PortfolioRepositoryPB) GetOrderByID(id string) (portfolio.Order, error) {
+	record, err := repo.pb.Dao().FindRecordById("paperorder", id)
+	if err != nil {
+		return portfolio.Order{}, err
+	}
+
+	return recordToOrder(record), nil
+}
+
+// CreateOrder saves a new order and returns it with its ID.
+func (repo *PortfolioRepositoryPB) CreateOrder(order portfolio.Order) (portfolio.Order, error) {
+	collection, err := repo.pb.Dao().FindCollectionByNameOrId("paperorder")
+	if err != nil {
+		return portfolio.Order{}, err
+	}
+
+	record := models.NewRecord(collection)
+	record.Load(convertOrderToRecord(order).ToMap())
+	if err := repo.pb.Dao().SaveRecord(record); err != nil {
+		return portfolio.Order{}, err
+	}
+
+	order.ID = record.Id
+	return order, nil
+}
+
+func (repo *PortfolioRepositoryPB) UpdateOrder(order portfolio.Order) error {
+	return repo.updateOrder(repo.pb.Dao(), order)
+}
+
+func (repo *PortfolioRepositoryPB) updateOrder(dao *daos.Dao, order portfolio.Order) error {
+	record, err := dao.FindRecordById("paperorder", order.ID)
+	if err != nil {
+		return err
+	}
+
+	record.Load(convertOrderToRecord(order).ToMap())
+	return dao.SaveRecord(record)
+}
+
+// SaveFill saves a filled order with the account and position it changed at once;
+// a position without shares is deleted.
+func (repo *PortfolioRepositoryPB) SaveFill(account portfolio.Account, position portfolio.Position, order portfolio.Order) error { //nolint:lll
+	return repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		recordAccount, err := txDao.FindFirstRecordByData("paperaccount", "name", account.Name)
+		if err != nil {
+			return err
+		}
+		recordAccount.Set("cash", account.Cash)
+		if err := txDao.SaveRecord(recordAccount); err != nil {
+			return err
+		}
+
+		recordsPosition, err := txDao.FindRecordsByExpr("paperposition", dbx.HashExp{
+			"account": account.Name,
+			"ticker":  position.Ticker,
+		})
+		if err != nil {
+			return err
+		}
+		var recordPosition *models.Record
+		if len(recordsPosition) > 0 {
+			recordPosition = recordsPosition[0]
+		}
+		switch {
+		case position.Shares <= 0.0 && recordPosition != nil:
+			if err := txDao.DeleteRecord(recordPosition); err != nil {
+				return err
+			}
+		case position.Shares > 0.0:
+			if recordPosition == nil {
+				collection, err := txDao.FindCollectionByNameOrId("paperposition")
+				if err != nil {
+					return err
+				}
+				recordPosition = models.NewRecord(collection)
+			}
+			recordPosition.Load(RecordPosition{
+				Account:  account.Name,
+				Ticker:   position.Ticker,
+				Shares:   position.Shares,
+				AvgCost:  position.AvgCost,
+				OpenedAt: position.OpenedAt,
+				LastBuy:  position.LastBuy,
+				Realized: position.Realized,
+			}.ToMap())
+			if err := txDao.SaveRecord(recordPosition); err != nil {
+				return err
+			}
+		}
+
+		return repo.updateOrder(txDao, order)
+	})
+}
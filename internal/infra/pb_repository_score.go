@@ -0,0 +1,118 @@
+package infra
+
+import (
+	"cmp"
+	"slices"
+
+	"example.com/stocker-back/internal/scoring"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+type ScoreRepositoryPB struct {
+	pb *pocketbase.PocketBase
+}
+
+func NewScoreRepositoryPB(pb *pocketbase.PocketBase) *ScoreRepositoryPB {
+	return &ScoreRepositoryPB{
+		pb: pb,
+	}
+}
+
+type RecordScore struct {
+	Ticker    string             `db:"ticker" json:"ticker"`
+	Date      string             `db:"date" json:"date"`
+	Sector    string             `db:"sector" json:"sector"`
+	Score     float64            `db:"score" json:"score"`
+	Rank      int                `db:"rank" json:"rank"`
+	Breakdown map[string]float64 `db:"breakdown" json:"breakdown"`
+}
+
+func (r RecordScore) ToMap() map[string]any {
+	return map[string]any{
+		"ticker":    r.Ticker,
+		"date":      r.Date,
+		"sector":    r.Sector,
+		"score":     r.Score,
+		"rank":      r.Rank,
+		"breakdown": r.Breakdown,
+	}
+}
+
+func (r RecordScore) ToModel() scoring.Score {
+	return scoring.Score{
+		Ticker:    r.Ticker,
+		Date:      r.Date,
+		Sector:    r.Sector,
+		Score:     r.Score,
+		Rank:      r.Rank,
+		Breakdown: r.Breakdown,
+	}
+}
+
+// SaveScores replaces the scores of date.
+func (repo *ScoreRepositoryPB) SaveScores(date string, scores []scoring.Score) error {
+	collection, err := repo.pb.Dao().FindCollectionByNameOrId("score")
+	if err != nil {
+		return err
+	}
+
+	return repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		if _, err := txDao.DB().Delete("score", dbx.HashExp{"date": date}).Execute(); err != nil {
+			return err
+		}
+
+		for _, data := range scores {
+			record := models.NewRecord(collection)
+			record.Load(RecordScore{
+				Ticker:    data.Ticker,
+				Date:      date,
+				Sector:    data.Sector,
+				Score:     data.Score,
+				Rank:      data.Rank,
+				Breakdown: data.Breakdown,
+			}.ToMap())
+
+			if err := txDao.SaveRecord(record); err != nil {
+				repo.pb.Logger().Error("cannot write to `score`", "error", err.Error(), "ticker", data.Ticker)
+				continue
+			}
+		}
+		return nil
+	})
+}
+
+// GetScoresLatest gets the scores of the latest scored date, best ranked first.
+func (repo *ScoreRepositoryPB) GetScoresLatest() ([]scoring.Score, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr(
+		"score",
+		dbx.NewExp("date = (SELECT MAX(date) FROM score)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]scoring.Score, 0, len(records))
+	for _, record := range records {
+		s := scoring.Score{
+			Ticker:    record.GetString("ticker"),
+			Date:      record.GetString("date"),
+			Sector:    record.GetString("sector"),
+			Score:     record.GetFloat("score"),
+			Rank:      record.GetInt("rank"),
+			Breakdown: map[string]float64{},
+		}
+		if err := record.UnmarshalJSONField("breakdown", &s.Breakdown); err != nil {
+			repo.pb.Logger().Error("cannot read `score`", "error", err.Error(), "ticker", s.Ticker)
+		}
+		scores = append(scores, s)
+	}
+
+	slices.SortFunc(scores, func(a, b scoring.Score) int {
+		return cmp.Compare(a.Rank, b.Rank)
+	})
+
+	return scores, nil
+}
@@ -5,6 +5,8 @@ import (
 	"slices"
 
 	"example.com/stocker-back/internal/screener"
+	"example.com/stocker-back/internal/stock"
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/daos"
 	"github.com/pocketbase/pocketbase/models"
@@ -21,26 +23,42 @@ func NewScreenRepositoryPB(pb *pocketbase.PocketBase) *ScreenRepositoryPB {
 }
 
 type RecordScreen struct {
-	Ticker string  `db:"ticker" json:"ticker"`
-	Kdj    float64 `db:"kdj" json:"kdj"`
+	Name   string        `db:"name" json:"name"`
+	Ticker string        `db:"ticker" json:"ticker"`
+	Kdj    float64       `db:"kdj" json:"kdj"`
+	Events []stock.Event `db:"events" json:"events"`
 }
 
 func (r RecordScreen) ToMap() map[string]any {
 	return map[string]any{
+		"name":   r.Name,
 		"ticker": r.Ticker,
 		"kdj":    r.Kdj,
+		"events": r.Events,
 	}
 }
 
 func (r RecordScreen) ToModel() screener.Screen {
 	return screener.Screen{
+		Name:   r.Name,
 		Ticker: r.Ticker,
 		Kdj:    r.Kdj,
+		Events: r.Events,
 	}
 }
 
+// GetScreens gets hits of the default daily screen.
 func (repo *ScreenRepositoryPB) GetScreens() ([]screener.Screen, error) {
-	records, err := repo.pb.Dao().FindRecordsByExpr("screen")
+	return repo.GetScreensByName("")
+}
+
+// SetScreens replaces hits of the default daily screen.
+func (repo *ScreenRepositoryPB) SetScreens(screens []screener.Screen) error {
+	return repo.SetScreensByName("", screens)
+}
+
+func (repo *ScreenRepositoryPB) GetScreensByName(name string) ([]screener.Screen, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr("screen", dbx.HashExp{"name": name})
 	if err != nil {
 		return []screener.Screen{}, err
 	}
@@ -48,9 +66,13 @@ func (repo *ScreenRepositoryPB) GetScreens() ([]screener.Screen, error) {
 	screens := make([]screener.Screen, 0, len(records))
 	for idx := range records {
 		s := screener.Screen{
+			Name:   records[idx].GetString("name"),
 			Ticker: records[idx].GetString("ticker"),
 			Kdj:    records[idx].GetFloat("kdj"),
+			Events: []stock.Event{},
 		}
+		// Records saved before events were kept have none.
+		_ = records[idx].UnmarshalJSONField("events", &s.Events)
 		screens = append(screens, s)
 	}
 
@@ -62,9 +84,9 @@ func (repo *ScreenRepositoryPB) GetScreens() ([]screener.Screen, error) {
 	return screens, nil
 }
 
-func (repo *ScreenRepositoryPB) SetScreens(screens []screener.Screen) error {
-	// First clear all records.
-	records, err := repo.pb.Dao().FindRecordsByExpr("screen")
+func (repo *ScreenRepositoryPB) SetScreensByName(name string, screens []screener.Screen) error {
+	// First clear all records of the screen.
+	records, err := repo.pb.Dao().FindRecordsByExpr("screen", dbx.HashExp{"name": name})
 	if err != nil {
 		return err
 	}
@@ -82,6 +104,7 @@ func (repo *ScreenRepositoryPB) SetScreens(screens []screener.Screen) error {
 
 	err = repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
 		for _, data := range screens {
+			data.Name = name
 			recordData, err := data.ToMap()
 			if err != nil {
 				return err
@@ -104,3 +127,273 @@ func (repo *ScreenRepositoryPB) SetScreens(screens []screener.Screen) error {
 
 	return nil
 }
+
+func recordToDefinition(record *models.Record) (screener.Definition, error) {
+	var rule screener.Rule
+	if err := record.UnmarshalJSONField("rule", &rule); err != nil {
+		return screener.Definition{}, err
+	}
+
+	return screener.Definition{
+		Name:        record.GetString("name"),
+		Description: record.GetString("description"),
+		Rule:        rule,
+		Schedule:    record.GetString("schedule"),
+	}, nil
+}
+
+func (repo *ScreenRepositoryPB) GetDefinitions() ([]screener.Definition, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr("screendefinition")
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]screener.Definition, 0, len(records))
+	for _, record := range records {
+		definition, err := recordToDefinition(record)
+		if err != nil {
+			repo.pb.Logger().Error("cannot read `screendefinition`", "error", err.Error(), "name", record.GetString("name"))
+			continue
+		}
+		definitions = append(definitions, definition)
+	}
+
+	slices.SortFunc(definitions, func(a, b screener.Definition) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return definitions, nil
+}
+
+func (repo *ScreenRepositoryPB) GetDefinitionByName(name string) (screener.Definition, error) {
+	record, err := repo.pb.Dao().FindFirstRecordByData("screendefinition", "name", name)
+	if err != nil {
+		return screener.Definition{}, err
+	}
+
+	return recordToDefinition(record)
+}
+
+// SaveDefinition creates the definition or updates the one of the same name.
+func (repo *ScreenRepositoryPB) SaveDefinition(definition screener.Definition) error {
+	record, err := repo.pb.Dao().FindFirstRecordByData("screendefinition", "name", definition.Name)
+	if err != nil {
+		collection, err := repo.pb.Dao().FindCollectionByNameOrId("screendefinition")
+		if err != nil {
+			return err
+		}
+		record = models.NewRecord(collection)
+	}
+
+	record.Load(map[string]any{
+		"name":        definition.Name,
+		"description": definition.Description,
+		"rule":        definition.Rule,
+		"schedule":    definition.Schedule,
+	})
+
+	return repo.pb.Dao().SaveRecord(record)
+}
+
+// DeleteDefinitionByName deletes the definition along with its hits.
+func (repo *ScreenRepositoryPB) DeleteDefinitionByName(name string) error {
+	record, err := repo.pb.Dao().FindFirstRecordByData("screendefinition", "name", name)
+	if err != nil {
+		return err
+	}
+
+	return repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		if _, err := txDao.DB().Delete("screen", dbx.HashExp{"name": name}).Execute(); err != nil {
+			return err
+		}
+		return txDao.DeleteRecord(record)
+	})
+}
+
+type RecordSnapshot struct {
+	Name   string  `db:"name" json:"name"`
+	Date   string  `db:"date" json:"date"`
+	Ticker string  `db:"ticker" json:"ticker"`
+	Kdj    float64 `db:"kdj" json:"kdj"`
+}
+
+func (r RecordSnapshot) ToMap() map[string]any {
+	return map[string]any{
+		"name":   r.Name,
+		"date":   r.Date,
+		"ticker": r.Ticker,
+		"kdj":    r.Kdj,
+	}
+}
+
+func (r RecordSnapshot) ToModel() screener.Snapshot {
+	return screener.Snapshot{
+		Name:   r.Name,
+		Date:   r.Date,
+		Ticker: r.Ticker,
+		Kdj:    r.Kdj,
+	}
+}
+
+// RecordScreenRun is a run of a screen, kept apart from its hits so that runs
+// without hits count.
+type RecordScreenRun struct {
+	Name string `db:"name" json:"name"`
+	Date string `db:"date" json:"date"`
+	Hits int    `db:"hits" json:"hits"`
+}
+
+func (r RecordScreenRun) ToMap() map[string]any {
+	return map[string]any{
+		"name": r.Name,
+		"date": r.Date,
+		"hits": r.Hits,
+	}
+}
+
+func (repo *ScreenRepositoryPB) SaveSnapshots(name, date string, screens []screener.Screen) error {
+	collection, err := repo.pb.Dao().FindCollectionByNameOrId("screensnapshot")
+	if err != nil {
+		return err
+	}
+	collectionRun, err := repo.pb.Dao().FindCollectionByNameOrId("screenrun")
+	if err != nil {
+		return err
+	}
+
+	return repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		// Re-running a screen on the same day replaces its run and snapshots.
+		_, err := txDao.DB().Delete("screensnapshot", dbx.HashExp{"name": name, "date": date}).Execute()
+		if err != nil {
+			return err
+		}
+		_, err = txDao.DB().Delete("screenrun", dbx.HashExp{"name": name, "date": date}).Execute()
+		if err != nil {
+			return err
+		}
+
+		run := models.NewRecord(collectionRun)
+		run.Load(RecordScreenRun{Name: name, Date: date, Hits: len(screens)}.ToMap())
+		if err := txDao.SaveRecord(run); err != nil {
+			return err
+		}
+
+		for _, data := range screens {
+			record := models.NewRecord(collection)
+			record.Load(RecordSnapshot{
+				Name:   name,
+				Date:   date,
+				Ticker: data.Ticker,
+				Kdj:    data.Kdj,
+			}.ToMap())
+
+			if err := txDao.SaveRecord(record); err != nil {
+				repo.pb.Logger().Error("cannot write to `screensnapshot`", "error", err.Error(), "ticker", data.Ticker)
+				continue
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *ScreenRepositoryPB) GetSnapshotDates(name string, limit int) ([]string, error) {
+	dates := make([]string, 0)
+	// Runs saved before runs were recorded are only known by their hits.
+	for _, collection := range []string{"screenrun", "screensnapshot"} {
+		var rows []struct {
+			Date string `db:"date"`
+		}
+
+		query := repo.pb.Dao().DB().
+			Select("date").
+			Distinct(true).
+			From(collection).
+			Where(dbx.HashExp{"name": name}).
+			OrderBy("date DESC")
+		if limit > 0 {
+			query = query.Limit(int64(limit))
+		}
+		if err := query.All(&rows); err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			dates = append(dates, row.Date)
+		}
+	}
+
+	slices.SortFunc(dates, func(a, b string) int {
+		return cmp.Compare(b, a)
+	})
+	dates = slices.Compact(dates)
+	if limit > 0 {
+		dates = dates[:min(limit, len(dates))]
+	}
+
+	return dates, nil
+}
+
+func (repo *ScreenRepositoryPB) GetSnapshotsByDate(name, date string) ([]screener.Snapshot, error) {
+	return repo.findSnapshots(dbx.HashExp{"name": name, "date": date})
+}
+
+func (repo *ScreenRepositoryPB) GetSnapshotsByTicker(name, ticker string) ([]screener.Snapshot, error) {
+	return repo.findSnapshots(dbx.HashExp{"name": name, "ticker": ticker})
+}
+
+func (repo *ScreenRepositoryPB) findSnapshots(where dbx.Expression) ([]screener.Snapshot, error) {
+	var records []RecordSnapshot
+
+	err := repo.pb.Dao().DB().
+		Select("name", "date", "ticker", "kdj").
+		From("screensnapshot").
+		Where(where).
+		OrderBy("date ASC", "ticker ASC").
+		All(&records)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]screener.Snapshot, 0, len(records))
+	for _, r := range records {
+		output = append(output, r.ToModel())
+	}
+
+	return output, nil
+}
+
+func (repo *ScreenRepositoryPB) GetNotifiedDate(name string) (string, error) {
+	var rows []struct {
+		Date string `db:"date"`
+	}
+
+	err := repo.pb.Dao().DB().
+		Select("date").
+		From("screennotified").
+		Where(dbx.HashExp{"name": name}).
+		All(&rows)
+	if err != nil || len(rows) == 0 {
+		return "", err
+	}
+
+	return rows[0].Date, nil
+}
+
+// SetNotifiedDate creates or updates the notified run date of the screen.
+func (repo *ScreenRepositoryPB) SetNotifiedDate(name, date string) error {
+	record, err := repo.pb.Dao().FindFirstRecordByData("screennotified", "name", name)
+	if err != nil {
+		collection, err := repo.pb.Dao().FindCollectionByNameOrId("screennotified")
+		if err != nil {
+			return err
+		}
+		record = models.NewRecord(collection)
+	}
+
+	record.Load(map[string]any{
+		"name": name,
+		"date": date,
+	})
+
+	return repo.pb.Dao().SaveRecord(record)
+}
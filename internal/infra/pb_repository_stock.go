@@ -1,8 +1,6 @@
 package infra
 
 import (
-	"fmt"
-
 	"example.com/stocker-back/internal/stock"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase"
@@ -158,6 +156,31 @@ func (repo *StockRepositoryPB) GetStocks() ([]stock.Stock, error) {
 	return output, nil
 }
 
+// GetStocksByTickers gets stocks of tickers at once, leaving out unknown tickers.
+func (repo *StockRepositoryPB) GetStocksByTickers(tickers []string) ([]stock.Stock, error) {
+	if len(tickers) == 0 {
+		return []stock.Stock{}, nil
+	}
+
+	var records []RecordStock
+
+	err := repo.pb.Dao().DB().
+		Select().
+		From("stocks").
+		Where(dbx.In("ticker", lo.ToAnySlice(tickers)...)).
+		All(&records)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]stock.Stock, 0, len(records))
+	for _, s := range records {
+		output = append(output, s.ToModel())
+	}
+
+	return output, nil
+}
+
 func (repo *StockRepositoryPB) GetStocksBySector(sector string) ([]stock.Stock, error) {
 	var records []RecordStock
 
@@ -184,6 +207,7 @@ func (repo *StockRepositoryPB) GetDailyDataAll() (map[string][]stock.DailyData,
 	err := repo.pb.Dao().DB().
 		Select().
 		From("daily").
+		OrderBy("date ASC").
 		All(&recordDailyData)
 	if err != nil {
 		return nil, err
@@ -245,6 +269,35 @@ func (repo *StockRepositoryPB) GetDailyDataLastAll() ([]stock.DailyData, error)
 	return output, nil
 }
 
+// GetDailyDataLastByTickers gets the last daily data of tickers at once, leaving out
+// tickers without daily data.
+func (repo *StockRepositoryPB) GetDailyDataLastByTickers(tickers []string) ([]stock.DailyData, error) {
+	if len(tickers) == 0 {
+		return []stock.DailyData{}, nil
+	}
+
+	var recordDailyData []RecordDailyData
+
+	err := repo.pb.Dao().DB().
+		Select().
+		From("daily").
+		Where(dbx.And(
+			dbx.In("ticker", lo.ToAnySlice(tickers)...),
+			dbx.NewExp("date = (SELECT MAX(last.date) FROM daily AS last WHERE last.ticker = daily.ticker)"),
+		)).
+		All(&recordDailyData)
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]stock.DailyData, 0, len(recordDailyData))
+	for _, d := range recordDailyData {
+		output = append(output, d.ToModel())
+	}
+
+	return output, nil
+}
+
 func (repo *StockRepositoryPB) CreateStock(stock stock.Stock) error {
 	collection, err := repo.pb.Dao().FindCollectionByNameOrId("stocks")
 	if err != nil {
@@ -364,50 +417,30 @@ func (repo *StockRepositoryPB) CreateDailyData(dailyData []stock.DailyData) erro
 	return nil
 }
 
-// DeleteStockByTicker deletes `ticker` records everywhere in the database.
+// tickerCollections hold the market data and tracking of a stock, deleted along with it.
+// Screen runs only count hits and are kept, as are trades and paper positions of the ticker.
+var tickerCollections = []string{"stocks", "daily", "indicators", "tracking", "screen", "score", "screensnapshot"}
+
+// DeleteStockByTicker deletes `ticker` records of tickerCollections.
 func (repo *StockRepositoryPB) DeleteStockByTicker(ticker string) error {
 	repo.pb.Logger().Info("DeleteStockByTicker", "ticker", ticker)
-	collections, err := repo.pb.Dao().FindCollectionsByType(models.CollectionTypeBase)
-	if err != nil {
-		return err
-	}
 
-	for _, c := range collections {
-		if !fieldInCollection(repo.pb.Dao(), "ticker", c) {
-			continue
-		}
-		if err := deleteRecords(repo.pb.Dao(), c.Name, "ticker", ticker); err != nil {
-			repo.pb.Logger().Info("deleteRecords", "error", err.Error())
-			return err
+	return repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		for _, collection := range tickerCollections {
+			if err := deleteRecords(txDao, collection, "ticker", ticker); err != nil {
+				repo.pb.Logger().Info("deleteRecords", "error", err.Error(), "collection", collection)
+				return err
+			}
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // deleteRecords deletes all records in `collectionName` where `fieldName` = `fieldValue`.
 func deleteRecords(dao *daos.Dao, collectionName, fieldName, fieldValue string) error {
-	query := fmt.Sprintf("DELETE FROM %v WHERE %v = '%v'", collectionName, fieldName, fieldValue)
-	if _, err := dao.DB().NewQuery(query).Execute(); err != nil {
+	if _, err := dao.DB().Delete(collectionName, dbx.HashExp{fieldName: fieldValue}).Execute(); err != nil {
 		return err
 	}
 
 	return nil
 }
-
-// fieldInCollection checks if `field` is in `collection`.
-func fieldInCollection(dao *daos.Dao, field string, collection *models.Collection) bool {
-	record := models.Record{
-		BaseModel: collection.BaseModel,
-	}
-	query := dao.RecordQuery(collection.Name).Limit(1)
-	if err := query.One(&record); err != nil {
-		return false
-	}
-
-	if record.Get(field) != nil {
-		return true
-	}
-
-	return false
-}
@@ -0,0 +1,162 @@
+package infra
+
+import (
+	"cmp"
+	"slices"
+
+	"example.com/stocker-back/internal/backtest"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase"
+	"github.com/pocketbase/pocketbase/daos"
+	"github.com/pocketbase/pocketbase/models"
+)
+
+type SweepRepositoryPB struct {
+	pb *pocketbase.PocketBase
+}
+
+func NewSweepRepositoryPB(pb *pocketbase.PocketBase) *SweepRepositoryPB {
+	return &SweepRepositoryPB{
+		pb: pb,
+	}
+}
+
+func recordToSweep(record *models.Record) (backtest.Sweep, error) {
+	var sweep backtest.Sweep
+	if err := record.UnmarshalJSONField("spec", &sweep); err != nil {
+		return backtest.Sweep{}, err
+	}
+
+	sweep.Name = record.GetString("name")
+	sweep.Status = backtest.SweepStatus(record.GetString("status"))
+	sweep.Error = record.GetString("error")
+	return sweep, nil
+}
+
+func (repo *SweepRepositoryPB) GetSweeps() ([]backtest.Sweep, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr("sweep")
+	if err != nil {
+		return nil, err
+	}
+
+	sweeps := make([]backtest.Sweep, 0, len(records))
+	for _, record := range records {
+		sweep, err := recordToSweep(record)
+		if err != nil {
+			repo.pb.Logger().Error("cannot read `sweep`", "error", err.Error(), "name", record.GetString("name"))
+			continue
+		}
+		sweeps = append(sweeps, sweep)
+	}
+
+	slices.SortFunc(sweeps, func(a, b backtest.Sweep) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	return sweeps, nil
+}
+
+func (repo *SweepRepositoryPB) GetSweepByName(name string) (backtest.Sweep, error) {
+	record, err := repo.pb.Dao().FindFirstRecordByData("sweep", "name", name)
+	if err != nil {
+		return backtest.Sweep{}, err
+	}
+
+	return recordToSweep(record)
+}
+
+// SaveSweep creates the sweep or updates the one of the same name.
+func (repo *SweepRepositoryPB) SaveSweep(sweep backtest.Sweep) error {
+	record, err := repo.pb.Dao().FindFirstRecordByData("sweep", "name", sweep.Name)
+	if err != nil {
+		collection, err := repo.pb.Dao().FindCollectionByNameOrId("sweep")
+		if err != nil {
+			return err
+		}
+		record = models.NewRecord(collection)
+	}
+
+	record.Load(map[string]any{
+		"name":   sweep.Name,
+		"spec":   sweep,
+		"status": string(sweep.Status),
+		"error":  sweep.Error,
+	})
+
+	return repo.pb.Dao().SaveRecord(record)
+}
+
+// DeleteSweepByName deletes the sweep along with its runs.
+func (repo *SweepRepositoryPB) DeleteSweepByName(name string) error {
+	record, err := repo.pb.Dao().FindFirstRecordByData("sweep", "name", name)
+	if err != nil {
+		return err
+	}
+
+	return repo.pb.Dao().RunInTransaction(func(txDao *daos.Dao) error {
+		if _, err := txDao.DB().Delete("sweeprun", dbx.HashExp{"name": name}).Execute(); err != nil {
+			return err
+		}
+		return txDao.DeleteRecord(record)
+	})
+}
+
+type RecordSweepRun struct {
+	Name string            `db:"name" json:"name"`
+	Key  string            `db:"key" json:"key"`
+	Run  backtest.SweepRun `db:"run" json:"run"`
+}
+
+func (r RecordSweepRun) ToMap() map[string]any {
+	return map[string]any{
+		"name": r.Name,
+		"key":  r.Key,
+		"run":  r.Run,
+	}
+}
+
+func (repo *SweepRepositoryPB) GetSweepRuns(name string) ([]backtest.SweepRun, error) {
+	records, err := repo.pb.Dao().FindRecordsByExpr("sweeprun", dbx.HashExp{"name": name})
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]backtest.SweepRun, 0, len(records))
+	for _, record := range records {
+		var run backtest.SweepRun
+		if err := record.UnmarshalJSONField("run", &run); err != nil {
+			repo.pb.Logger().Error("cannot read `sweeprun`", "error", err.Error(), "name", name, "key", record.GetString("key"))
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// SaveSweepRun creates the run or updates the one of the same sweep and key.
+func (repo *SweepRepositoryPB) SaveSweepRun(name string, run backtest.SweepRun) error {
+	records, err := repo.pb.Dao().FindRecordsByExpr("sweeprun", dbx.HashExp{"name": name, "key": run.Key()})
+	if err != nil {
+		return err
+	}
+
+	var record *models.Record
+	if len(records) > 0 {
+		record = records[0]
+	} else {
+		collection, err := repo.pb.Dao().FindCollectionByNameOrId("sweeprun")
+		if err != nil {
+			return err
+		}
+		record = models.NewRecord(collection)
+	}
+
+	record.Load(RecordSweepRun{
+		Name: name,
+		Key:  run.Key(),
+		Run:  run,
+	}.ToMap())
+
+	return repo.pb.Dao().SaveRecord(record)
+}
@@ -0,0 +1,167 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidTrade = errors.New("invalid journal trade")
+	ErrOversold     = errors.New("sell exceeds shares held")
+)
+
+// dateLayout is the layout of trade dates.
+const dateLayout = "2006-01-02"
+
+// Kind is the type of a journal entry.
+type Kind string
+
+const (
+	KindBuy      Kind = "buy"
+	KindSell     Kind = "sell"
+	KindDividend Kind = "dividend"
+	KindFee      Kind = "fee"
+)
+
+// Method is how the cost of shares sold is told.
+type Method string
+
+const (
+	// MethodFIFO sells the oldest lots first at their own cost.
+	MethodFIFO Method = "fifo"
+	// MethodAverage sells the oldest lots first at the weighted-average cost of the
+	// shares held, so lots only tell holding periods.
+	MethodAverage Method = "average"
+)
+
+// Trade is entity of a brokerage journal entry on Date, "2006-01-02". Buys and sells
+// are of Shares at Price with Fees; a dividend is of cash Amount received, a fee of
+// Fees charged apart from a trade, e.g. dividend tax or custody. Ref is the broker's
+// reference, e.g. contract number, telling imported trades apart.
+type Trade struct {
+	ID     string  `json:"id"`
+	Ticker string  `json:"ticker"`
+	Date   string  `json:"date"`
+	Kind   Kind    `json:"kind"`
+	Shares float64 `json:"shares"`
+	Price  float64 `json:"price"`
+	Fees   float64 `json:"fees"`
+	Amount float64 `json:"amount"`
+	Ref    string  `json:"ref"`
+	Note   string  `json:"note"`
+}
+
+// Validate checks the trade has a ticker, a valid date and the figures of its kind.
+func (t Trade) Validate() error {
+	if t.Ticker == "" {
+		return fmt.Errorf("%w: ticker is required", ErrInvalidTrade)
+	}
+	if _, err := time.Parse(dateLayout, t.Date); err != nil {
+		return fmt.Errorf("%w: date must be %s", ErrInvalidTrade, dateLayout)
+	}
+	if t.Fees < 0.0 {
+		return fmt.Errorf("%w: fees must not be negative", ErrInvalidTrade)
+	}
+
+	switch t.Kind {
+	case KindBuy, KindSell:
+		if t.Shares <= 0.0 || t.Price <= 0.0 {
+			return fmt.Errorf("%w: %s needs positive shares and price", ErrInvalidTrade, t.Kind)
+		}
+	case KindDividend:
+		if t.Amount <= 0.0 {
+			return fmt.Errorf("%w: dividend needs positive amount", ErrInvalidTrade)
+		}
+	case KindFee:
+		if t.Fees <= 0.0 {
+			return fmt.Errorf("%w: fee needs positive fees", ErrInvalidTrade)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidTrade, t.Kind)
+	}
+
+	return nil
+}
+
+// Lot is valueobject of shares bought on OpenDate still held, at Cost per share with
+// buy fees included.
+type Lot struct {
+	Ticker   string  `json:"ticker"`
+	OpenDate string  `json:"opendate"`
+	Shares   float64 `json:"shares"`
+	Cost     float64 `json:"cost"`
+}
+
+// ClosedLot is valueobject of shares of a lot sold on CloseDate. Cost is their total
+// cost, Proceeds net of their share of sell fees, Return in percent of Cost, HoldDays
+// in calendar days.
+type ClosedLot struct {
+	Ticker    string  `json:"ticker"`
+	OpenDate  string  `json:"opendate"`
+	CloseDate string  `json:"closedate"`
+	Shares    float64 `json:"shares"`
+	Cost      float64 `json:"cost"`
+	Proceeds  float64 `json:"proceeds"`
+	Gain      float64 `json:"gain"`
+	Return    float64 `json:"return"`
+	HoldDays  int     `json:"holddays"`
+}
+
+// Position is valueobject of a ticker traded in the journal, open if Shares are held.
+// Marked at Price, the close of Date, when daily data is stored, at cost otherwise;
+// Realized sums gains of closed lots less fees booked apart, Dividends the cash received.
+type Position struct {
+	Ticker        string  `json:"ticker"`
+	Name          string  `json:"name"`
+	Sector        string  `json:"sector"`
+	Shares        float64 `json:"shares"`
+	AvgCost       float64 `json:"avgcost"`
+	CostBasis     float64 `json:"costbasis"`
+	Price         float64 `json:"price"`
+	Date          string  `json:"date"`
+	Value         float64 `json:"value"`
+	Unrealized    float64 `json:"unrealized"`
+	UnrealizedPct float64 `json:"unrealizedpct"`
+	Realized      float64 `json:"realized"`
+	Dividends     float64 `json:"dividends"`
+	Lots          []Lot   `json:"lots"`
+}
+
+// HoldingStats aggregates closed lots: WinRate and AvgReturn in percent, hold days in
+// calendar days, AvgWinHoldDays and AvgLossHoldDays over lots closed with a gain and
+// without one.
+type HoldingStats struct {
+	Closed          int     `json:"closed"`
+	Wins            int     `json:"wins"`
+	WinRate         float64 `json:"winrate"`
+	AvgReturn       float64 `json:"avgreturn"`
+	AvgHoldDays     float64 `json:"avgholddays"`
+	MedianHoldDays  float64 `json:"medianholddays"`
+	MaxHoldDays     int     `json:"maxholddays"`
+	AvgWinHoldDays  float64 `json:"avgwinholddays"`
+	AvgLossHoldDays float64 `json:"avglossholddays"`
+}
+
+// Book is valueobject of the journal replayed with Method: positions by ticker, open
+// ones first, closed lots in date order and totals over all tickers.
+type Book struct {
+	Method     Method       `json:"method"`
+	Positions  []Position   `json:"positions"`
+	Closed     []ClosedLot  `json:"closed"`
+	CostBasis  float64      `json:"costbasis"`
+	Value      float64      `json:"value"`
+	Unrealized float64      `json:"unrealized"`
+	Realized   float64      `json:"realized"`
+	Dividends  float64      `json:"dividends"`
+	Fees       float64      `json:"fees"`
+	Stats      HoldingStats `json:"stats"`
+}
+
+// Import is valueobject of a broker export imported: trades saved, rows already in the
+// journal and rows skipped for their operation.
+type Import struct {
+	Imported   int `json:"imported"`
+	Duplicates int `json:"duplicates"`
+	Skipped    int `json:"skipped"`
+}
@@ -0,0 +1,11 @@
+package journal
+
+type Repository interface {
+	// GetTrades gets all trades in date order.
+	GetTrades() ([]Trade, error)
+	// GetTradesByTicker gets trades of ticker in date order.
+	GetTradesByTicker(ticker string) ([]Trade, error)
+	// CreateTrades saves new trades at once and returns them with their IDs.
+	CreateTrades(trades []Trade) ([]Trade, error)
+	DeleteTradeByID(id string) error
+}
@@ -0,0 +1,244 @@
+//nolint:gomnd //ignore
+package journal
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// sharesEpsilon absorbs float error when comparing share counts.
+const sharesEpsilon = 1e-6
+
+// Replay books trades in date order, trades of the same date in the order given, into
+// lots and closed lots by method. Selling more shares than held is ErrOversold.
+// Positions are left unmarked, see Book.Mark.
+func Replay(trades []Trade, method Method) (Book, error) {
+	if method != MethodFIFO && method != MethodAverage {
+		return Book{}, fmt.Errorf("%w: unknown method %q", ErrInvalidTrade, method)
+	}
+
+	trades = slices.Clone(trades)
+	slices.SortStableFunc(trades, func(a, b Trade) int {
+		return cmp.Compare(a.Date, b.Date)
+	})
+
+	book := Book{
+		Method:     method,
+		Positions:  make([]Position, 0),
+		Closed:     make([]ClosedLot, 0),
+		CostBasis:  0.0,
+		Value:      0.0,
+		Unrealized: 0.0,
+		Realized:   0.0,
+		Dividends:  0.0,
+		Fees:       0.0,
+		Stats:      HoldingStats{}, //nolint:exhaustruct
+	}
+	positions := make(map[string]*Position)
+	position := func(ticker string) *Position {
+		if positions[ticker] == nil {
+			positions[ticker] = &Position{Ticker: ticker, Lots: make([]Lot, 0)} //nolint:exhaustruct
+		}
+		return positions[ticker]
+	}
+
+	for _, t := range trades {
+		if err := t.Validate(); err != nil {
+			return Book{}, err
+		}
+		p := position(t.Ticker)
+		book.Fees += t.Fees
+
+		switch t.Kind {
+		case KindBuy:
+			p.Lots = append(p.Lots, Lot{
+				Ticker:   t.Ticker,
+				OpenDate: t.Date,
+				Shares:   t.Shares,
+				Cost:     (t.Shares*t.Price + t.Fees) / t.Shares,
+			})
+			if method == MethodAverage {
+				avg := lotsCost(p.Lots) / lotsShares(p.Lots)
+				for idx := range p.Lots {
+					p.Lots[idx].Cost = avg
+				}
+			}
+
+		case KindSell:
+			if t.Shares > lotsShares(p.Lots)+sharesEpsilon {
+				return Book{}, fmt.Errorf("%w: %s on %s sells %g of %g", ErrOversold, t.Ticker, t.Date, t.Shares, lotsShares(p.Lots))
+			}
+			proceedsPerShare := (t.Shares*t.Price - t.Fees) / t.Shares
+			remaining := t.Shares
+			for remaining > sharesEpsilon && len(p.Lots) > 0 {
+				lot := &p.Lots[0]
+				take := math.Min(lot.Shares, remaining)
+				closed := ClosedLot{
+					Ticker:    t.Ticker,
+					OpenDate:  lot.OpenDate,
+					CloseDate: t.Date,
+					Shares:    take,
+					Cost:      take * lot.Cost,
+					Proceeds:  take * proceedsPerShare,
+					Gain:      take * (proceedsPerShare - lot.Cost),
+					Return:    0.0,
+					HoldDays:  daysBetween(lot.OpenDate, t.Date),
+				}
+				if closed.Cost > 0.0 {
+					closed.Return = 100.0 * closed.Gain / closed.Cost
+				}
+				book.Closed = append(book.Closed, closed)
+				p.Realized += closed.Gain
+
+				lot.Shares -= take
+				remaining -= take
+				if lot.Shares <= sharesEpsilon {
+					p.Lots = p.Lots[1:]
+				}
+			}
+
+		case KindDividend:
+			p.Dividends += t.Amount
+
+		case KindFee:
+			p.Realized -= t.Fees
+		}
+	}
+
+	for _, p := range positions {
+		p.Shares = lotsShares(p.Lots)
+		p.CostBasis = lotsCost(p.Lots)
+		if p.Shares > 0.0 {
+			p.AvgCost = p.CostBasis / p.Shares
+		}
+		p.Price, p.Value = p.AvgCost, p.CostBasis
+
+		book.Positions = append(book.Positions, *p)
+		book.CostBasis += p.CostBasis
+		book.Value += p.Value
+		book.Realized += p.Realized
+		book.Dividends += p.Dividends
+	}
+	slices.SortFunc(book.Positions, func(a, b Position) int {
+		if (a.Shares > 0.0) != (b.Shares > 0.0) {
+			if a.Shares > 0.0 {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Ticker, b.Ticker)
+	})
+	book.Stats = ComputeHoldingStats(book.Closed)
+
+	return book, nil
+}
+
+// Mark names open positions from stocks and marks them at the close of the last daily
+// data of each ticker, at cost without it.
+func (b Book) Mark(stocks map[string]stock.Stock, last map[string]stock.DailyData) Book {
+	b.Positions = slices.Clone(b.Positions)
+	b.Value, b.Unrealized = 0.0, 0.0
+
+	for idx := range b.Positions {
+		p := &b.Positions[idx]
+		if s, ok := stocks[p.Ticker]; ok {
+			p.Name, p.Sector = s.Name, s.Sector
+		}
+		p.Price, p.Date = p.AvgCost, ""
+		if d, ok := last[p.Ticker]; ok && d.Close > 0.0 && p.Shares > 0.0 {
+			p.Price, p.Date = d.Close, d.Date
+		}
+		p.Value = p.Shares * p.Price
+		p.Unrealized = p.Value - p.CostBasis
+		p.UnrealizedPct = 0.0
+		if p.CostBasis > 0.0 {
+			p.UnrealizedPct = 100.0 * p.Unrealized / p.CostBasis
+		}
+
+		b.Value += p.Value
+		b.Unrealized += p.Unrealized
+	}
+
+	return b
+}
+
+// OpenTickers returns tickers with shares held.
+func (b Book) OpenTickers() []string {
+	open := lo.Filter(b.Positions, func(p Position, _ int) bool { return p.Shares > 0.0 })
+	return lo.Map(open, func(p Position, _ int) string { return p.Ticker })
+}
+
+// ComputeHoldingStats aggregates closed lots.
+func ComputeHoldingStats(closed []ClosedLot) HoldingStats {
+	stats := HoldingStats{
+		Closed:          len(closed),
+		Wins:            0,
+		WinRate:         0.0,
+		AvgReturn:       0.0,
+		AvgHoldDays:     0.0,
+		MedianHoldDays:  0.0,
+		MaxHoldDays:     0,
+		AvgWinHoldDays:  0.0,
+		AvgLossHoldDays: 0.0,
+	}
+	if len(closed) == 0 {
+		return stats
+	}
+
+	wins := lo.Filter(closed, func(c ClosedLot, _ int) bool { return c.Gain > 0.0 })
+	losses := lo.Reject(closed, func(c ClosedLot, _ int) bool { return c.Gain > 0.0 })
+	holdDays := func(lots []ClosedLot) float64 {
+		if len(lots) == 0 {
+			return 0.0
+		}
+		return float64(lo.SumBy(lots, func(c ClosedLot) int { return c.HoldDays })) / float64(len(lots))
+	}
+
+	stats.Wins = len(wins)
+	stats.WinRate = 100.0 * float64(len(wins)) / float64(len(closed))
+	stats.AvgReturn = lo.SumBy(closed, func(c ClosedLot) float64 { return c.Return }) / float64(len(closed))
+	stats.AvgHoldDays = holdDays(closed)
+	stats.AvgWinHoldDays = holdDays(wins)
+	stats.AvgLossHoldDays = holdDays(losses)
+
+	days := lo.Map(closed, func(c ClosedLot, _ int) int { return c.HoldDays })
+	slices.Sort(days)
+	stats.MaxHoldDays = days[len(days)-1]
+	if mid := len(days) / 2; len(days)%2 == 1 {
+		stats.MedianHoldDays = float64(days[mid])
+	} else {
+		stats.MedianHoldDays = float64(days[mid-1]+days[mid]) / 2.0
+	}
+
+	return stats
+}
+
+func lotsShares(lots []Lot) float64 {
+	return lo.SumBy(lots, func(l Lot) float64 { return l.Shares })
+}
+
+func lotsCost(lots []Lot) float64 {
+	return lo.SumBy(lots, func(l Lot) float64 { return l.Shares * l.Cost })
+}
+
+// daysBetween returns calendar days from open to closing date.
+func daysBetween(open, closing string) int {
+	from, errFrom := time.Parse(dateLayout, open)
+	to, errTo := time.Parse(dateLayout, closing)
+	if errFrom != nil || errTo != nil {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// Key tells a trade apart from others of the journal regardless of its ID, telling
+// re-imported rows of a broker export.
+func (t Trade) Key() string {
+	return fmt.Sprintf("%s/%s/%s/%g/%g/%g/%g/%s", t.Date, t.Ticker, t.Kind, t.Shares, t.Price, t.Fees, t.Amount, t.Ref)
+}
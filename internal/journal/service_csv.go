@@ -0,0 +1,214 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+var ErrInvalidCSV = errors.New("invalid broker csv")
+
+// CSVFormat maps columns of a broker export, by header, onto trades. Side holds the
+// operation, told by the values listed for each kind; rows of other operations, e.g.
+// bank transfers, are skipped. Fees sums several columns, e.g. commission, stamp duty
+// and transfer fee; Amount is the cash of dividends. Ticker takes a 6-digit code or
+// an eastmoney ticker, e.g. "1.600000".
+type CSVFormat struct {
+	Date       string   `json:"date"`
+	DateLayout string   `json:"datelayout"`
+	Ticker     string   `json:"ticker"`
+	Side       string   `json:"side"`
+	Shares     string   `json:"shares"`
+	Price      string   `json:"price"`
+	Fees       []string `json:"fees"`
+	Amount     string   `json:"amount"`
+	Ref        string   `json:"ref"`
+	Buy        []string `json:"buy"`
+	Sell       []string `json:"sell"`
+	Dividend   []string `json:"dividend"`
+	Fee        []string `json:"fee"`
+}
+
+// DefaultCSVFormat returns the delivery statement (交割单) export of common A-share
+// brokerage clients.
+func DefaultCSVFormat() CSVFormat {
+	return CSVFormat{
+		Date:       "成交日期",
+		DateLayout: "20060102",
+		Ticker:     "证券代码",
+		Side:       "业务名称",
+		Shares:     "成交数量",
+		Price:      "成交价格",
+		Fees:       []string{"手续费", "印花税", "过户费", "其他杂费"},
+		Amount:     "发生金额",
+		Ref:        "合同编号",
+		Buy:        []string{"证券买入", "买入"},
+		Sell:       []string{"证券卖出", "卖出"},
+		Dividend:   []string{"红利入账", "股息入账"},
+		Fee:        []string{"股息红利税补", "红利税补缴"},
+	}
+}
+
+// ParseCSV reads trades from a broker export in format, UTF-8 or else GBK as most
+// brokerage clients save it, returning them in row order with the number of rows
+// skipped for their operation.
+func ParseCSV(r io.Reader, format CSVFormat) ([]Trade, int, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !utf8.Valid(b) {
+		if b, err = simplifiedchinese.GBK.NewDecoder().Bytes(b); err != nil {
+			return nil, 0, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+		}
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", ErrInvalidCSV, err)
+	}
+	if len(rows) == 0 {
+		return nil, 0, fmt.Errorf("%w: no header", ErrInvalidCSV)
+	}
+
+	columns := make(map[string]int, len(rows[0]))
+	for idx, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = idx
+	}
+	for _, name := range []string{format.Date, format.Ticker, format.Side} {
+		if _, ok := columns[name]; !ok {
+			return nil, 0, fmt.Errorf("%w: missing column %q", ErrInvalidCSV, name)
+		}
+	}
+
+	trades := make([]Trade, 0, len(rows)-1)
+	skipped := 0
+	for line, row := range rows[1:] {
+		cell := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(row) {
+				return ""
+			}
+			// Exports quote codes as ="600000" to keep leading zeros in spreadsheets.
+			return strings.Trim(strings.TrimSpace(row[idx]), "=\"\t")
+		}
+		number := func(name string) (float64, error) {
+			value := strings.ReplaceAll(cell(name), ",", "")
+			if value == "" {
+				return 0.0, nil
+			}
+			return strconv.ParseFloat(value, 64)
+		}
+
+		kind, ok := format.kind(cell(format.Side))
+		if !ok {
+			skipped++
+			continue
+		}
+
+		date, err := time.Parse(format.DateLayout, cell(format.Date))
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: line %d: %w", ErrInvalidCSV, line+2, err)
+		}
+		ticker, err := TickerFromCode(cell(format.Ticker))
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: line %d: %w", ErrInvalidCSV, line+2, err)
+		}
+
+		t := Trade{
+			ID:     "",
+			Ticker: ticker,
+			Date:   date.Format(dateLayout),
+			Kind:   kind,
+			Shares: 0.0,
+			Price:  0.0,
+			Fees:   0.0,
+			Amount: 0.0,
+			Ref:    cell(format.Ref),
+			Note:   cell(format.Side),
+		}
+		values := []struct {
+			name   string
+			target *float64
+		}{{format.Shares, &t.Shares}, {format.Price, &t.Price}, {format.Amount, &t.Amount}}
+		for _, v := range values {
+			if *v.target, err = number(v.name); err != nil {
+				return nil, 0, fmt.Errorf("%w: line %d: %s: %w", ErrInvalidCSV, line+2, v.name, err)
+			}
+		}
+		for _, name := range format.Fees {
+			fee, err := number(name)
+			if err != nil {
+				return nil, 0, fmt.Errorf("%w: line %d: %s: %w", ErrInvalidCSV, line+2, name, err)
+			}
+			t.Fees += math.Abs(fee)
+		}
+		// Exports sign quantities and cash by direction.
+		t.Shares, t.Amount = math.Abs(t.Shares), math.Abs(t.Amount)
+		if kind == KindFee && t.Fees == 0.0 {
+			t.Fees = t.Amount
+		}
+		if kind != KindDividend {
+			t.Amount = 0.0
+		}
+
+		if err := t.Validate(); err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		trades = append(trades, t)
+	}
+
+	return trades, skipped, nil
+}
+
+func (f CSVFormat) kind(side string) (Kind, bool) {
+	switch {
+	case slices.Contains(f.Buy, side):
+		return KindBuy, true
+	case slices.Contains(f.Sell, side):
+		return KindSell, true
+	case slices.Contains(f.Dividend, side):
+		return KindDividend, true
+	case slices.Contains(f.Fee, side):
+		return KindFee, true
+	default:
+		return "", false
+	}
+}
+
+// TickerFromCode returns the eastmoney ticker of an A-share code, leading zeros lost
+// to spreadsheets restored: "0." for Beijing codes starting with 4, 8 or 92, "1." for
+// Shanghai codes starting with 5, 6 or 9 otherwise, "0." for Shenzhen. Eastmoney
+// tickers are returned as is.
+func TickerFromCode(code string) (string, error) {
+	if strings.Contains(code, ".") {
+		return code, nil
+	}
+	if code == "" || len(code) > 6 || strings.Trim(code, "0123456789") != "" {
+		return "", fmt.Errorf("%w: invalid code %q", ErrInvalidTrade, code)
+	}
+
+	code = strings.Repeat("0", 6-len(code)) + code
+	switch {
+	case strings.HasPrefix(code, "4"), strings.HasPrefix(code, "8"), strings.HasPrefix(code, "92"):
+		return "0." + code, nil
+	case strings.ContainsRune("569", rune(code[0])):
+		return "1." + code, nil
+	default:
+		return "0." + code, nil
+	}
+}
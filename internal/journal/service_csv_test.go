@@ -0,0 +1,89 @@
+//nolint:testpackage,lll //ignore
+package journal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestParseCSV(t *testing.T) {
+	export := "\xef\xbb\xbf成交日期,证券代码,证券名称,业务名称,成交数量,成交价格,发生金额,手续费,印花税,过户费,其他杂费,合同编号\n" +
+		"20240102,=\"600000\",浦发银行,证券买入,100,10.00,-1005.01,5.00,0.00,0.01,0,1001\n" +
+		"20240110,1,平安银行,证券买入,200,5.00,-1005.00,5.00,0.00,0.00,0,1002\n" +
+		"20240115,,,银行转证券,0,0,50000,0,0,0,0,\n" +
+		"20240301,600000,浦发银行,证券卖出,-100,13.00,1293.34,5.00,0.65,0.01,0,1003\n" +
+		"20240315,600000,浦发银行,红利入账,0,0,20.00,0,0,0,0,\n" +
+		"20240320,600000,浦发银行,股息红利税补,0,0,-2.00,0,0,0,0,\n"
+
+	trades, skipped, err := ParseCSV(strings.NewReader(export), DefaultCSVFormat())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, skipped)
+	if !assert.Len(t, trades, 5) {
+		return
+	}
+
+	assert.Equal(t, Trade{Ticker: "1.600000", Date: "2024-01-02", Kind: KindBuy, Shares: 100, Price: 10, Fees: 5.01, Ref: "1001", Note: "证券买入"}, trades[0]) //nolint:exhaustruct
+	assert.Equal(t, "0.000001", trades[1].Ticker)
+	assert.Equal(t, KindSell, trades[2].Kind)
+	assert.InDelta(t, 100.0, trades[2].Shares, 1e-9)
+	assert.InDelta(t, 5.66, trades[2].Fees, 1e-9)
+	assert.Equal(t, KindDividend, trades[3].Kind)
+	assert.InDelta(t, 20.0, trades[3].Amount, 1e-9)
+	assert.Equal(t, KindFee, trades[4].Kind)
+	assert.InDelta(t, 2.0, trades[4].Fees, 1e-9)
+	assert.InDelta(t, 0.0, trades[4].Amount, 1e-9)
+}
+
+func TestParseCSVGBK(t *testing.T) {
+	export, err := simplifiedchinese.GBK.NewEncoder().String("成交日期,证券代码,证券名称,业务名称,成交数量,成交价格\n20240102,600000,浦发银行,证券买入,100,10.00\n")
+	assert.NoError(t, err)
+
+	trades, _, err := ParseCSV(strings.NewReader(export), DefaultCSVFormat())
+	assert.NoError(t, err)
+	if assert.Len(t, trades, 1) {
+		assert.Equal(t, KindBuy, trades[0].Kind)
+		assert.Equal(t, "证券买入", trades[0].Note)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	_, _, err := ParseCSV(strings.NewReader("date,code\n"), DefaultCSVFormat())
+	assert.ErrorIs(t, err, ErrInvalidCSV)
+
+	export := "成交日期,证券代码,业务名称,成交数量,成交价格\n2024-01-02,600000,证券买入,100,10\n"
+	_, _, err = ParseCSV(strings.NewReader(export), DefaultCSVFormat())
+	assert.ErrorIs(t, err, ErrInvalidCSV)
+
+	export = "成交日期,证券代码,业务名称,成交数量,成交价格\n20240102,600000,证券买入,0,10\n"
+	_, _, err = ParseCSV(strings.NewReader(export), DefaultCSVFormat())
+	assert.ErrorIs(t, err, ErrInvalidTrade)
+}
+
+func TestTickerFromCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		ticker string
+	}{
+		{"600000", "1.600000"},
+		{"688001", "1.688001"},
+		{"510300", "1.510300"},
+		{"1", "0.000001"},
+		{"300750", "0.300750"},
+		{"830799", "0.830799"},
+		{"430047", "0.430047"},
+		{"920002", "0.920002"},
+		{"900901", "1.900901"},
+		{"0.000001", "0.000001"},
+	}
+	for _, tt := range tests {
+		ticker, err := TickerFromCode(tt.code)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.ticker, ticker)
+	}
+
+	_, err := TickerFromCode("60000A")
+	assert.ErrorIs(t, err, ErrInvalidTrade)
+}
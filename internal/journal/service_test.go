@@ -0,0 +1,126 @@
+//nolint:testpackage,lll //ignore
+package journal
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/stretchr/testify/assert"
+)
+
+func tradesOf() []Trade {
+	return []Trade{
+		{Ticker: "1.600000", Date: "2024-01-02", Kind: KindBuy, Shares: 100, Price: 10, Fees: 5},  //nolint:exhaustruct
+		{Ticker: "1.600000", Date: "2024-02-01", Kind: KindBuy, Shares: 100, Price: 12, Fees: 5},  //nolint:exhaustruct
+		{Ticker: "1.600000", Date: "2024-03-01", Kind: KindSell, Shares: 150, Price: 13, Fees: 6}, //nolint:exhaustruct
+		{Ticker: "1.600000", Date: "2024-03-15", Kind: KindDividend, Amount: 20},                  //nolint:exhaustruct
+		{Ticker: "1.600000", Date: "2024-03-20", Kind: KindFee, Fees: 2},                          //nolint:exhaustruct
+		{Ticker: "0.000001", Date: "2024-01-10", Kind: KindBuy, Shares: 200, Price: 5, Fees: 5},   //nolint:exhaustruct
+		{Ticker: "0.000001", Date: "2024-01-20", Kind: KindSell, Shares: 200, Price: 4, Fees: 5},  //nolint:exhaustruct
+	}
+}
+
+func TestReplayFIFO(t *testing.T) {
+	book, err := Replay(tradesOf(), MethodFIFO)
+	assert.NoError(t, err)
+
+	// Sell of 150 closes the first lot and half of the second, sell fees pro rata.
+	if assert.Len(t, book.Closed, 3) {
+		assert.Equal(t, "0.000001", book.Closed[0].Ticker)
+		assert.InDelta(t, 795.0-1005.0, book.Closed[0].Gain, 1e-9)
+		assert.Equal(t, 10, book.Closed[0].HoldDays)
+
+		first, second := book.Closed[1], book.Closed[2]
+		assert.InDelta(t, 100.0, first.Shares, 1e-9)
+		assert.InDelta(t, 1005.0, first.Cost, 1e-9)
+		assert.InDelta(t, 1296.0, first.Proceeds, 1e-9)
+		assert.InDelta(t, 291.0, first.Gain, 1e-9)
+		assert.Equal(t, 59, first.HoldDays)
+		assert.InDelta(t, 50.0, second.Shares, 1e-9)
+		assert.InDelta(t, 602.5, second.Cost, 1e-9)
+		assert.InDelta(t, 648.0, second.Proceeds, 1e-9)
+		assert.Equal(t, "2024-02-01", second.OpenDate)
+	}
+
+	if assert.Len(t, book.Positions, 2) {
+		// Open positions first.
+		open := book.Positions[0]
+		assert.Equal(t, "1.600000", open.Ticker)
+		assert.InDelta(t, 50.0, open.Shares, 1e-9)
+		assert.InDelta(t, 12.05, open.AvgCost, 1e-9)
+		assert.InDelta(t, 291.0+45.5-2.0, open.Realized, 1e-9)
+		assert.InDelta(t, 20.0, open.Dividends, 1e-9)
+		if assert.Len(t, open.Lots, 1) {
+			assert.InDelta(t, 50.0, open.Lots[0].Shares, 1e-9)
+		}
+		assert.InDelta(t, 0.0, book.Positions[1].Shares, 1e-9)
+	}
+	assert.InDelta(t, 5+5+6+2+5+5, book.Fees, 1e-9)
+	assert.InDelta(t, 20.0, book.Dividends, 1e-9)
+	assert.Equal(t, []string{"1.600000"}, book.OpenTickers())
+}
+
+func TestReplayAverage(t *testing.T) {
+	book, err := Replay(tradesOf(), MethodAverage)
+	assert.NoError(t, err)
+
+	// Both lots carry the pooled cost of (1005 + 1205) / 200.
+	open := book.Positions[0]
+	assert.InDelta(t, 11.05, open.AvgCost, 1e-9)
+	assert.InDelta(t, 50.0*11.05, open.CostBasis, 1e-9)
+	assert.InDelta(t, 1944.0-150*11.05-2.0, open.Realized, 1e-9)
+	// Holding periods still follow the lots.
+	assert.Equal(t, "2024-01-02", book.Closed[1].OpenDate)
+	assert.Equal(t, "2024-02-01", book.Closed[2].OpenDate)
+}
+
+func TestReplayOversold(t *testing.T) {
+	trades := []Trade{
+		{Ticker: "1.600000", Date: "2024-01-02", Kind: KindBuy, Shares: 100, Price: 10},  //nolint:exhaustruct
+		{Ticker: "1.600000", Date: "2024-01-01", Kind: KindSell, Shares: 100, Price: 10}, //nolint:exhaustruct
+	}
+	_, err := Replay(trades, MethodFIFO)
+	assert.ErrorIs(t, err, ErrOversold)
+
+	_, err = Replay(nil, "lifo")
+	assert.ErrorIs(t, err, ErrInvalidTrade)
+}
+
+func TestBookMark(t *testing.T) {
+	book, err := Replay(tradesOf(), MethodFIFO)
+	assert.NoError(t, err)
+
+	s := stock.NewEmptyStock()
+	s.Ticker, s.Name, s.Sector = "1.600000", "浦发银行", "银行"
+	d := stock.NewEmptyDailyData()
+	d.Date, d.Close = "2024-04-01 00:00:00.000Z", 14.05
+
+	marked := book.Mark(map[string]stock.Stock{s.Ticker: s}, map[string]stock.DailyData{s.Ticker: d})
+	open := marked.Positions[0]
+	assert.Equal(t, "浦发银行", open.Name)
+	assert.Equal(t, "银行", open.Sector)
+	assert.InDelta(t, 702.5, open.Value, 1e-9)
+	assert.InDelta(t, 100.0, open.Unrealized, 1e-9)
+	assert.InDelta(t, 100.0*100/602.5, open.UnrealizedPct, 1e-9)
+	assert.InDelta(t, 100.0, marked.Unrealized, 1e-9)
+	// The book passed in is left as is.
+	assert.InDelta(t, 602.5, book.Positions[0].Value, 1e-9)
+}
+
+func TestComputeHoldingStats(t *testing.T) {
+	stats := ComputeHoldingStats([]ClosedLot{
+		{Gain: 10, Return: 10, HoldDays: 5},  //nolint:exhaustruct
+		{Gain: -5, Return: -5, HoldDays: 20}, //nolint:exhaustruct
+		{Gain: 3, Return: 3, HoldDays: 8},    //nolint:exhaustruct
+		{Gain: 0, Return: 0, HoldDays: 1},    //nolint:exhaustruct
+	})
+	assert.Equal(t, 4, stats.Closed)
+	assert.Equal(t, 2, stats.Wins)
+	assert.InDelta(t, 50.0, stats.WinRate, 1e-9)
+	assert.InDelta(t, 2.0, stats.AvgReturn, 1e-9)
+	assert.InDelta(t, 8.5, stats.AvgHoldDays, 1e-9)
+	assert.InDelta(t, 6.5, stats.MedianHoldDays, 1e-9)
+	assert.Equal(t, 20, stats.MaxHoldDays)
+	assert.InDelta(t, 6.5, stats.AvgWinHoldDays, 1e-9)
+	assert.InDelta(t, 10.5, stats.AvgLossHoldDays, 1e-9)
+}
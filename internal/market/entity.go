@@ -0,0 +1,58 @@
+//nolint:gomnd //ignore
+package market
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidMarket = errors.New("invalid market")
+
+// Market holds the trading rules fills are subject to; zero values turn a rule off.
+// Limits are daily price limits in percent from the previous close by board, fees are
+// rates of the traded value: commission on both sides with a minimum per order,
+// transfer fee on both sides and stamp duty on sells.
+type Market struct {
+	T1             bool    `json:"t1"`
+	LotSize        float64 `json:"lotsize"`
+	LimitMain      float64 `json:"limitmain"`
+	LimitGrowth    float64 `json:"limitgrowth"`
+	LimitBSE       float64 `json:"limitbse"`
+	LimitST        float64 `json:"limitst"`
+	CommissionRate float64 `json:"commissionrate"`
+	CommissionMin  float64 `json:"commissionmin"`
+	TransferRate   float64 `json:"transferrate"`
+	StampDutyRate  float64 `json:"stampdutyrate"`
+}
+
+// DefaultMarket returns A-share rules: T+1, lots of 100, limits of 10% on main boards,
+// 20% on STAR and ChiNext, 30% on Beijing and 5% for ST, commission 0.025% at least 5,
+// transfer fee 0.001% and stamp duty 0.05%.
+func DefaultMarket() Market {
+	return Market{
+		T1:             true,
+		LotSize:        100.0,
+		LimitMain:      10.0,
+		LimitGrowth:    20.0,
+		LimitBSE:       30.0,
+		LimitST:        5.0,
+		CommissionRate: 0.00025,
+		CommissionMin:  5.0,
+		TransferRate:   0.00001,
+		StampDutyRate:  0.0005,
+	}
+}
+
+// Validate checks no rule is negative.
+func (m Market) Validate() error {
+	values := []float64{
+		m.LotSize, m.LimitMain, m.LimitGrowth, m.LimitBSE, m.LimitST,
+		m.CommissionRate, m.CommissionMin, m.TransferRate, m.StampDutyRate,
+	}
+	for _, v := range values {
+		if v < 0.0 {
+			return fmt.Errorf("%w: rules must not be negative", ErrInvalidMarket)
+		}
+	}
+	return nil
+}
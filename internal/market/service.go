@@ -0,0 +1,99 @@
+package market
+
+import (
+	"math"
+	"strings"
+
+	"example.com/stocker-back/internal/stock"
+)
+
+// LimitPct returns the daily price limit of s in percent, 0 if unlimited. The board
+// is told by the code of the ticker, e.g. "1.688001": 688/689 STAR and 300/301
+// ChiNext take LimitGrowth, 4/8/92 Beijing LimitBSE; other boards take LimitST when
+// the name starts with ST or *ST, LimitMain otherwise.
+func (m Market) LimitPct(s stock.Stock) float64 {
+	code := s.Ticker[strings.LastIndex(s.Ticker, ".")+1:]
+
+	switch {
+	case hasAnyPrefix(code, "688", "689", "300", "301"):
+		return m.LimitGrowth
+	case hasAnyPrefix(code, "4", "8", "92"):
+		return m.LimitBSE
+	case hasAnyPrefix(strings.ToUpper(s.Name), "ST", "*ST"):
+		return m.LimitST
+	default:
+		return m.LimitMain
+	}
+}
+
+// LimitPrices returns limit-up and limit-down prices of s from the previous close,
+// rounded to the cent; ok is false without a limit.
+func (m Market) LimitPrices(s stock.Stock, prevClose float64) (up, down float64, ok bool) {
+	pct := m.LimitPct(s)
+	if pct <= 0.0 || prevClose <= 0.0 {
+		return 0.0, 0.0, false
+	}
+	return roundCent(prevClose * (1.0 + pct/100.0)), roundCent(prevClose * (1.0 - pct/100.0)), true
+}
+
+// CanBuy tells whether a buy fills at price, i.e. price is below limit-up.
+func (m Market) CanBuy(s stock.Stock, prevClose, price float64) bool {
+	up, _, ok := m.LimitPrices(s, prevClose)
+	return !ok || price < up
+}
+
+// CanSell tells whether a sell fills at price, i.e. price is above limit-down.
+func (m Market) CanSell(s stock.Stock, prevClose, price float64) bool {
+	_, down, ok := m.LimitPrices(s, prevClose)
+	return !ok || price > down
+}
+
+// BuyFees returns commission and transfer fee of buying value.
+func (m Market) BuyFees(value float64) float64 {
+	return m.commission(value) + value*m.TransferRate
+}
+
+// SellFees returns commission, transfer fee and stamp duty of selling value.
+func (m Market) SellFees(value float64) float64 {
+	return m.commission(value) + value*m.TransferRate + value*m.StampDutyRate
+}
+
+// Shares returns the most shares, in whole lots, buyable at price with budget fees included.
+func (m Market) Shares(budget, price float64) float64 {
+	if price <= 0.0 || budget <= 0.0 {
+		return 0.0
+	}
+
+	shares := budget / price
+	if m.LotSize <= 0.0 {
+		// Fractional shares; scale down so fees fit the budget.
+		cost := shares*price + m.BuyFees(shares*price)
+		return shares * budget / cost
+	}
+
+	shares = math.Floor(shares/m.LotSize) * m.LotSize
+	for shares > 0.0 && shares*price+m.BuyFees(shares*price) > budget {
+		shares -= m.LotSize
+	}
+	return shares
+}
+
+func (m Market) commission(value float64) float64 {
+	if m.CommissionRate <= 0.0 && m.CommissionMin <= 0.0 {
+		return 0.0
+	}
+	return math.Max(value*m.CommissionRate, m.CommissionMin)
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func roundCent(price float64) float64 {
+	return math.Round(price*100.0) / 100.0
+}
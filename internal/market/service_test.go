@@ -0,0 +1,75 @@
+//nolint:testpackage,lll //ignore
+package market
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarketLimits(t *testing.T) {
+	market := DefaultMarket()
+	newStock := func(ticker, name string) stock.Stock {
+		s := stock.NewEmptyStock()
+		s.Ticker, s.Name = ticker, name
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		stock stock.Stock
+		pct   float64
+		up    float64
+		down  float64
+	}{
+		{"main shanghai", newStock("1.600000", "浦发银行"), 10, 11, 9},
+		{"main shenzhen", newStock("0.000001", "平安银行"), 10, 11, 9},
+		{"star", newStock("1.688001", "华兴源创"), 20, 12, 8},
+		{"chinext", newStock("0.300750", "宁德时代"), 20, 12, 8},
+		{"beijing", newStock("0.830799", "艾融软件"), 30, 13, 7},
+		{"st", newStock("0.000004", "*ST国华"), 5, 10.5, 9.5},
+		{"st chinext", newStock("0.300023", "ST宝德"), 20, 12, 8},
+		{"st within name", newStock("1.600003", "东方STAR"), 10, 11, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.pct, market.LimitPct(tt.stock), 1e-9)
+			up, down, ok := market.LimitPrices(tt.stock, 10)
+			assert.True(t, ok)
+			assert.InDelta(t, tt.up, up, 1e-9)
+			assert.InDelta(t, tt.down, down, 1e-9)
+			assert.False(t, market.CanBuy(tt.stock, 10, tt.up))
+			assert.True(t, market.CanBuy(tt.stock, 10, tt.up-0.01))
+			assert.False(t, market.CanSell(tt.stock, 10, tt.down))
+			assert.True(t, market.CanSell(tt.stock, 10, tt.down+0.01))
+		})
+	}
+
+	// Limits turned off.
+	s := newStock("1.600000", "浦发银行")
+	_, _, ok := Market{}.LimitPrices(s, 10) //nolint:exhaustruct
+	assert.False(t, ok)
+	assert.True(t, Market{}.CanBuy(s, 10, 100)) //nolint:exhaustruct
+}
+
+func TestMarketFees(t *testing.T) {
+	market := DefaultMarket()
+
+	// Commission at its minimum on small orders.
+	assert.InDelta(t, 5.0+0.01, market.BuyFees(1000), 1e-9)
+	assert.InDelta(t, 25.0+1.0, market.BuyFees(100000), 1e-9)
+	assert.InDelta(t, 25.0+1.0+50.0, market.SellFees(100000), 1e-9)
+	assert.InDelta(t, 0.0, Market{}.SellFees(100000), 1e-9) //nolint:exhaustruct
+}
+
+func TestMarketShares(t *testing.T) {
+	market := DefaultMarket()
+
+	assert.InDelta(t, 8300.0, market.Shares(100000, 12), 1e-9)
+	// The last lot does not fit once fees are added.
+	assert.InDelta(t, 900.0, market.Shares(10000, 10), 1e-9)
+	assert.InDelta(t, 0.0, market.Shares(1000, 12), 1e-9)
+	assert.InDelta(t, 1000.0/12.0, Market{}.Shares(1000, 12), 1e-9) //nolint:exhaustruct
+}
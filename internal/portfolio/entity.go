@@ -0,0 +1,151 @@
+//nolint:gomnd //ignore
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+
+	"example.com/stocker-back/internal/market"
+)
+
+var (
+	ErrInvalidAccount = errors.New("invalid portfolio account")
+	ErrInvalidOrder   = errors.New("invalid portfolio order")
+)
+
+// Account is entity of a paper-trading account holding Cash out of the starting
+// Capital, trading under Market rules, A-share if unset. With Screen set, the account
+// is signal-driven: new hits of that saved screen, "" being the default daily screen,
+// are bought with an equal slot of the equity each, up to Slots positions.
+type Account struct {
+	Name    string         `json:"name"`
+	Capital float64        `json:"capital"`
+	Cash    float64        `json:"cash"`
+	Market  *market.Market `json:"market,omitempty"`
+	Screen  *string        `json:"screen,omitempty"`
+	Slots   int            `json:"slots"`
+	Created string         `json:"created"`
+}
+
+// WithDefaults fills unset market with market.DefaultMarket and unset slots of a
+// signal-driven account with 10.
+func (a Account) WithDefaults() Account {
+	if a.Market == nil {
+		defaults := market.DefaultMarket()
+		a.Market = &defaults
+	}
+	if a.Screen != nil && a.Slots == 0 {
+		a.Slots = 10
+	}
+	return a
+}
+
+// Validate checks the account is named with positive capital and valid market rules.
+func (a Account) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAccount)
+	}
+	if a.Capital <= 0.0 {
+		return fmt.Errorf("%w: capital must be positive", ErrInvalidAccount)
+	}
+	if a.Slots < 0 {
+		return fmt.Errorf("%w: slots must not be negative", ErrInvalidAccount)
+	}
+	if a.Market != nil {
+		if err := a.Market.Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidAccount, err)
+		}
+	}
+	return nil
+}
+
+// Side is the direction of an order.
+type Side string
+
+const (
+	SideBuy  Side = "buy"
+	SideSell Side = "sell"
+)
+
+// OrderStatus is the state of an order.
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderFilled    OrderStatus = "filled"
+	OrderRejected  OrderStatus = "rejected"
+	OrderCancelled OrderStatus = "cancelled"
+)
+
+// Source tells who placed an order.
+type Source string
+
+const (
+	SourceManual Source = "manual"
+	SourceSignal Source = "signal"
+)
+
+// Order is entity of a paper order, a day order filled at the open of the first daily
+// data of Ticker dated after the day it was placed, or rejected with Reason. A buy is
+// of Shares in whole lots or, with Amount instead, of the most lots Amount pays for,
+// fees included; a sell is of Shares, odd lots allowed. Realized is the profit of a
+// filled sell over the average cost, net of fees.
+type Order struct {
+	ID       string      `json:"id"`
+	Account  string      `json:"account"`
+	Ticker   string      `json:"ticker"`
+	Side     Side        `json:"side"`
+	Shares   float64     `json:"shares"`
+	Amount   float64     `json:"amount"`
+	Source   Source      `json:"source"`
+	Status   OrderStatus `json:"status"`
+	Reason   string      `json:"reason"`
+	PlacedAt string      `json:"placedat"`
+	FilledAt string      `json:"filledat"`
+	Price    float64     `json:"price"`
+	Fees     float64     `json:"fees"`
+	Realized float64     `json:"realized"`
+}
+
+// Position is entity of the holding of Ticker in Account. AvgCost is per share with buy
+// fees included; LastBuy is the date of the last filled buy, for T+1; Realized sums
+// the profit of sells since the position was opened.
+type Position struct {
+	Account  string  `json:"account"`
+	Ticker   string  `json:"ticker"`
+	Shares   float64 `json:"shares"`
+	AvgCost  float64 `json:"avgcost"`
+	OpenedAt string  `json:"openedat"`
+	LastBuy  string  `json:"lastbuy"`
+	Realized float64 `json:"realized"`
+}
+
+// Holding is valueobject of a position marked at Price of Date. Unrealized is over the
+// average cost, UnrealizedPct in percent of it; Weight is the percentage of equity.
+type Holding struct {
+	Ticker        string  `json:"ticker"`
+	Name          string  `json:"name"`
+	Shares        float64 `json:"shares"`
+	AvgCost       float64 `json:"avgcost"`
+	Price         float64 `json:"price"`
+	Date          string  `json:"date"`
+	Value         float64 `json:"value"`
+	Unrealized    float64 `json:"unrealized"`
+	UnrealizedPct float64 `json:"unrealizedpct"`
+	Realized      float64 `json:"realized"`
+	Weight        float64 `json:"weight"`
+}
+
+// Valuation is valueobject of an account marked to market. Realized sums filled sells
+// over the life of the account, Return is of Equity over Capital in percent.
+type Valuation struct {
+	Account    string    `json:"account"`
+	Capital    float64   `json:"capital"`
+	Cash       float64   `json:"cash"`
+	Value      float64   `json:"value"`
+	Equity     float64   `json:"equity"`
+	Realized   float64   `json:"realized"`
+	Unrealized float64   `json:"unrealized"`
+	Return     float64   `json:"return"`
+	Holdings   []Holding `json:"holdings"`
+}
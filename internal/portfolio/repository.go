@@ -0,0 +1,23 @@
+package portfolio
+
+type Repository interface {
+	GetAccounts() ([]Account, error)
+	GetAccountByName(name string) (Account, error)
+	CreateAccount(account Account) error
+	// DeleteAccountByName deletes the account along with its orders and positions.
+	DeleteAccountByName(name string) error
+
+	GetPositions(account string) ([]Position, error)
+
+	// GetOrders gets orders of account, latest placed first.
+	GetOrders(account string) ([]Order, error)
+	GetOrdersPending() ([]Order, error)
+	GetOrderByID(id string) (Order, error)
+	// CreateOrder saves a new order and returns it with its ID.
+	CreateOrder(order Order) (Order, error)
+	UpdateOrder(order Order) error
+
+	// SaveFill saves a filled order with the account and position it changed at once;
+	// a position without shares is deleted.
+	SaveFill(account Account, position Position, order Order) error
+}
@@ -0,0 +1,231 @@
+package portfolio
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"example.com/stocker-back/internal/common"
+	"example.com/stocker-back/internal/market"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// Validate checks the order has a ticker, a side and a size fitting the market rules: buys of
+// either whole lots or an amount, sells of shares.
+func (o Order) Validate(rules market.Market) error {
+	if o.Ticker == "" {
+		return fmt.Errorf("%w: ticker is required", ErrInvalidOrder)
+	}
+
+	switch o.Side {
+	case SideBuy:
+		if (o.Shares > 0.0) == (o.Amount > 0.0) {
+			return fmt.Errorf("%w: buy needs either shares or amount", ErrInvalidOrder)
+		}
+		if o.Shares > 0.0 && rules.LotSize > 0.0 && math.Mod(o.Shares, rules.LotSize) != 0.0 {
+			return fmt.Errorf("%w: buy shares must be whole lots of %g", ErrInvalidOrder, rules.LotSize)
+		}
+	case SideSell:
+		if o.Shares <= 0.0 || o.Amount != 0.0 {
+			return fmt.Errorf("%w: sell needs shares", ErrInvalidOrder)
+		}
+	default:
+		return fmt.Errorf("%w: unknown side %q", ErrInvalidOrder, o.Side)
+	}
+
+	return nil
+}
+
+// NextBar returns the first of daily data in ascending date dated after the day of
+// placedAt, with the close before it, 0 if none; ok is false until it is stored.
+func NextBar(dailyData []stock.DailyData, placedAt string) (bar stock.DailyData, prevClose float64, ok bool) {
+	idx := slices.IndexFunc(dailyData, func(d stock.DailyData) bool {
+		return common.Day(d.Date) > common.Day(placedAt)
+	})
+	if idx < 0 {
+		return stock.NewEmptyDailyData(), 0.0, false
+	}
+	if idx > 0 {
+		prevClose = dailyData[idx-1].Close
+	}
+	return dailyData[idx], prevClose, true
+}
+
+// Fill executes a pending order at the open of bar under the market rules of account,
+// returning the account, the position of the ticker, empty if none, and the order
+// as changed. An order that cannot fill is rejected with the reason: limit-up for
+// buys, limit-down or T+1 for sells, lacking cash or shares.
+func Fill(account Account, position Position, order Order, s stock.Stock, prevClose float64, bar stock.DailyData) (Account, Position, Order) { //nolint:lll
+	rules := *account.WithDefaults().Market
+	price := bar.Open
+	order.FilledAt = bar.Date
+
+	reject := func(reason string) (Account, Position, Order) {
+		order.Status, order.Reason = OrderRejected, reason
+		return account, position, order
+	}
+	if price <= 0.0 {
+		return reject("no price")
+	}
+
+	switch order.Side {
+	case SideBuy:
+		if !rules.CanBuy(s, prevClose, price) {
+			return reject("limit-up")
+		}
+		shares := order.Shares
+		if order.Amount > 0.0 {
+			shares = rules.Shares(math.Min(order.Amount, account.Cash), price)
+		}
+		fees := rules.BuyFees(shares * price)
+		if shares <= 0.0 || shares*price+fees > account.Cash {
+			return reject("insufficient cash")
+		}
+
+		if position.Shares == 0.0 {
+			position = Position{
+				Account:  account.Name,
+				Ticker:   order.Ticker,
+				Shares:   0.0,
+				AvgCost:  0.0,
+				OpenedAt: bar.Date,
+				LastBuy:  "",
+				Realized: 0.0,
+			}
+		}
+		position.AvgCost = (position.Shares*position.AvgCost + shares*price + fees) / (position.Shares + shares)
+		position.Shares += shares
+		position.LastBuy = bar.Date
+		account.Cash -= shares*price + fees
+		order.Shares, order.Fees = shares, fees
+
+	case SideSell:
+		if rules.T1 && common.Day(position.LastBuy) >= common.Day(bar.Date) {
+			return reject("T+1")
+		}
+		if order.Shares > position.Shares {
+			return reject("insufficient shares")
+		}
+		if !rules.CanSell(s, prevClose, price) {
+			return reject("limit-down")
+		}
+		value := order.Shares * price
+		fees := rules.SellFees(value)
+		order.Fees = fees
+		order.Realized = value - fees - order.Shares*position.AvgCost
+		position.Shares -= order.Shares
+		position.Realized += order.Realized
+		account.Cash += value - fees
+	}
+
+	order.Status, order.Price = OrderFilled, price
+	return account, position, order
+}
+
+// SignalOrders returns buys of a signal-driven account for hits, in their order, that
+// are neither held nor pending, each of an equal slot of equity, up to the free slots.
+func SignalOrders(account Account, positions []Position, pending []Order, hits []string, equity float64) []Order {
+	account = account.WithDefaults()
+	if account.Screen == nil || account.Slots == 0 {
+		return nil
+	}
+
+	taken := lo.SliceToMap(positions, func(p Position) (string, bool) { return p.Ticker, true })
+	pendingBuys := 0
+	for _, o := range pending {
+		if o.Side == SideBuy {
+			taken[o.Ticker] = true
+			pendingBuys++
+		}
+	}
+	free := account.Slots - len(positions) - pendingBuys
+
+	orders := make([]Order, 0)
+	for _, ticker := range lo.Uniq(hits) {
+		if len(orders) >= free {
+			break
+		}
+		if taken[ticker] {
+			continue
+		}
+		orders = append(orders, Order{ //nolint:exhaustruct
+			Account: account.Name,
+			Ticker:  ticker,
+			Side:    SideBuy,
+			Amount:  equity / float64(account.Slots),
+			Source:  SourceSignal,
+			Status:  OrderPending,
+		})
+	}
+	return orders
+}
+
+// Value marks positions of account at the last daily data of each ticker, at the
+// average cost if there is none, and sums realized profit of filled orders. names
+// give the stock names of holdings.
+func Value(account Account, positions []Position, orders []Order, last map[string]stock.DailyData, names map[string]string) Valuation { //nolint:lll
+	valuation := Valuation{
+		Account:    account.Name,
+		Capital:    account.Capital,
+		Cash:       account.Cash,
+		Value:      0.0,
+		Equity:     0.0,
+		Realized:   0.0,
+		Unrealized: 0.0,
+		Return:     0.0,
+		Holdings:   make([]Holding, 0, len(positions)),
+	}
+
+	for _, p := range positions {
+		h := Holding{
+			Ticker:        p.Ticker,
+			Name:          names[p.Ticker],
+			Shares:        p.Shares,
+			AvgCost:       p.AvgCost,
+			Price:         p.AvgCost,
+			Date:          "",
+			Value:         0.0,
+			Unrealized:    0.0,
+			UnrealizedPct: 0.0,
+			Realized:      p.Realized,
+			Weight:        0.0,
+		}
+		if d, ok := last[p.Ticker]; ok && d.Close > 0.0 {
+			h.Price, h.Date = d.Close, d.Date
+		}
+		h.Value = h.Shares * h.Price
+		h.Unrealized = h.Shares * (h.Price - h.AvgCost)
+		if h.AvgCost > 0.0 {
+			h.UnrealizedPct = 100.0 * (h.Price/h.AvgCost - 1.0)
+		}
+		valuation.Value += h.Value
+		valuation.Unrealized += h.Unrealized
+		valuation.Holdings = append(valuation.Holdings, h)
+	}
+
+	valuation.Equity = valuation.Cash + valuation.Value
+	valuation.Realized = lo.SumBy(orders, func(o Order) float64 {
+		if o.Status != OrderFilled {
+			return 0.0
+		}
+		return o.Realized
+	})
+	if account.Capital > 0.0 {
+		valuation.Return = 100.0 * (valuation.Equity/account.Capital - 1.0)
+	}
+	for idx := range valuation.Holdings {
+		if valuation.Equity > 0.0 {
+			valuation.Holdings[idx].Weight = 100.0 * valuation.Holdings[idx].Value / valuation.Equity
+		}
+	}
+	slices.SortStableFunc(valuation.Holdings, func(a, b Holding) int {
+		if c := cmp.Compare(b.Value, a.Value); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Ticker, b.Ticker)
+	})
+
+	return valuation
+}
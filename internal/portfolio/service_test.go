@@ -0,0 +1,158 @@
+//nolint:testpackage,lll //ignore
+package portfolio
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/market"
+	"example.com/stocker-back/internal/stock"
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func barOf(idx int, open, closePrice float64) stock.DailyData {
+	d := stock.NewEmptyDailyData()
+	d.Date, d.Open, d.Close = stocktest.Date(idx), open, closePrice
+	return d
+}
+
+func stockOf(ticker string) stock.Stock {
+	s := stock.NewEmptyStock()
+	s.Ticker = ticker
+	return s
+}
+
+func TestOrderValidate(t *testing.T) {
+	rules := market.DefaultMarket()
+	tests := []struct {
+		name  string
+		order Order
+		ok    bool
+	}{
+		{"buy lots", Order{Ticker: "1.600000", Side: SideBuy, Shares: 200}, true},
+		{"buy amount", Order{Ticker: "1.600000", Side: SideBuy, Amount: 10000}, true},
+		{"buy odd lot", Order{Ticker: "1.600000", Side: SideBuy, Shares: 150}, false},
+		{"buy both", Order{Ticker: "1.600000", Side: SideBuy, Shares: 100, Amount: 10000}, false},
+		{"sell odd lot", Order{Ticker: "1.600000", Side: SideSell, Shares: 50}, true},
+		{"sell amount", Order{Ticker: "1.600000", Side: SideSell, Amount: 10000}, false},
+		{"no side", Order{Ticker: "1.600000", Shares: 100}, false},
+		{"no ticker", Order{Side: SideBuy, Shares: 100}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.order.Validate(rules)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidOrder)
+			}
+		})
+	}
+}
+
+func TestNextBar(t *testing.T) {
+	daily := []stock.DailyData{barOf(1, 10, 10.5), barOf(2, 10.6, 11), barOf(3, 11, 11.2)}
+
+	bar, prevClose, ok := NextBar(daily, "2024-01-03 06:30:00.000Z")
+	assert.True(t, ok)
+	assert.Equal(t, daily[2].Date, bar.Date)
+	assert.InDelta(t, 11.0, prevClose, 1e-9)
+
+	_, _, ok = NextBar(daily, "2024-01-04 08:00:00.000Z")
+	assert.False(t, ok)
+}
+
+func TestFill(t *testing.T) {
+	rules := market.DefaultMarket()
+	account := Account{Name: "paper", Capital: 100000, Cash: 100000, Market: &rules} //nolint:exhaustruct
+	s := stockOf("1.600000")
+
+	// Buy by amount in whole lots, fees in the average cost.
+	buy := Order{Account: "paper", Ticker: s.Ticker, Side: SideBuy, Amount: 50000, Status: OrderPending} //nolint:exhaustruct
+	account, position, buy := Fill(account, Position{}, buy, s, 10, barOf(1, 10, 10.5))                  //nolint:exhaustruct
+	assert.Equal(t, OrderFilled, buy.Status)
+	assert.InDelta(t, 4900.0, buy.Shares, 1e-9)
+	assert.InDelta(t, 12.25+0.49, buy.Fees, 1e-9)
+	assert.InDelta(t, 100000-49000-buy.Fees, account.Cash, 1e-9)
+	assert.InDelta(t, (49000+buy.Fees)/4900, position.AvgCost, 1e-9)
+
+	// Sell on the day of the buy is rejected under T+1.
+	sell := Order{Account: "paper", Ticker: s.Ticker, Side: SideSell, Shares: 2000, Status: OrderPending} //nolint:exhaustruct
+	_, _, rejected := Fill(account, position, sell, s, 10, barOf(1, 10, 10.5))
+	assert.Equal(t, OrderRejected, rejected.Status)
+	assert.Equal(t, "T+1", rejected.Reason)
+
+	// Sell at limit-down is rejected.
+	_, _, rejected = Fill(account, position, sell, s, 10.5, barOf(2, 9.45, 9.45))
+	assert.Equal(t, "limit-down", rejected.Reason)
+
+	// Partial sell realizes profit over the average cost.
+	cash := account.Cash
+	account, position, sell = Fill(account, position, sell, s, 10.5, barOf(2, 11, 11))
+	assert.Equal(t, OrderFilled, sell.Status)
+	assert.InDelta(t, 5.5+0.22+11.0, sell.Fees, 1e-9)
+	assert.InDelta(t, 22000-sell.Fees-2000*position.AvgCost, sell.Realized, 1e-9)
+	assert.InDelta(t, cash+22000-sell.Fees, account.Cash, 1e-9)
+	assert.InDelta(t, 2900.0, position.Shares, 1e-9)
+	assert.InDelta(t, sell.Realized, position.Realized, 1e-9)
+
+	// Selling more than held and buying beyond cash are rejected.
+	sell.Shares, sell.Status = 3000, OrderPending
+	_, _, rejected = Fill(account, position, sell, s, 11, barOf(3, 11, 11))
+	assert.Equal(t, "insufficient shares", rejected.Reason)
+	buy = Order{Account: "paper", Ticker: s.Ticker, Side: SideBuy, Shares: 10000, Status: OrderPending} //nolint:exhaustruct
+	_, _, rejected = Fill(account, position, buy, s, 11, barOf(3, 11, 11))
+	assert.Equal(t, "insufficient cash", rejected.Reason)
+
+	// Buy at limit-up is rejected.
+	buy.Shares = 100
+	_, _, rejected = Fill(account, position, buy, s, 11, barOf(3, 12.1, 12.1))
+	assert.Equal(t, "limit-up", rejected.Reason)
+}
+
+func TestSignalOrders(t *testing.T) {
+	screen := ""
+	account := Account{Name: "signal", Capital: 100000, Cash: 100000, Screen: &screen, Slots: 3} //nolint:exhaustruct
+	positions := []Position{{Ticker: "a", Shares: 100}}                                          //nolint:exhaustruct
+	pending := []Order{{Ticker: "b", Side: SideBuy}}                                             //nolint:exhaustruct
+
+	orders := SignalOrders(account, positions, pending, []string{"a", "b", "c", "d"}, 90000)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, "c", orders[0].Ticker)
+		assert.InDelta(t, 30000.0, orders[0].Amount, 1e-9)
+		assert.Equal(t, SourceSignal, orders[0].Source)
+	}
+
+	account.Screen = nil
+	assert.Empty(t, SignalOrders(account, nil, nil, []string{"a"}, 90000))
+}
+
+func TestValue(t *testing.T) {
+	account := Account{Name: "paper", Capital: 10000, Cash: 4000} //nolint:exhaustruct
+	positions := []Position{
+		{Ticker: "a", Shares: 100, AvgCost: 20, Realized: 50}, //nolint:exhaustruct
+		{Ticker: "b", Shares: 100, AvgCost: 10},               //nolint:exhaustruct
+	}
+	orders := []Order{
+		{Status: OrderFilled, Realized: 50},   //nolint:exhaustruct
+		{Status: OrderFilled, Realized: -20},  //nolint:exhaustruct
+		{Status: OrderRejected, Realized: 99}, //nolint:exhaustruct
+	}
+	last := map[string]stock.DailyData{"a": barOf(4, 25, 25)}
+
+	v := Value(account, positions, orders, last, map[string]string{"a": "A"})
+	assert.InDelta(t, 3500.0, v.Value, 1e-9)
+	assert.InDelta(t, 7500.0, v.Equity, 1e-9)
+	assert.InDelta(t, 500.0, v.Unrealized, 1e-9)
+	assert.InDelta(t, 30.0, v.Realized, 1e-9)
+	assert.InDelta(t, -25.0, v.Return, 1e-9)
+	if assert.Len(t, v.Holdings, 2) {
+		assert.Equal(t, "A", v.Holdings[0].Name)
+		assert.InDelta(t, 25.0, v.Holdings[0].UnrealizedPct, 1e-9)
+		assert.InDelta(t, 100.0*2500/7500, v.Holdings[0].Weight, 1e-9)
+		// Without daily data marked at cost.
+		assert.InDelta(t, 10.0, v.Holdings[1].Price, 1e-9)
+		assert.Equal(t, "", v.Holdings[1].Date)
+	}
+}
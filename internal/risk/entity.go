@@ -0,0 +1,155 @@
+//nolint:gomnd //ignore
+package risk
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidParams    = errors.New("invalid risk params")
+	ErrNoHoldings       = errors.New("no holdings to assess")
+	ErrInsufficientData = errors.New("insufficient daily data for risk")
+)
+
+// Params of a risk assessment: Benchmark is the index ticker in `daily` beta is taken
+// against, Lookback the trading days of returns used, Confidence the level of VaR and
+// expected shortfall, e.g. 0.95, and TradingDays per year annualizing volatility.
+type Params struct {
+	Benchmark   string  `json:"benchmark"`
+	Lookback    int     `json:"lookback"`
+	Confidence  float64 `json:"confidence"`
+	TradingDays int     `json:"tradingdays"`
+}
+
+// DefaultParams returns the CSI 300 as benchmark, a year of returns and 95% confidence.
+func DefaultParams() Params {
+	return Params{
+		Benchmark:   "1.000300",
+		Lookback:    252,
+		Confidence:  0.95,
+		TradingDays: 252,
+	}
+}
+
+// WithDefaults fills zero params with DefaultParams.
+func (p Params) WithDefaults() Params {
+	defaults := DefaultParams()
+	if p.Benchmark == "" {
+		p.Benchmark = defaults.Benchmark
+	}
+	if p.Lookback == 0 {
+		p.Lookback = defaults.Lookback
+	}
+	if p.Confidence == 0.0 {
+		p.Confidence = defaults.Confidence
+	}
+	if p.TradingDays == 0 {
+		p.TradingDays = defaults.TradingDays
+	}
+	return p
+}
+
+// Validate checks the lookback covers the longest VaR horizon and confidence is a level.
+func (p Params) Validate() error {
+	if p.Lookback < MinDays {
+		return fmt.Errorf("%w: lookback must be at least %d", ErrInvalidParams, MinDays)
+	}
+	if p.Confidence <= 0.5 || p.Confidence >= 1.0 {
+		return fmt.Errorf("%w: confidence must be within (0.5, 1)", ErrInvalidParams)
+	}
+	if p.TradingDays <= 0 {
+		return fmt.Errorf("%w: tradingdays must be positive", ErrInvalidParams)
+	}
+	return nil
+}
+
+// Horizons are the days VaR and expected shortfall are given for.
+var Horizons = []int{1, 10}
+
+// MinDays is the fewest daily returns a report is computed from.
+const MinDays = 20
+
+// Method is how VaR is estimated.
+type Method string
+
+const (
+	// MethodHistorical takes quantiles of past portfolio returns, compounded over
+	// overlapping windows for horizons beyond a day.
+	MethodHistorical Method = "historical"
+	// MethodParametric assumes normal returns of the past mean and volatility, scaled
+	// by the square root of the horizon.
+	MethodParametric Method = "parametric"
+)
+
+// Holding is valueobject of a position assessed, valued at market.
+type Holding struct {
+	Ticker string  `json:"ticker"`
+	Name   string  `json:"name"`
+	Sector string  `json:"sector"`
+	Value  float64 `json:"value"`
+}
+
+// Exposure is valueobject of the holdings of a sector, Weight in percent of value.
+type Exposure struct {
+	Sector  string   `json:"sector"`
+	Tickers []string `json:"tickers"`
+	Value   float64  `json:"value"`
+	Weight  float64  `json:"weight"`
+}
+
+// Concentration is valueobject of single-name concentration, weights in percent.
+// HHI is the Herfindahl index of weights as fractions, Effective its inverse, the
+// number of equal holdings as concentrated.
+type Concentration struct {
+	Largest       string  `json:"largest"`
+	LargestWeight float64 `json:"largestweight"`
+	Top5Weight    float64 `json:"top5weight"`
+	HHI           float64 `json:"hhi"`
+	Effective     float64 `json:"effective"`
+}
+
+// HoldingRisk is valueobject of a holding's risk: Weight in percent of all holdings,
+// Volatility annualized in percent, nil if left out of returns, Beta against the
+// benchmark, nil without it.
+type HoldingRisk struct {
+	Ticker     string   `json:"ticker"`
+	Name       string   `json:"name"`
+	Sector     string   `json:"sector"`
+	Weight     float64  `json:"weight"`
+	Volatility *float64 `json:"volatility"`
+	Beta       *float64 `json:"beta"`
+}
+
+// VaR is valueobject of value at risk and expected shortfall over Horizon days, as
+// losses in percent of value and in amount.
+type VaR struct {
+	Method    Method  `json:"method"`
+	Horizon   int     `json:"horizon"`
+	VaR       float64 `json:"var"`
+	ES        float64 `json:"es"`
+	VaRAmount float64 `json:"varamount"`
+	ESAmount  float64 `json:"esamount"`
+}
+
+// Report is valueobject of the risk of holdings over the Days of daily returns from
+// Start to End, taken at current weights of the holdings assessed, VaR amounts of the
+// whole Value. Volatility is annualized in percent; Beta is nil without benchmark;
+// DiversificationRatio is the weighted volatility of holdings over that of the
+// portfolio, 1 when they move alike; AvgCorrelation is weighted over pairs.
+type Report struct {
+	Value                float64       `json:"value"`
+	Benchmark            string        `json:"benchmark"`
+	Start                string        `json:"start"`
+	End                  string        `json:"end"`
+	Days                 int           `json:"days"`
+	Confidence           float64       `json:"confidence"`
+	Exposures            []Exposure    `json:"exposures"`
+	Concentration        Concentration `json:"concentration"`
+	Holdings             []HoldingRisk `json:"holdings"`
+	Volatility           float64       `json:"volatility"`
+	Beta                 *float64      `json:"beta"`
+	DiversificationRatio float64       `json:"diversificationratio"`
+	AvgCorrelation       float64       `json:"avgcorrelation"`
+	VaR                  []VaR         `json:"var"`
+}
@@ -0,0 +1,339 @@
+//nolint:gomnd //ignore
+package risk
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"example.com/stocker-back/internal/common"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// Assess computes the risk of holdings from their daily data, in ascending date by
+// ticker, and that of the benchmark index, over the last params.Lookback returns
+// at current weights. Holdings that traded fewer than MinDays in the window, e.g.
+// listed within it or without daily data, are left out of returns, the others
+// weighted among themselves, and only count in exposures and concentration, without
+// volatility and beta. Only days every holding assessed traded are kept, so suspensions
+// do not count as flat days; fewer than MinDays of them are ErrInsufficientData.
+// Holdings without value are left out; betas are nil with fewer than MinDays of those
+// days the benchmark traded, the report without benchmark.
+func Assess(holdings []Holding, histories map[string][]stock.DailyData, benchmark []stock.DailyData, params Params) (Report, error) { //nolint:lll
+	params = params.WithDefaults()
+	if err := params.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	holdings = lo.Filter(holdings, func(h Holding, _ int) bool { return h.Value > 0.0 })
+	if len(holdings) == 0 {
+		return Report{}, ErrNoHoldings
+	}
+	slices.SortFunc(holdings, func(a, b Holding) int {
+		if a.Value != b.Value {
+			return cmp.Compare(b.Value, a.Value)
+		}
+		return cmp.Compare(a.Ticker, b.Ticker)
+	})
+	value := lo.SumBy(holdings, func(h Holding) float64 { return h.Value })
+
+	dates := windowDates(holdings, histories, params.Lookback)
+	assessed := make([]int, 0, len(holdings))
+	returns := make([][]float64, 0, len(holdings))
+	traded := make([][]bool, 0, len(holdings))
+	for i, h := range holdings {
+		r, ok := windowReturns(histories[h.Ticker], dates)
+		if lo.Count(ok, true) < MinDays {
+			continue
+		}
+		assessed = append(assessed, i)
+		returns, traded = append(returns, r), append(traded, ok)
+	}
+	if len(assessed) == 0 {
+		return Report{}, fmt.Errorf("%w: no holding traded %d days", ErrInsufficientData, MinDays)
+	}
+	kept := make([]int, 0, max(len(dates)-1, 0))
+	for t := range max(len(dates)-1, 0) {
+		if lo.EveryBy(traded, func(ok []bool) bool { return ok[t] }) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) < MinDays {
+		return Report{}, fmt.Errorf("%w: %d daily returns all holdings traded, need %d", ErrInsufficientData, len(kept), MinDays)
+	}
+	for i := range returns {
+		returns[i] = pick(returns[i], kept)
+	}
+	indexReturns, indexTraded := windowReturns(benchmark, dates)
+	index, indexKept := pick(indexReturns, kept), pick(indexTraded, kept)
+	days := len(kept)
+
+	assessedValue := lo.SumBy(assessed, func(i int) float64 { return holdings[i].Value })
+	weights := lo.Map(assessed, func(i int, _ int) float64 { return holdings[i].Value / assessedValue })
+	portfolio := make([]float64, days)
+	for i, r := range returns {
+		for t := range days {
+			portfolio[t] += weights[i] * r[t]
+		}
+	}
+
+	report := Report{
+		Value:                value,
+		Benchmark:            params.Benchmark,
+		Start:                common.Day(dates[kept[0]+1]),
+		End:                  common.Day(dates[kept[days-1]+1]),
+		Days:                 days,
+		Confidence:           params.Confidence,
+		Exposures:            ComputeExposures(holdings),
+		Concentration:        ComputeConcentration(holdings),
+		Holdings:             make([]HoldingRisk, 0, len(holdings)),
+		Volatility:           annualize(stddev(portfolio), params.TradingDays),
+		Beta:                 beta(portfolio, index, indexKept),
+		DiversificationRatio: 0.0,
+		AvgCorrelation:       0.0,
+		VaR:                  make([]VaR, 0, 2*len(Horizons)),
+	}
+	for i, h := range holdings {
+		holdingRisk := HoldingRisk{
+			Ticker:     h.Ticker,
+			Name:       h.Name,
+			Sector:     h.Sector,
+			Weight:     100.0 * h.Value / value,
+			Volatility: nil,
+			Beta:       nil,
+		}
+		if j := slices.Index(assessed, i); j >= 0 {
+			holdingRisk.Volatility = lo.ToPtr(annualize(stddev(returns[j]), params.TradingDays))
+			holdingRisk.Beta = beta(returns[j], index, indexKept)
+		}
+		report.Holdings = append(report.Holdings, holdingRisk)
+	}
+
+	if std := stddev(portfolio); std > 0.0 {
+		weighted := 0.0
+		for i, r := range returns {
+			weighted += weights[i] * stddev(r)
+		}
+		report.DiversificationRatio = weighted / std
+	}
+	pairs, correlated := 0.0, 0.0
+	for i := range returns {
+		for j := i + 1; j < len(returns); j++ {
+			pairs += weights[i] * weights[j]
+			correlated += weights[i] * weights[j] * correlation(returns[i], returns[j])
+		}
+	}
+	if pairs > 0.0 {
+		report.AvgCorrelation = correlated / pairs
+	}
+
+	for _, horizon := range Horizons {
+		for _, v := range []VaR{
+			historicalVaR(portfolio, horizon, params.Confidence),
+			parametricVaR(portfolio, horizon, params.Confidence),
+		} {
+			v.VaRAmount, v.ESAmount = value*v.VaR/100.0, value*v.ES/100.0
+			report.VaR = append(report.VaR, v)
+		}
+	}
+
+	if report.Beta == nil {
+		report.Benchmark = ""
+	}
+
+	return report, nil
+}
+
+// ComputeExposures groups holdings by sector, largest first, weights in percent.
+func ComputeExposures(holdings []Holding) []Exposure {
+	value := lo.SumBy(holdings, func(h Holding) float64 { return h.Value })
+	if value <= 0.0 {
+		return make([]Exposure, 0)
+	}
+
+	bySector := lo.GroupBy(holdings, func(h Holding) string { return h.Sector })
+	exposures := make([]Exposure, 0, len(bySector))
+	for sector, members := range bySector {
+		sum := lo.SumBy(members, func(h Holding) float64 { return h.Value })
+		exposures = append(exposures, Exposure{
+			Sector:  sector,
+			Tickers: lo.Map(members, func(h Holding, _ int) string { return h.Ticker }),
+			Value:   sum,
+			Weight:  100.0 * sum / value,
+		})
+	}
+	slices.SortFunc(exposures, func(a, b Exposure) int {
+		if a.Value != b.Value {
+			return cmp.Compare(b.Value, a.Value)
+		}
+		return cmp.Compare(a.Sector, b.Sector)
+	})
+
+	return exposures
+}
+
+// ComputeConcentration measures single-name concentration of holdings.
+func ComputeConcentration(holdings []Holding) Concentration {
+	concentration := Concentration{
+		Largest:       "",
+		LargestWeight: 0.0,
+		Top5Weight:    0.0,
+		HHI:           0.0,
+		Effective:     0.0,
+	}
+	value := lo.SumBy(holdings, func(h Holding) float64 { return h.Value })
+	if value <= 0.0 {
+		return concentration
+	}
+
+	weights := lo.Map(holdings, func(h Holding, _ int) float64 { return h.Value / value })
+	largest := lo.MaxBy(holdings, func(a, b Holding) bool { return a.Value > b.Value })
+	sorted := slices.Clone(weights)
+	slices.SortFunc(sorted, func(a, b float64) int { return cmp.Compare(b, a) })
+
+	concentration.Largest = largest.Ticker
+	concentration.LargestWeight = 100.0 * largest.Value / value
+	concentration.Top5Weight = 100.0 * lo.Sum(sorted[:min(5, len(sorted))])
+	concentration.HHI = lo.SumBy(weights, func(w float64) float64 { return w * w })
+	concentration.Effective = 1.0 / concentration.HHI
+
+	return concentration
+}
+
+// windowDates returns the last lookback+1 dates any holding has daily data of.
+func windowDates(holdings []Holding, histories map[string][]stock.DailyData, lookback int) []string {
+	seen := make(map[string]struct{})
+	for _, h := range holdings {
+		for _, d := range histories[h.Ticker] {
+			seen[d.Date] = struct{}{}
+		}
+	}
+	dates := lo.Keys(seen)
+	slices.Sort(dates)
+
+	return dates[max(len(dates)-lookback-1, 0):]
+}
+
+// windowReturns returns daily returns of dailyData over dates with whether it traded
+// each day, i.e. has daily data on the date and a close before it; returns of days
+// it did not are zero.
+func windowReturns(dailyData []stock.DailyData, dates []string) ([]float64, []bool) {
+	returns := make([]float64, max(len(dates)-1, 0))
+	traded := make([]bool, len(returns))
+	idx, last := 0, 0.0
+	for t, date := range dates {
+		prev, dated := last, false
+		for idx < len(dailyData) && dailyData[idx].Date <= date {
+			if dailyData[idx].Close > 0.0 {
+				last, dated = dailyData[idx].Close, dailyData[idx].Date == date
+			}
+			idx++
+		}
+		if t > 0 && dated && prev > 0.0 {
+			returns[t-1], traded[t-1] = last/prev-1.0, true
+		}
+	}
+	return returns, traded
+}
+
+// pick returns the values at indices.
+func pick[T any](values []T, indices []int) []T {
+	return lo.Map(indices, func(idx int, _ int) T { return values[idx] })
+}
+
+// historicalVaR takes the loss quantile of returns compounded over overlapping windows
+// of horizon days, and the mean loss beyond it.
+func historicalVaR(returns []float64, horizon int, confidence float64) VaR {
+	compounded := make([]float64, 0, len(returns))
+	for t := 0; t+horizon <= len(returns); t++ {
+		growth := 1.0
+		for _, r := range returns[t : t+horizon] {
+			growth *= 1.0 + r
+		}
+		compounded = append(compounded, growth-1.0)
+	}
+	slices.Sort(compounded)
+
+	tail := max(int(math.Ceil((1.0-confidence)*float64(len(compounded)))), 1)
+	return VaR{
+		Method:    MethodHistorical,
+		Horizon:   horizon,
+		VaR:       -100.0 * compounded[tail-1],
+		ES:        -100.0 * lo.Sum(compounded[:tail]) / float64(tail),
+		VaRAmount: 0.0,
+		ESAmount:  0.0,
+	}
+}
+
+// parametricVaR takes the loss quantile and expected shortfall of normal returns of
+// the mean and volatility of returns over horizon days.
+func parametricVaR(returns []float64, horizon int, confidence float64) VaR {
+	mu := mean(returns) * float64(horizon)
+	sigma := stddev(returns) * math.Sqrt(float64(horizon))
+	z := math.Sqrt2 * math.Erfinv(2.0*confidence-1.0)
+	density := math.Exp(-z*z/2.0) / math.Sqrt(2.0*math.Pi)
+
+	return VaR{
+		Method:    MethodParametric,
+		Horizon:   horizon,
+		VaR:       100.0 * (z*sigma - mu),
+		ES:        100.0 * (sigma*density/(1.0-confidence) - mu),
+		VaRAmount: 0.0,
+		ESAmount:  0.0,
+	}
+}
+
+// beta regresses returns on those of the index over the days it traded, nil with
+// fewer than MinDays of them or if the index does not move.
+func beta(returns, index []float64, traded []bool) *float64 {
+	var a, b []float64
+	for t, ok := range traded {
+		if ok {
+			a, b = append(a, returns[t]), append(b, index[t])
+		}
+	}
+	variance := covariance(b, b)
+	if len(b) < MinDays || variance == 0.0 {
+		return nil
+	}
+	return lo.ToPtr(covariance(a, b) / variance)
+}
+
+func correlation(a, b []float64) float64 {
+	stdA, stdB := stddev(a), stddev(b)
+	if stdA == 0.0 || stdB == 0.0 {
+		return 0.0
+	}
+	return covariance(a, b) / (stdA * stdB)
+}
+
+// covariance is the population covariance of a and b of the same length.
+func covariance(a, b []float64) float64 {
+	if len(a) == 0 {
+		return 0.0
+	}
+	meanA, meanB := mean(a), mean(b)
+	sum := 0.0
+	for idx := range a {
+		sum += (a[idx] - meanA) * (b[idx] - meanB)
+	}
+	return sum / float64(len(a))
+}
+
+func stddev(values []float64) float64 {
+	return math.Sqrt(covariance(values, values))
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	return lo.Sum(values) / float64(len(values))
+}
+
+// annualize scales daily volatility to a year, in percent.
+func annualize(std float64, tradingDays int) float64 {
+	return 100.0 * std * math.Sqrt(float64(tradingDays))
+}
@@ -0,0 +1,221 @@
+//nolint:testpackage,lll //ignore
+package risk
+
+import (
+	"math"
+	"testing"
+
+	"example.com/stocker-back/internal/stock"
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// cycle is repeated daily returns of mean zero.
+var cycle = []float64{0.01, -0.02, 0.015, -0.005}
+
+// dailyOf returns daily data of ticker on consecutive days from 2024-01-01, moving
+// scale times the returns of cycle.
+func dailyOf(ticker string, days int, scale float64) []stock.DailyData {
+	output := make([]stock.DailyData, 0, days)
+	price := 10.0
+	for t := range days {
+		if t > 0 {
+			price *= 1.0 + scale*cycle[(t-1)%len(cycle)]
+		}
+		d := stock.NewEmptyDailyData()
+		d.Ticker, d.Date, d.Close = ticker, stocktest.Date(t), price
+		output = append(output, d)
+	}
+	return output
+}
+
+func TestComputeExposures(t *testing.T) {
+	holdings := []Holding{
+		{Ticker: "1.600000", Sector: "银行", Value: 600}, //nolint:exhaustruct
+		{Ticker: "0.300750", Sector: "电池", Value: 100}, //nolint:exhaustruct
+		{Ticker: "1.601398", Sector: "银行", Value: 300}, //nolint:exhaustruct
+	}
+
+	exposures := ComputeExposures(holdings)
+	if assert.Len(t, exposures, 2) {
+		assert.Equal(t, "银行", exposures[0].Sector)
+		assert.Equal(t, []string{"1.600000", "1.601398"}, exposures[0].Tickers)
+		assert.InDelta(t, 90.0, exposures[0].Weight, 1e-9)
+		assert.InDelta(t, 10.0, exposures[1].Weight, 1e-9)
+	}
+
+	concentration := ComputeConcentration(holdings)
+	assert.Equal(t, "1.600000", concentration.Largest)
+	assert.InDelta(t, 60.0, concentration.LargestWeight, 1e-9)
+	assert.InDelta(t, 100.0, concentration.Top5Weight, 1e-9)
+	assert.InDelta(t, 0.46, concentration.HHI, 1e-9)
+	assert.InDelta(t, 1.0/0.46, concentration.Effective, 1e-9)
+}
+
+func TestAssess(t *testing.T) {
+	holdings := []Holding{
+		{Ticker: "A", Sector: "x", Value: 500}, //nolint:exhaustruct
+		{Ticker: "B", Sector: "y", Value: 500}, //nolint:exhaustruct
+		{Ticker: "C", Sector: "y", Value: 0},   //nolint:exhaustruct
+	}
+	histories := map[string][]stock.DailyData{
+		"A": dailyOf("A", 61, 1.0),
+		"B": dailyOf("B", 61, 2.0),
+	}
+	params := DefaultParams()
+	params.Lookback = 40
+
+	report, err := Assess(holdings, histories, dailyOf("1.000300", 61, 1.0), params)
+	assert.NoError(t, err)
+
+	assert.InDelta(t, 1000.0, report.Value, 1e-9)
+	assert.Equal(t, 40, report.Days)
+	assert.Equal(t, "2024-01-22", report.Start)
+	assert.Equal(t, "2024-03-01", report.End)
+	if assert.Len(t, report.Holdings, 2) && assert.NotNil(t, report.Holdings[0].Beta) && assert.NotNil(t, report.Holdings[1].Beta) {
+		assert.InDelta(t, 1.0, *report.Holdings[0].Beta, 1e-9)
+		assert.InDelta(t, 2.0, *report.Holdings[1].Beta, 1e-9)
+		if assert.NotNil(t, report.Holdings[0].Volatility) && assert.NotNil(t, report.Holdings[1].Volatility) {
+			assert.InDelta(t, 2.0**report.Holdings[0].Volatility, *report.Holdings[1].Volatility, 1e-9)
+		}
+	}
+	// B moves twice as A, so both move alike.
+	if assert.NotNil(t, report.Beta) {
+		assert.InDelta(t, 1.5, *report.Beta, 1e-9)
+	}
+	assert.Equal(t, "1.000300", report.Benchmark)
+	assert.InDelta(t, 1.0, report.DiversificationRatio, 1e-9)
+	assert.InDelta(t, 1.0, report.AvgCorrelation, 1e-9)
+
+	std := 1.5 * math.Sqrt(1.875e-4)
+	z := 1.6448536269514722
+	assert.InDelta(t, 100.0*std*math.Sqrt(252), report.Volatility, 1e-9)
+	if assert.Len(t, report.VaR, 4) {
+		// Of 40 returns, the 2 worst are both -3%.
+		assert.Equal(t, VaR{Method: MethodHistorical, Horizon: 1, VaR: 3.0, ES: 3.0, VaRAmount: 30.0, ESAmount: 30.0}, roundVaR(report.VaR[0]))
+		assert.Equal(t, MethodParametric, report.VaR[1].Method)
+		assert.InDelta(t, 100.0*z*std, report.VaR[1].VaR, 1e-6)
+		assert.InDelta(t, 100.0*std*math.Exp(-z*z/2.0)/math.Sqrt(2.0*math.Pi)/0.05, report.VaR[1].ES, 1e-6)
+		assert.Equal(t, 10, report.VaR[2].Horizon)
+		assert.GreaterOrEqual(t, report.VaR[2].ES, report.VaR[2].VaR)
+		assert.InDelta(t, 100.0*z*std*math.Sqrt(10), report.VaR[3].VaR, 1e-6)
+		assert.InDelta(t, 10.0*report.VaR[3].VaR, report.VaR[3].VaRAmount, 1e-9)
+	}
+}
+
+func roundVaR(v VaR) VaR {
+	round := func(x float64) float64 { return math.Round(x*1e6) / 1e6 }
+	v.VaR, v.ES, v.VaRAmount, v.ESAmount = round(v.VaR), round(v.ES), round(v.VaRAmount), round(v.ESAmount)
+	return v
+}
+
+func TestAssessUntradedDays(t *testing.T) {
+	holdings := []Holding{
+		{Ticker: "A", Value: 500}, //nolint:exhaustruct
+		{Ticker: "B", Value: 500}, //nolint:exhaustruct
+	}
+	params := DefaultParams()
+	params.Lookback = 40
+
+	// B suspended 5 days within the window: those days are dropped, not flat.
+	suspended := dailyOf("B", 61, 2.0)
+	histories := map[string][]stock.DailyData{"A": dailyOf("A", 61, 1.0), "B": append(suspended[:30:30], suspended[35:]...)}
+	report, err := Assess(holdings, histories, nil, params)
+	assert.NoError(t, err)
+	assert.Equal(t, 35, report.Days)
+	assert.Equal(t, "2024-01-22", report.Start)
+	assert.Equal(t, "2024-03-01", report.End)
+	// Without benchmark, there is no beta.
+	assert.Nil(t, report.Beta)
+	assert.Nil(t, report.Holdings[0].Beta)
+	assert.Equal(t, "", report.Benchmark)
+
+	// B listed 15 days before the end is left out of returns, A assessed alone.
+	histories["B"] = dailyOf("B", 61, 2.0)[45:]
+	report, err = Assess(holdings, histories, nil, params)
+	assert.NoError(t, err)
+	assert.Equal(t, 40, report.Days)
+	if assert.Len(t, report.Holdings, 2) {
+		assert.Equal(t, "B", report.Holdings[1].Ticker)
+		assert.InDelta(t, 50.0, report.Holdings[1].Weight, 1e-9)
+		assert.Nil(t, report.Holdings[1].Volatility)
+		if assert.NotNil(t, report.Holdings[0].Volatility) {
+			assert.InDelta(t, *report.Holdings[0].Volatility, report.Volatility, 1e-9)
+		}
+	}
+}
+
+func TestAssessWithoutDailyData(t *testing.T) {
+	holdings := []Holding{
+		{Ticker: "A", Sector: "x", Value: 300}, //nolint:exhaustruct
+		{Ticker: "B", Sector: "y", Value: 300}, //nolint:exhaustruct
+		{Ticker: "C", Sector: "y", Value: 400}, //nolint:exhaustruct
+	}
+	histories := map[string][]stock.DailyData{
+		"A": dailyOf("A", 61, 1.0),
+		"B": dailyOf("B", 61, 2.0),
+	}
+	params := DefaultParams()
+	params.Lookback = 40
+
+	// C, e.g. deleted or imported, has no daily data: it is left out of returns, A and B
+	// weighted equally, but still counts in exposures and concentration.
+	report, err := Assess(holdings, histories, dailyOf("1.000300", 61, 1.0), params)
+	assert.NoError(t, err)
+	assert.InDelta(t, 1000.0, report.Value, 1e-9)
+	assert.Equal(t, 40, report.Days)
+	if assert.NotNil(t, report.Beta) {
+		assert.InDelta(t, 1.5, *report.Beta, 1e-9)
+	}
+	if assert.Len(t, report.Exposures, 2) {
+		assert.Equal(t, "y", report.Exposures[0].Sector)
+		assert.InDelta(t, 70.0, report.Exposures[0].Weight, 1e-9)
+	}
+	assert.Equal(t, "C", report.Concentration.Largest)
+	if assert.Len(t, report.Holdings, 3) {
+		assert.Equal(t, "C", report.Holdings[0].Ticker)
+		assert.InDelta(t, 40.0, report.Holdings[0].Weight, 1e-9)
+		assert.Nil(t, report.Holdings[0].Volatility)
+		assert.Nil(t, report.Holdings[0].Beta)
+		assert.NotNil(t, report.Holdings[1].Volatility)
+	}
+	if assert.Len(t, report.VaR, 4) {
+		assert.InDelta(t, 10.0*report.VaR[0].VaR, report.VaR[0].VaRAmount, 1e-9)
+	}
+
+	// Without any holding traded enough, there is no report.
+	_, err = Assess([]Holding{holdings[2]}, histories, nil, params)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
+
+func TestAssessErrors(t *testing.T) {
+	histories := map[string][]stock.DailyData{"A": dailyOf("A", 20, 1.0)}
+	holdings := []Holding{{Ticker: "A", Value: 100}} //nolint:exhaustruct
+
+	_, err := Assess(holdings, histories, nil, DefaultParams())
+	assert.ErrorIs(t, err, ErrInsufficientData)
+
+	_, err = Assess([]Holding{{Ticker: "A"}}, histories, nil, DefaultParams()) //nolint:exhaustruct
+	assert.ErrorIs(t, err, ErrNoHoldings)
+
+	params := DefaultParams()
+	params.Confidence = 1.0
+	_, err = Assess(holdings, histories, nil, params)
+	assert.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestWindowReturns(t *testing.T) {
+	dailyData := dailyOf("A", 4, 1.0)
+	dates := []string{"2023-12-31", dailyData[0].Date, dailyData[1].Date, "2024-01-02 12:00:00.000Z", dailyData[3].Date}
+
+	// Not traded without a close before the first daily data, nor on the date without one.
+	returns, traded := windowReturns(append(dailyData[:2:2], dailyData[3]), dates)
+	assert.Equal(t, []bool{false, true, false, true}, traded)
+	assert.InDelta(t, 0.01, returns[1], 1e-9)
+	assert.InDelta(t, dailyData[3].Close/dailyData[1].Close-1.0, returns[3], 1e-9)
+
+	// Without daily data, e.g. of a missing benchmark, nothing trades and there is no beta.
+	index, indexTraded := windowReturns(nil, dates)
+	assert.Equal(t, []bool{false, false, false, false}, indexTraded)
+	assert.Nil(t, beta(returns, index, indexTraded))
+}
@@ -0,0 +1,82 @@
+package scoring
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidConfig = errors.New("invalid scoring config")
+
+// Method is how raw factor values are standardised before weighting.
+type Method string
+
+const (
+	// MethodZScore scores by standard deviations from the mean, clipped to ±3.
+	MethodZScore Method = "zscore"
+	// MethodPercentile scores by percentile rank in [0,100].
+	MethodPercentile Method = "percentile"
+)
+
+// Scope is the peer group values are standardised within.
+type Scope string
+
+const (
+	ScopeUniverse Scope = "universe"
+	ScopeSector   Scope = "sector"
+)
+
+// Factor is valueobject of a scored field. Field names a key of Input.Values;
+// LowerBetter flips the sign, e.g. for PE; Positive drops non-positive values,
+// e.g. loss-making PE.
+type Factor struct {
+	Name        string  `json:"name"`
+	Group       string  `json:"group"`
+	Field       string  `json:"field"`
+	Weight      float64 `json:"weight"`
+	LowerBetter bool    `json:"lowerbetter"`
+	Positive    bool    `json:"positive"`
+}
+
+// Config is the scoring setup: which factors, how they are standardised and among whom.
+type Config struct {
+	Method  Method   `json:"method"`
+	Scope   Scope    `json:"scope"`
+	Factors []Factor `json:"factors"`
+}
+
+// Validate checks method, scope and factors are usable.
+func (c Config) Validate() error {
+	if c.Method != MethodZScore && c.Method != MethodPercentile {
+		return fmt.Errorf("%w: unknown method %q", ErrInvalidConfig, c.Method)
+	}
+	if c.Scope != ScopeUniverse && c.Scope != ScopeSector {
+		return fmt.Errorf("%w: unknown scope %q", ErrInvalidConfig, c.Scope)
+	}
+	if len(c.Factors) == 0 {
+		return fmt.Errorf("%w: no factors", ErrInvalidConfig)
+	}
+	for _, f := range c.Factors {
+		if f.Name == "" || f.Field == "" || f.Weight <= 0.0 {
+			return fmt.Errorf("%w: factor %q needs name, field and positive weight", ErrInvalidConfig, f.Name)
+		}
+	}
+	return nil
+}
+
+// Input is valueobject of raw factor values of a ticker.
+type Input struct {
+	Ticker string             `json:"ticker"`
+	Sector string             `json:"sector"`
+	Values map[string]float64 `json:"values"`
+}
+
+// Score is entity of the composite score of a ticker on Date. Breakdown holds the
+// standardised score of each factor the ticker had a value for; Rank starts at 1.
+type Score struct {
+	Ticker    string             `json:"ticker"`
+	Date      string             `json:"date"`
+	Sector    string             `json:"sector"`
+	Score     float64            `json:"score"`
+	Rank      int                `json:"rank"`
+	Breakdown map[string]float64 `json:"breakdown"`
+}
@@ -0,0 +1,8 @@
+package scoring
+
+type Repository interface {
+	// SaveScores replaces the ranking of date.
+	SaveScores(date string, scores []Score) error
+	// GetScoresLatest gets the ranking of the latest date, best first.
+	GetScoresLatest() ([]Score, error)
+}
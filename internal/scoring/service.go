@@ -0,0 +1,203 @@
+//nolint:gomnd //ignore
+package scoring
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// zScoreClip bounds z-scores so a single outlier cannot dominate the composite.
+const zScoreClip = 3.0
+
+// DefaultConfig returns equally weighted value, quality, growth and momentum factors,
+// z-scored within the whole universe.
+func DefaultConfig() Config {
+	return Config{
+		Method: MethodZScore,
+		Scope:  ScopeUniverse,
+		Factors: []Factor{
+			{Name: "pe", Group: "value", Field: "priceperearning", Weight: 1.0, LowerBetter: true, Positive: true},
+			{Name: "pb", Group: "value", Field: "priceperbook", Weight: 1.0, LowerBetter: true, Positive: true},
+			{Name: "roe", Group: "quality", Field: "roe", Weight: 1.0, LowerBetter: false, Positive: false},
+			{Name: "grossmargin", Group: "quality", Field: "grossprofitmargin", Weight: 1.0, LowerBetter: false, Positive: false},
+			{Name: "debtratio", Group: "quality", Field: "debtratio", Weight: 1.0, LowerBetter: true, Positive: false},
+			{Name: "profitgrowth", Group: "growth", Field: "netprofitchange", Weight: 1.0, LowerBetter: false, Positive: false},
+			{Name: "revenuegrowth", Group: "growth", Field: "totalrevenuechange", Weight: 1.0, LowerBetter: false, Positive: false},
+			{Name: "momentum20", Group: "momentum", Field: "ret20", Weight: 1.0, LowerBetter: false, Positive: false},
+			{Name: "momentum60", Group: "momentum", Field: "ret60", Weight: 1.0, LowerBetter: false, Positive: false},
+		},
+	}
+}
+
+// NewInput collects fundamentals of s and returns from its daily data in ascending date,
+// see stock.ComputeReturns, for momentum factors.
+func NewInput(s stock.Stock, dailyData []stock.DailyData) (Input, error) {
+	m, err := s.ToMap()
+	if err != nil {
+		return Input{}, err
+	}
+
+	returns := stock.ComputeReturns(lo.Map(dailyData, func(d stock.DailyData, _ int) float64 {
+		return d.Close
+	}))
+
+	values := make(map[string]float64, len(m)+len(returns))
+	for key, value := range m {
+		if v, ok := value.(float64); ok {
+			values[key] = v
+		}
+	}
+	for key, value := range returns {
+		values[key] = value
+	}
+
+	return Input{
+		Ticker: s.Ticker,
+		Sector: s.Sector,
+		Values: values,
+	}, nil
+}
+
+// ComputeScores standardises each factor within the configured scope, combines the
+// weighted factor scores of each ticker, and ranks tickers best first. Factors a ticker
+// has no value for count as neutral, so that a single strong factor does not outrank
+// a full profile; tickers without any factor value are left out.
+func ComputeScores(date string, inputs []Input, config Config) ([]Score, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	groups := lo.GroupBy(inputs, func(input Input) string {
+		if config.Scope == ScopeSector {
+			return input.Sector
+		}
+		return ""
+	})
+
+	breakdowns := make(map[string]map[string]float64, len(inputs))
+	for _, group := range groups {
+		for _, factor := range config.Factors {
+			for ticker, score := range standardise(group, factor, config.Method) {
+				if breakdowns[ticker] == nil {
+					breakdowns[ticker] = make(map[string]float64, len(config.Factors))
+				}
+				breakdowns[ticker][factor.Name] = score
+			}
+		}
+	}
+
+	scores := make([]Score, 0, len(breakdowns))
+	for _, input := range inputs {
+		breakdown, ok := breakdowns[input.Ticker]
+		if !ok {
+			continue
+		}
+
+		sum, weights := 0.0, 0.0
+		for _, factor := range config.Factors {
+			score, ok := breakdown[factor.Name]
+			if !ok {
+				score = neutral(config.Method)
+			}
+			sum += factor.Weight * score
+			weights += factor.Weight
+		}
+
+		scores = append(scores, Score{
+			Ticker:    input.Ticker,
+			Date:      date,
+			Sector:    input.Sector,
+			Score:     sum / weights,
+			Rank:      0,
+			Breakdown: breakdown,
+		})
+	}
+
+	slices.SortStableFunc(scores, func(a, b Score) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Ticker, b.Ticker)
+	})
+	for idx := range scores {
+		scores[idx].Rank = idx + 1
+	}
+
+	return scores, nil
+}
+
+// neutral is the score of the average ticker under method.
+func neutral(method Method) float64 {
+	if method == MethodPercentile {
+		return 50.0
+	}
+	return 0.0
+}
+
+// standardise scores valid values of factor among inputs, oriented so higher is better.
+func standardise(inputs []Input, factor Factor, method Method) map[string]float64 {
+	type point struct {
+		ticker string
+		value  float64
+	}
+
+	points := make([]point, 0, len(inputs))
+	for _, input := range inputs {
+		value, ok := input.Values[factor.Field]
+		if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+		if factor.Positive && value <= 0.0 {
+			continue
+		}
+		if factor.LowerBetter {
+			value = -value
+		}
+		points = append(points, point{ticker: input.Ticker, value: value})
+	}
+
+	output := make(map[string]float64, len(points))
+	if len(points) == 0 {
+		return output
+	}
+
+	switch method {
+	case MethodZScore:
+		values := lo.Map(points, func(p point, _ int) float64 { return p.value })
+		mean := lo.Sum(values) / float64(len(values))
+		variance := lo.SumBy(values, func(v float64) float64 {
+			return (v - mean) * (v - mean)
+		}) / float64(len(values))
+		std := math.Sqrt(variance)
+		for _, p := range points {
+			if std == 0.0 {
+				output[p.ticker] = 0.0
+				continue
+			}
+			output[p.ticker] = max(-zScoreClip, min(zScoreClip, (p.value-mean)/std))
+		}
+	case MethodPercentile:
+		if len(points) == 1 {
+			output[points[0].ticker] = 50.0
+			return output
+		}
+		sorted := lo.Map(points, func(p point, _ int) float64 { return p.value })
+		slices.Sort(sorted)
+		for _, p := range points {
+			// Ties share the average of their ranks.
+			lower, _ := slices.BinarySearch(sorted, p.value)
+			upper := lower
+			for upper+1 < len(sorted) && sorted[upper+1] == p.value {
+				upper++
+			}
+			rank := float64(lower+upper) / 2.0
+			output[p.ticker] = 100.0 * rank / float64(len(sorted)-1)
+		}
+	}
+
+	return output
+}
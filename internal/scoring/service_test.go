@@ -0,0 +1,134 @@
+//nolint:testpackage,lll //ignore
+package scoring
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeScores(t *testing.T) {
+	config := Config{
+		Method: MethodZScore,
+		Scope:  ScopeUniverse,
+		Factors: []Factor{
+			{Name: "pe", Group: "value", Field: "pe", Weight: 1.0, LowerBetter: true, Positive: true},
+			{Name: "roe", Group: "quality", Field: "roe", Weight: 1.0, LowerBetter: false, Positive: false},
+		},
+	}
+	inputs := []Input{
+		{Ticker: "a", Sector: "x", Values: map[string]float64{"pe": 10, "roe": 20}},
+		{Ticker: "b", Sector: "x", Values: map[string]float64{"pe": 20, "roe": 10}},
+		{Ticker: "c", Sector: "y", Values: map[string]float64{"pe": -5, "roe": 15}},
+		{Ticker: "d", Sector: "y", Values: map[string]float64{}},
+	}
+
+	scores, err := ComputeScores("2024-01-05", inputs, config)
+	assert.NoError(t, err)
+	assert.Len(t, scores, 3)
+
+	// Cheap and profitable ranks first; loss-making PE is skipped, not penalised.
+	assert.Equal(t, "a", scores[0].Ticker)
+	assert.Equal(t, 1, scores[0].Rank)
+	assert.Equal(t, "b", scores[2].Ticker)
+	assert.Equal(t, 3, scores[2].Rank)
+	assert.NotContains(t, scores[1].Breakdown, "pe")
+	assert.InDelta(t, 1.0, scores[0].Breakdown["pe"], 1e-9)
+	assert.InDelta(t, -1.0, scores[2].Breakdown["pe"], 1e-9)
+	assert.Equal(t, "2024-01-05", scores[0].Date)
+
+	// Missing PE counts as neutral, halving the ROE score of c.
+	assert.Equal(t, "c", scores[1].Ticker)
+	assert.InDelta(t, scores[1].Breakdown["roe"]/2.0, scores[1].Score, 1e-9)
+}
+
+func TestComputeScoresMissingNeutral(t *testing.T) {
+	config := Config{
+		Method: MethodPercentile,
+		Scope:  ScopeUniverse,
+		Factors: []Factor{
+			{Name: "pe", Group: "value", Field: "pe", Weight: 1.0, LowerBetter: true, Positive: true},
+			{Name: "roe", Group: "quality", Field: "roe", Weight: 1.0, LowerBetter: false, Positive: false},
+		},
+	}
+	inputs := []Input{
+		{Ticker: "a", Sector: "x", Values: map[string]float64{"pe": 10, "roe": 15}},
+		{Ticker: "b", Sector: "x", Values: map[string]float64{"pe": 20, "roe": 10}},
+		{Ticker: "c", Sector: "x", Values: map[string]float64{"roe": 20}},
+	}
+
+	scores, err := ComputeScores("", inputs, config)
+	assert.NoError(t, err)
+
+	// Best ROE alone scores (50 + 100) / 2, no more than cheapest PE with median ROE.
+	assert.Equal(t, []string{"a", "c", "b"}, []string{scores[0].Ticker, scores[1].Ticker, scores[2].Ticker})
+	assert.InDelta(t, 75.0, scores[0].Score, 1e-9)
+	assert.InDelta(t, 75.0, scores[1].Score, 1e-9)
+}
+
+func TestComputeScoresPercentileBySector(t *testing.T) {
+	config := Config{
+		Method: MethodPercentile,
+		Scope:  ScopeSector,
+		Factors: []Factor{
+			{Name: "roe", Group: "quality", Field: "roe", Weight: 1.0, LowerBetter: false, Positive: false},
+		},
+	}
+	inputs := []Input{
+		{Ticker: "a", Sector: "x", Values: map[string]float64{"roe": 5}},
+		{Ticker: "b", Sector: "x", Values: map[string]float64{"roe": 10}},
+		{Ticker: "c", Sector: "y", Values: map[string]float64{"roe": 1}},
+		{Ticker: "d", Sector: "y", Values: map[string]float64{"roe": 2}},
+		{Ticker: "e", Sector: "y", Values: map[string]float64{"roe": 2}},
+	}
+
+	scores, err := ComputeScores("", inputs, config)
+	assert.NoError(t, err)
+
+	got := make(map[string]float64, len(scores))
+	for _, s := range scores {
+		got[s.Ticker] = s.Score
+	}
+	// Ranked within sector; ties share their average rank.
+	assert.InDelta(t, 0.0, got["a"], 1e-9)
+	assert.InDelta(t, 100.0, got["b"], 1e-9)
+	assert.InDelta(t, 0.0, got["c"], 1e-9)
+	assert.InDelta(t, 75.0, got["d"], 1e-9)
+	assert.InDelta(t, 75.0, got["e"], 1e-9)
+}
+
+func TestComputeScoresInvalidConfig(t *testing.T) {
+	_, err := ComputeScores("", nil, Config{Method: "rank", Scope: ScopeUniverse, Factors: DefaultConfig().Factors})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+
+	config := DefaultConfig()
+	config.Factors[0].Weight = 0.0
+	_, err = ComputeScores("", nil, config)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+}
+
+func TestNewInput(t *testing.T) {
+	s := stock.NewEmptyStock()
+	s.Ticker = "600000"
+	s.PricePerEarning = 8.0
+
+	daily := make([]stock.DailyData, 61)
+	for idx := range daily {
+		daily[idx] = stock.NewEmptyDailyData()
+		daily[idx].Close = 10.0 + float64(idx)
+	}
+
+	input, err := NewInput(s, daily)
+	assert.NoError(t, err)
+	assert.Equal(t, "600000", input.Ticker)
+	assert.InDelta(t, 8.0, input.Values["priceperearning"], 1e-9)
+	assert.InDelta(t, 100.0*20.0/50.0, input.Values["ret20"], 1e-9)
+	assert.InDelta(t, 100.0*60.0/10.0, input.Values["ret60"], 1e-9)
+
+	// Too short a history leaves momentum out.
+	input, err = NewInput(s, daily[:30])
+	assert.NoError(t, err)
+	assert.Contains(t, input.Values, "ret20")
+	assert.NotContains(t, input.Values, "ret60")
+}
@@ -1,10 +1,26 @@
 package screener
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
 
+	"example.com/stocker-back/internal/stock"
+	"github.com/pocketbase/pocketbase/tools/cron"
+)
+
+var (
+	ErrInvalidDefinition = errors.New("invalid screen definition")
+	ErrInvalidPlan       = errors.New("invalid plan params")
+)
+
+// Screen is a hit of a screen; Name is the screen definition it belongs to,
+// empty for the default daily screen. Events lists events the rule matched on.
 type Screen struct {
-	Ticker string  `json:"ticker"`
-	Kdj    float64 `json:"kdj"`
+	Name   string        `json:"name"`
+	Ticker string        `json:"ticker"`
+	Kdj    float64       `json:"kdj"`
+	Events []stock.Event `json:"events"`
 }
 
 func (s *Screen) ToMap() (map[string]interface{}, error) {
@@ -18,3 +34,100 @@ func (s *Screen) ToMap() (map[string]interface{}, error) {
 	}
 	return m, nil
 }
+
+// Definition is entity of a named screen users define. Schedule is a cron expression
+// (UTC); empty Schedule runs the screen right after the default daily screen.
+type Definition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Rule        Rule   `json:"rule"`
+	Schedule    string `json:"schedule"`
+}
+
+// Validate checks the definition is named, holds a valid rule and a cron expression
+// for schedule if any.
+func (d Definition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidDefinition)
+	}
+	if d.Schedule != "" {
+		if _, err := cron.NewSchedule(d.Schedule); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidDefinition, err.Error())
+		}
+	}
+	return d.Rule.Validate()
+}
+
+// Snapshot is valueobject of a screen hit kept for the trading day Date of the run.
+type Snapshot struct {
+	Name   string  `json:"name"`
+	Date   string  `json:"date"`
+	Ticker string  `json:"ticker"`
+	Kdj    float64 `json:"kdj"`
+}
+
+// HitHistory is valueobject of how a ticker has appeared in a screen.
+// ConsecutiveDays counts the runs up to the latest one the ticker stayed in,
+// zero if it is not in the latest run.
+type HitHistory struct {
+	Name            string `json:"name"`
+	Ticker          string `json:"ticker"`
+	LastSeen        string `json:"lastseen"`
+	ConsecutiveDays int    `json:"consecutivedays"`
+	TotalDays       int    `json:"totaldays"`
+	Runs            int    `json:"runs"`
+}
+
+// Diff is valueobject of tickers entering and leaving a screen between two runs.
+type Diff struct {
+	Name     string   `json:"name"`
+	Date     string   `json:"date"`
+	PrevDate string   `json:"prevdate"`
+	Entered  []string `json:"entered"`
+	Left     []string `json:"left"`
+}
+
+// StopMethod is how the stop of a plan is placed.
+type StopMethod string
+
+const (
+	// StopATR places the stop ATRMultiple ATRs below entry.
+	StopATR StopMethod = "atr"
+	// StopSupport places the stop SupportBuffer percent below the nearest support,
+	// as StopATR without support below entry.
+	StopSupport StopMethod = "support"
+)
+
+// PlanParams sizes trades of an account of Capital risking RiskPct percent of it per
+// trade, in lots of LotSize shares and never beyond Capital. Targets are the nearest
+// resistance if at least TargetMultiple times the risk per share above entry, that
+// multiple of the risk above entry otherwise. Zero params but Capital take defaults,
+// so a SupportBuffer of 0 is the default buffer, not a stop at support itself.
+type PlanParams struct {
+	Capital        float64    `json:"capital"`
+	RiskPct        float64    `json:"riskpct"`
+	Stop           StopMethod `json:"stop"`
+	ATRMultiple    float64    `json:"atrmultiple"`
+	SupportBuffer  float64    `json:"supportbuffer"`
+	TargetMultiple float64    `json:"targetmultiple"`
+	LotSize        float64    `json:"lotsize"`
+}
+
+// Plan is valueobject of a trade planned on a screen hit entered at Entry, the last
+// close of Date. Risk is the loss at Stop, RiskPct in percent of capital; RewardRisk
+// is the gain at Target over the loss at Stop. Shares is zero if a lot risks more
+// than allowed or costs more than capital.
+type Plan struct {
+	Date         string     `json:"date"`
+	Entry        float64    `json:"entry"`
+	ATR          float64    `json:"atr"`
+	Stop         float64    `json:"stop"`
+	StopMethod   StopMethod `json:"stopmethod"`
+	Target       float64    `json:"target"`
+	TargetSource string     `json:"targetsource"`
+	RewardRisk   float64    `json:"rewardrisk"`
+	Shares       float64    `json:"shares"`
+	Cost         float64    `json:"cost"`
+	Risk         float64    `json:"risk"`
+	RiskPct      float64    `json:"riskpct"`
+}
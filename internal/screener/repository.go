@@ -3,4 +3,26 @@ package screener
 type Repository interface {
 	GetScreens() ([]Screen, error)
 	SetScreens([]Screen) error
+	GetScreensByName(name string) ([]Screen, error)
+	SetScreensByName(name string, screens []Screen) error
+	GetDefinitions() ([]Definition, error)
+	GetDefinitionByName(name string) (Definition, error)
+	SaveDefinition(definition Definition) error
+	DeleteDefinitionByName(name string) error
+	SnapshotRepository
+}
+
+// SnapshotRepository keeps dated screen results.
+type SnapshotRepository interface {
+	// SaveSnapshots records the run of the screen on date, with or without hits, and
+	// replaces its snapshots.
+	SaveSnapshots(name, date string, screens []Screen) error
+	// GetSnapshotDates gets the last `limit` run dates of the screen, latest first,
+	// including runs without hits.
+	GetSnapshotDates(name string, limit int) ([]string, error)
+	GetSnapshotsByDate(name, date string) ([]Snapshot, error)
+	GetSnapshotsByTicker(name, ticker string) ([]Snapshot, error)
+	// GetNotifiedDate gets the run date of the screen last notified, empty if none.
+	GetNotifiedDate(name string) (string, error)
+	SetNotifiedDate(name, date string) error
 }
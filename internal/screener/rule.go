@@ -0,0 +1,597 @@
+package screener
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+var (
+	ErrInvalidRule  = errors.New("invalid screen rule")
+	ErrUnknownField = errors.New("unknown screen field")
+)
+
+// Op is the operator of a rule node.
+type Op string
+
+const (
+	OpAnd        Op = "and"
+	OpOr         Op = "or"
+	OpNot        Op = "not"
+	OpGt         Op = "gt"
+	OpGte        Op = "gte"
+	OpLt         Op = "lt"
+	OpLte        Op = "lte"
+	OpEq         Op = "eq"
+	OpNeq        Op = "neq"
+	OpBetween    Op = "between"
+	OpPattern    Op = "pattern"
+	OpDivergence Op = "divergence"
+	OpEvent      Op = "event"
+)
+
+// Rule is a node of the screen rule tree, written as JSON, e.g.
+//
+//	{"op": "and", "rules": [
+//	  {"op": "lte", "field": "j", "value": 30},
+//	  {"op": "lt", "field": "pe", "value": 20},
+//	  {"op": "gt", "field": "close", "ref": "sma20"},
+//	  {"op": "not", "rules": [{"op": "eq", "field": "sector", "text": "银行"}]}
+//	]}
+//
+// Logical ops combine Rules; comparisons take Field against Value, another field Ref,
+// or Text for string fields; between takes Min and Max inclusive; pattern, divergence
+// and event match the criterion against the ticker's candles, e.g.
+//
+//	{"op": "event", "event": {"kinds": ["macdgoldencross", "kdgoldencross"], "days": 3}}
+//
+// Sector-relative fields compare a ticker with its sector, e.g. PE below sector median
+// and 20-day return above sector average by 5%:
+//
+//	{"op": "and", "rules": [
+//	  {"op": "lt", "field": "pe", "ref": "sectorpe"},
+//	  {"op": "gt", "field": "relret20", "value": 5}
+//	]}
+//
+// Technical fields such as bollpercentb, atr, adx, cci or volumeratio are computed
+// from the ticker's candles, e.g. a trending stock closing above its upper band:
+//
+//	{"op": "and", "rules": [
+//	  {"op": "gt", "field": "adx", "value": 25},
+//	  {"op": "gt", "field": "bollpercentb", "value": 1}
+//	]}
+type Rule struct {
+	Op         Op                   `json:"op"`
+	Rules      []Rule               `json:"rules,omitempty"`
+	Field      string               `json:"field,omitempty"`
+	Value      float64              `json:"value,omitempty"`
+	Ref        string               `json:"ref,omitempty"`
+	Text       string               `json:"text,omitempty"`
+	Min        float64              `json:"min,omitempty"`
+	Max        float64              `json:"max,omitempty"`
+	Pattern    *PatternCriterion    `json:"pattern,omitempty"`
+	Divergence *DivergenceCriterion `json:"divergence,omitempty"`
+	Event      *EventCriterion      `json:"event,omitempty"`
+}
+
+// DefaultRule keeps stocks whose KDJ J is at most 30.
+func DefaultRule() Rule {
+	return Rule{
+		Op:    OpLte,
+		Field: "j",
+		Value: 30, //nolint:gomnd //ignore
+	}
+}
+
+// ParseRule decodes and validates a JSON rule.
+func ParseRule(data []byte) (Rule, error) {
+	var rule Rule
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return Rule{}, fmt.Errorf("%w: %s", ErrInvalidRule, err.Error())
+	}
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// Validate checks the rule tree is well formed and only refers to known fields, text
+// compared with string fields only.
+func (r Rule) Validate() error {
+	known := knownFacts()
+	checkField := func(field string) error {
+		_, numeric := known.Values[field]
+		_, text := known.Texts[field]
+		if !numeric && !text {
+			return fmt.Errorf("%w: %q", ErrUnknownField, field)
+		}
+		return nil
+	}
+
+	switch r.Op {
+	case OpAnd, OpOr:
+		if len(r.Rules) == 0 {
+			return fmt.Errorf("%w: %s needs rules", ErrInvalidRule, r.Op)
+		}
+	case OpNot:
+		if len(r.Rules) != 1 {
+			return fmt.Errorf("%w: not needs exactly one rule", ErrInvalidRule)
+		}
+	case OpGt, OpGte, OpLt, OpLte, OpEq, OpNeq, OpBetween:
+		if err := checkField(r.Field); err != nil {
+			return err
+		}
+		if r.Ref != "" {
+			if err := checkField(r.Ref); err != nil {
+				return err
+			}
+		}
+		if r.Text != "" && r.Op != OpEq && r.Op != OpNeq {
+			return fmt.Errorf("%w: text only compares with eq or neq", ErrInvalidRule)
+		}
+		if _, text := known.Texts[r.Field]; text != (r.Text != "") {
+			return fmt.Errorf("%w: %q compares with %s", ErrInvalidRule, r.Field, lo.Ternary(text, "text", "value or ref"))
+		}
+		if _, text := known.Texts[r.Ref]; r.Ref != "" && (text || r.Text != "") {
+			return fmt.Errorf("%w: ref %q must be a numeric field", ErrInvalidRule, r.Ref)
+		}
+		if r.Op == OpBetween && r.Min > r.Max {
+			return fmt.Errorf("%w: between needs min <= max", ErrInvalidRule)
+		}
+	case OpPattern:
+		if r.Pattern == nil {
+			return fmt.Errorf("%w: pattern needs criterion", ErrInvalidRule)
+		}
+		if err := r.Pattern.validate(); err != nil {
+			return err
+		}
+	case OpDivergence:
+		if r.Divergence == nil {
+			return fmt.Errorf("%w: divergence needs criterion", ErrInvalidRule)
+		}
+	case OpEvent:
+		if r.Event == nil {
+			return fmt.Errorf("%w: event needs criterion", ErrInvalidRule)
+		}
+		if err := r.Event.validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown op %q", ErrInvalidRule, r.Op)
+	}
+
+	for _, child := range r.Rules {
+		if err := child.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Fields returns the fields referred to by the rule tree.
+func (r Rule) Fields() []string {
+	fields := make([]string, 0)
+	if r.Field != "" {
+		fields = append(fields, r.Field)
+	}
+	if r.Ref != "" {
+		fields = append(fields, r.Ref)
+	}
+	for _, child := range r.Rules {
+		fields = append(fields, child.Fields()...)
+	}
+
+	slices.Sort(fields)
+	return slices.Compact(fields)
+}
+
+// NeedsCandles tells whether the rule tree matches on candles rather than fields only.
+func (r Rule) NeedsCandles() bool {
+	if r.Op == OpPattern || r.Op == OpDivergence || r.Op == OpEvent {
+		return true
+	}
+	for _, child := range r.Rules {
+		if child.NeedsCandles() {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsDaily tells whether the rule tree refers to fields of daily data.
+func (r Rule) NeedsDaily() bool {
+	daily := stock.NewEmptyDailyData()
+	m, _ := daily.ToMap()
+	m["price"] = 0.0
+	for _, field := range r.Fields() {
+		if _, ok := m[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsTechnicals tells whether the rule tree refers to fields computed from candles
+// by SetTechnicals.
+func (r Rule) NeedsTechnicals() bool {
+	for _, field := range r.Fields() {
+		if slices.Contains(technicalFields, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsSector tells whether the rule tree refers to returns or sector aggregates.
+func (r Rule) NeedsSector() bool {
+	facts := Facts{Values: make(map[string]float64), Texts: nil, Candles: nil}
+	facts.SetSector(knownSectorStats(), emptyReturns())
+	for _, field := range r.Fields() {
+		if _, ok := facts.Values[field]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Eval evaluates the rule tree against facts of a ticker; comparisons of facts it
+// lacks are false.
+func (r Rule) Eval(facts Facts) (bool, error) {
+	switch r.Op {
+	case OpAnd:
+		for _, child := range r.Rules {
+			ok, err := child.Eval(facts)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case OpOr:
+		for _, child := range r.Rules {
+			ok, err := child.Eval(facts)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+	case OpNot:
+		if len(r.Rules) != 1 {
+			return false, ErrInvalidRule
+		}
+		ok, err := r.Rules[0].Eval(facts)
+		return !ok, err
+	case OpPattern:
+		_, ok := r.Pattern.Match(facts.Candles)
+		return ok, nil
+	case OpDivergence:
+		_, ok := r.Divergence.Match(facts.Candles)
+		return ok, nil
+	case OpEvent:
+		_, ok := r.Event.Match(facts.Candles)
+		return ok, nil
+	case OpEq, OpNeq:
+		if r.Text != "" {
+			text, ok := facts.Texts[r.Field]
+			if !ok {
+				return false, nil
+			}
+			return (text == r.Text) == (r.Op == OpEq), nil
+		}
+	case OpGt, OpGte, OpLt, OpLte, OpBetween:
+	default:
+		return false, fmt.Errorf("%w: unknown op %q", ErrInvalidRule, r.Op)
+	}
+
+	// Facts may be missing, e.g. without sector or still warming up, matching nothing.
+	left, ok := facts.Values[r.Field]
+	if !ok {
+		return false, nil
+	}
+	right := r.Value
+	if r.Ref != "" {
+		if right, ok = facts.Values[r.Ref]; !ok {
+			return false, nil
+		}
+	}
+	// Loss-making PE or negative book is not cheap, matching no comparison.
+	notPositive := func(field string, value float64) bool {
+		return slices.Contains(positiveFields, field) && value <= 0.0
+	}
+	if notPositive(r.Field, left) || notPositive(r.Ref, right) {
+		return false, nil
+	}
+
+	switch r.Op {
+	case OpGt:
+		return left > right, nil
+	case OpGte:
+		return left >= right, nil
+	case OpLt:
+		return left < right, nil
+	case OpLte:
+		return left <= right, nil
+	case OpEq:
+		return left == right, nil
+	case OpNeq:
+		return left != right, nil
+	case OpBetween:
+		return left >= r.Min && left <= r.Max, nil
+	}
+
+	return false, nil
+}
+
+// FiredEvents returns events matched by the event nodes of the rule tree, in date order,
+// to report what fired for a hit.
+func (r Rule) FiredEvents(facts Facts) []stock.Event {
+	events := make([]stock.Event, 0)
+	if r.Op == OpEvent && r.Event != nil {
+		matched, _ := r.Event.Match(facts.Candles)
+		events = append(events, matched...)
+	}
+	for _, child := range r.Rules {
+		events = append(events, child.FiredEvents(facts)...)
+	}
+
+	slices.SortStableFunc(events, func(a, b stock.Event) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+
+	return slices.CompactFunc(events, func(a, b stock.Event) bool {
+		return a.Date == b.Date && a.Kind == b.Kind
+	})
+}
+
+// Facts holds what a rule can be evaluated on for a single ticker: numeric and
+// string fields keyed by their JSON name, and candles in date order.
+type Facts struct {
+	Values  map[string]float64
+	Texts   map[string]string
+	Candles []stock.OHLC
+}
+
+// fieldAliases are short names for frequently screened fields.
+var fieldAliases = map[string]string{
+	"pe":    "priceperearning",
+	"pb":    "priceperbook",
+	"price": "close",
+}
+
+// positiveFields are valuation ratios only meaningful when positive.
+var positiveFields = []string{"pe", "priceperearning", "pb", "priceperbook"}
+
+// NewFacts flattens fundamentals, latest indicators and latest daily data of a ticker
+// into facts. Booleans become 1 or 0; identifiers such as ticker and date are dropped.
+func NewFacts(s stock.Stock, indicators stock.Indicators, daily stock.DailyData) (Facts, error) {
+	facts := Facts{
+		Values:  make(map[string]float64),
+		Texts:   make(map[string]string),
+		Candles: nil,
+	}
+
+	maps := make([]map[string]interface{}, 0, 3) //nolint:gomnd //ignore
+	for _, toMap := range []func() (map[string]interface{}, error){s.ToMap, indicators.ToMap, daily.ToMap} {
+		m, err := toMap()
+		if err != nil {
+			return Facts{}, err
+		}
+		maps = append(maps, m)
+	}
+
+	for _, m := range maps {
+		for key, value := range m {
+			if key == "ticker" || key == "date" {
+				continue
+			}
+			switch v := value.(type) {
+			case float64:
+				facts.Values[key] = v
+			case bool:
+				facts.Values[key] = 0.0
+				if v {
+					facts.Values[key] = 1.0
+				}
+			case string:
+				facts.Texts[key] = v
+			}
+		}
+	}
+
+	for alias, field := range fieldAliases {
+		if value, ok := facts.Values[field]; ok {
+			facts.Values[alias] = value
+		}
+	}
+
+	return facts, nil
+}
+
+// SetSector adds returns of the ticker (`ret<N>`), aggregates of its sector (`sectorpe`,
+// `sectorpb`, `sectorroe`, `sectorret<N>`) and returns relative to its sector (`relret<N>`,
+// in percentage points). Returns missing for short history and aggregates missing for
+// stocks without sector, or sectors without positive PE or PB, are left out.
+func (f *Facts) SetSector(stats stock.SectorStats, returns map[string]float64) {
+	if stats.Count > 0 {
+		f.Values["sectorroe"] = stats.MedianROE
+		if stats.MedianPE > 0.0 {
+			f.Values["sectorpe"] = stats.MedianPE
+		}
+		if stats.MedianPB > 0.0 {
+			f.Values["sectorpb"] = stats.MedianPB
+		}
+	}
+
+	for _, n := range stock.ReturnDays {
+		field := stock.ReturnField(n)
+		value, ok := returns[field]
+		if ok {
+			f.Values[field] = value
+		}
+		sectorValue, sectorOk := stats.Returns[field]
+		if sectorOk {
+			f.Values["sector"+field] = sectorValue
+		}
+		if ok && sectorOk {
+			f.Values["rel"+field] = value - sectorValue
+		}
+	}
+}
+
+// technicalFields are the fields SetTechnicals computes from candles.
+var technicalFields = []string{
+	"bollmid", "bollupper", "bolllower", "bollpercentb", "bollbandwidth", "atr", "kcmid", "kcupper", "kclower",
+	"cci", "wr", "stochrsik", "stochrsid", "roc", "rocma", "trix", "trixma", "bias1", "bias2", "bias3",
+	"plusdi", "minusdi", "adx", "sar", "sarup", "aroonup", "aroondown", "aroonosc",
+	"cmf", "mfi", "vwap", "volumema", "volumeratio",
+}
+
+// SetTechnicals adds the latest Bollinger, ATR, Keltner, oscillator, trend and volume
+// indicators of candles in date order, with default params. Unlike indicators, they
+// are not stored and are computed from the candles given; fields still warming up are
+// left out.
+func (f *Facts) SetTechnicals(candles []stock.OHLCV) {
+	ohlc := stock.OHLCV2OHLC(candles)
+	set := func(field string, value float64) {
+		if !math.IsNaN(value) && !math.IsInf(value, 0) {
+			f.Values[field] = value
+		}
+	}
+
+	if v, ok := lastOf(stock.ComputeBollingerSeries(ohlc, stock.DefaultBollingerParams())); ok {
+		set("bollmid", v.Mid)
+		set("bollupper", v.Upper)
+		set("bolllower", v.Lower)
+		set("bollpercentb", v.PercentB)
+		set("bollbandwidth", v.Bandwidth)
+	}
+	if v, ok := lastOf(stock.ComputeATRSeries(ohlc, stock.DefaultATRParams())); ok {
+		set("atr", v.Atr)
+	}
+	if v, ok := lastOf(stock.ComputeKeltnerSeries(ohlc, stock.DefaultKeltnerParams())); ok {
+		set("kcmid", v.Mid)
+		set("kcupper", v.Upper)
+		set("kclower", v.Lower)
+	}
+
+	if v, ok := lastOf(stock.ComputeCCISeries(ohlc, stock.DefaultCCIParams())); ok {
+		set("cci", v)
+	}
+	if v, ok := lastOf(stock.ComputeWRSeries(ohlc, stock.DefaultWRParams())); ok {
+		set("wr", v)
+	}
+	if v, ok := lastOf(stock.ComputeStochRSISeries(ohlc, stock.DefaultStochRSIParams())); ok {
+		set("stochrsik", v.K)
+		set("stochrsid", v.D)
+	}
+	if v, ok := lastOf(stock.ComputeROCSeries(ohlc, stock.DefaultROCParams())); ok {
+		set("roc", v.Roc)
+		set("rocma", v.Ma)
+	}
+	if v, ok := lastOf(stock.ComputeTRIXSeries(ohlc, stock.DefaultTRIXParams())); ok {
+		set("trix", v.Trix)
+		set("trixma", v.Ma)
+	}
+	if v, ok := lastOf(stock.ComputeBIASSeries(ohlc, stock.DefaultBIASParams())); ok {
+		set("bias1", v.Bias1)
+		set("bias2", v.Bias2)
+		set("bias3", v.Bias3)
+	}
+
+	if v, ok := lastOf(stock.ComputeDMISeries(ohlc, stock.DefaultDMIParams())); ok {
+		set("plusdi", v.PlusDI)
+		set("minusdi", v.MinusDI)
+		set("adx", v.Adx)
+	}
+	if v, ok := lastOf(stock.ComputeSARSeries(ohlc, stock.DefaultSARParams())); ok {
+		set("sar", v.Sar)
+		set("sarup", 0.0)
+		if v.Up {
+			set("sarup", 1.0)
+		}
+	}
+	if v, ok := lastOf(stock.ComputeAroonSeries(ohlc, stock.DefaultAroonParams())); ok {
+		set("aroonup", v.Up)
+		set("aroondown", v.Down)
+		set("aroonosc", v.Osc)
+	}
+
+	if v, ok := lastOf(stock.ComputeCMFSeries(candles, stock.DefaultCMFParams())); ok {
+		set("cmf", v)
+	}
+	if v, ok := lastOf(stock.ComputeMFISeries(candles, stock.DefaultMFIParams())); ok {
+		set("mfi", v)
+	}
+	if v, ok := lastOf(stock.ComputeVWAPSeries(candles, stock.DefaultVWAPParams())); ok {
+		set("vwap", v)
+	}
+	if v, ok := lastOf(stock.ComputeVolumeMASeries(candles, stock.DefaultVolumeMAParams())); ok {
+		set("volumema", v.Ma)
+		set("volumeratio", v.Ratio)
+	}
+}
+
+// lastOf returns the last value of series past warm-up, false on error.
+func lastOf[T any](series stock.Series[T], err error) (T, bool) {
+	if err != nil {
+		var zero T
+		return zero, false
+	}
+	return series.Last()
+}
+
+// knownSectorStats returns stats with every aggregate, to list the fields they set.
+func knownSectorStats() stock.SectorStats {
+	return stock.SectorStats{
+		Sector:    "",
+		Count:     1,
+		MedianPE:  1.0,
+		MedianPB:  1.0,
+		MedianROE: 0.0,
+		Returns:   emptyReturns(),
+	}
+}
+
+func emptyReturns() map[string]float64 {
+	returns := make(map[string]float64, len(stock.ReturnDays))
+	for _, n := range stock.ReturnDays {
+		returns[stock.ReturnField(n)] = 0.0
+	}
+	return returns
+}
+
+// KnownFields lists the fields rules can refer to.
+func KnownFields() []string {
+	facts := knownFacts()
+
+	fields := make([]string, 0, len(facts.Values)+len(facts.Texts))
+	for key := range facts.Values {
+		fields = append(fields, key)
+	}
+	for key := range facts.Texts {
+		fields = append(fields, key)
+	}
+	slices.Sort(fields)
+
+	return fields
+}
+
+// knownFacts returns facts holding every field, zero or empty.
+func knownFacts() Facts {
+	s := stock.NewEmptyStock()
+	facts, _ := NewFacts(s, stock.Indicators{}, stock.NewEmptyDailyData()) //nolint:exhaustruct
+	facts.SetSector(knownSectorStats(), emptyReturns())
+	for _, field := range technicalFields {
+		facts.Values[field] = 0.0
+	}
+	return facts
+}
@@ -0,0 +1,240 @@
+//nolint:testpackage,lll //ignore
+package screener
+
+import (
+	"fmt"
+	"testing"
+
+	"example.com/stocker-back/internal/stock"
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFacts(t *testing.T) Facts {
+	t.Helper()
+
+	s := stock.NewEmptyStock()
+	s.Ticker = "1.600000"
+	s.PricePerEarning = 12.0
+	s.ROE = 15.0
+	s.Sector = "银行"
+	s.ETF = false
+
+	indicators := stock.Indicators{Ticker: "1.600000", Candles: 200} //nolint:exhaustruct
+	indicators.J = 12.0
+	indicators.Sma20 = 9.5
+
+	daily := stock.NewEmptyDailyData()
+	daily.Close = 10.0
+	daily.Volume = 1000.0
+
+	facts, err := NewFacts(s, indicators, daily)
+	if err != nil {
+		t.Fatal("fail to run NewFacts()")
+	}
+	return facts
+}
+
+func TestRuleEval(t *testing.T) {
+	facts := newTestFacts(t)
+
+	tests := []struct {
+		name string
+		rule string
+		want bool
+	}{
+		{"default", `{"op": "lte", "field": "j", "value": 30}`, true},
+		{"alias", `{"op": "lt", "field": "pe", "value": 10}`, false},
+		{"ref", `{"op": "gt", "field": "price", "ref": "sma20"}`, true},
+		{"between", `{"op": "between", "field": "roe", "min": 10, "max": 20}`, true},
+		{"bool", `{"op": "eq", "field": "etf", "value": 0}`, true},
+		{"text", `{"op": "eq", "field": "sector", "text": "银行"}`, true},
+		{"and", `{"op": "and", "rules": [{"op": "lte", "field": "j", "value": 30}, {"op": "gt", "field": "pe", "value": 20}]}`, false},
+		{"or", `{"op": "or", "rules": [{"op": "lte", "field": "j", "value": 0}, {"op": "gt", "field": "volume", "value": 500}]}`, true},
+		{"not", `{"op": "not", "rules": [{"op": "neq", "field": "sector", "text": "银行"}]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule([]byte(tt.rule))
+			if err != nil {
+				t.Fatalf("fail to ParseRule(): %s", err.Error())
+			}
+			got, err := rule.Eval(facts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRuleInvalid(t *testing.T) {
+	_, err := ParseRule([]byte(`{"op": "gt", "field": "nope", "value": 1}`))
+	assert.ErrorIs(t, err, ErrUnknownField)
+
+	_, err = ParseRule([]byte(`{"op": "xor"}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = ParseRule([]byte(`{"op": "not", "rules": []}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = ParseRule([]byte(`{"op": "lt", "field": "sector", "text": "银行"}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = ParseRule([]byte(`{"op": "pattern"}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	// Criteria name known kinds and look back within the candles screened.
+	_, err = ParseRule([]byte(`{"op": "event", "event": {"kinds": ["goldencross"], "days": 3}}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+	_, err = ParseRule([]byte(`{"op": "event", "event": {"kinds": ["macdgoldencross"], "days": 200}}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+	_, err = ParseRule([]byte(`{"op": "pattern", "pattern": {"kinds": ["hammer"], "days": -1}}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+	_, err = ParseRule([]byte(`{"op": "pattern", "pattern": {"kinds": ["hammer", "dragonfly"], "days": 3}}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	// Text only compares with string fields, which take nothing else.
+	_, err = ParseRule([]byte(`{"op": "eq", "field": "pe", "text": "12"}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+	_, err = ParseRule([]byte(`{"op": "eq", "field": "sector", "value": 0}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+	_, err = ParseRule([]byte(`{"op": "gt", "field": "close", "ref": "sector"}`))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestRuleNeeds(t *testing.T) {
+	assert.False(t, DefaultRule().NeedsDaily())
+	assert.False(t, DefaultRule().NeedsCandles())
+
+	rule, _ := ParseRule([]byte(`{"op": "and", "rules": [{"op": "gt", "field": "price", "value": 5}, {"op": "pattern", "pattern": {"kinds": ["hammer"], "days": 3}}]}`))
+	assert.True(t, rule.NeedsDaily())
+	assert.True(t, rule.NeedsCandles())
+	assert.Equal(t, []string{"price"}, rule.Fields())
+	assert.False(t, rule.NeedsSector())
+
+	rule, _ = ParseRule([]byte(`{"op": "lt", "field": "pe", "ref": "sectorpe"}`))
+	assert.True(t, rule.NeedsSector())
+	assert.False(t, rule.NeedsDaily())
+}
+
+func TestRuleSector(t *testing.T) {
+	facts := newTestFacts(t)
+	facts.SetSector(stock.SectorStats{
+		Sector:    "银行",
+		Count:     3,
+		MedianPE:  15.0,
+		MedianPB:  1.0,
+		MedianROE: 10.0,
+		Returns:   map[string]float64{"ret5": 1.0, "ret20": 2.0, "ret60": 3.0},
+	}, map[string]float64{"ret5": 0.5, "ret20": 8.0})
+
+	tests := []struct {
+		name string
+		rule string
+		want bool
+	}{
+		{"pe below sector median", `{"op": "lt", "field": "pe", "ref": "sectorpe"}`, true},
+		{"roe above sector median", `{"op": "gt", "field": "roe", "ref": "sectorroe"}`, true},
+		{"ret20 above sector by 5", `{"op": "gt", "field": "relret20", "value": 5}`, true},
+		{"ret5 above sector", `{"op": "gt", "field": "relret5", "value": 0}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule([]byte(tt.rule))
+			if err != nil {
+				t.Fatalf("fail to ParseRule(): %s", err.Error())
+			}
+			got, err := rule.Eval(facts)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// Return over 60 days is missing for short history, matching nothing but its siblings.
+	rule, _ := ParseRule([]byte(`{"op": "gt", "field": "relret60", "value": 0}`))
+	got, err := rule.Eval(facts)
+	assert.NoError(t, err)
+	assert.False(t, got)
+	either, _ := ParseRule([]byte(`{"op": "or", "rules": [{"op": "gt", "field": "relret60", "value": 0}, {"op": "gt", "field": "close", "value": 0}]}`))
+	got, err = either.Eval(facts)
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	// Loss-makers are not below the sector median PE.
+	cheap, _ := ParseRule([]byte(`{"op": "lt", "field": "pe", "ref": "sectorpe"}`))
+	facts.Values["pe"] = -3.0
+	got, err = cheap.Eval(facts)
+	assert.NoError(t, err)
+	assert.False(t, got)
+
+	// Without sector, or a sector without positive PE, there is no median to compare.
+	facts = newTestFacts(t)
+	facts.SetSector(stock.SectorStats{}, map[string]float64{"ret5": 0.5}) //nolint:exhaustruct
+	assert.NotContains(t, facts.Values, "sectorpe")
+	assert.NotContains(t, facts.Values, "sectorroe")
+	got, err = cheap.Eval(facts)
+	assert.NoError(t, err)
+	assert.False(t, got)
+
+	facts = newTestFacts(t)
+	facts.SetSector(stock.SectorStats{Sector: "银行", Count: 2, MedianPE: 0.0, MedianPB: 1.0, MedianROE: 5.0, Returns: nil}, nil)
+	assert.NotContains(t, facts.Values, "sectorpe")
+	assert.InDelta(t, 1.0, facts.Values["sectorpb"], 1e-9)
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	assert.NoError(t, Definition{Name: "oversold", Description: "", Rule: DefaultRule(), Schedule: ""}.Validate())
+	assert.ErrorIs(t, Definition{Name: "", Description: "", Rule: DefaultRule(), Schedule: ""}.Validate(), ErrInvalidDefinition)
+	assert.ErrorIs(t, Definition{Name: "bad", Description: "", Rule: Rule{Op: "xor"}, Schedule: ""}.Validate(), ErrInvalidRule) //nolint:exhaustruct
+	assert.NoError(t, Definition{Name: "premarket", Description: "", Rule: DefaultRule(), Schedule: "0 1 * * 1-5"}.Validate())
+	assert.ErrorIs(t, Definition{Name: "bad", Description: "", Rule: DefaultRule(), Schedule: "every day"}.Validate(), ErrInvalidDefinition)
+}
+
+func TestRuleEvent(t *testing.T) {
+	facts := newTestFacts(t)
+	for idx, c := range stocktest.DeclineRally() {
+		facts.Candles = append(facts.Candles, stock.OHLC{Date: fmt.Sprintf("2024-%03d", idx), Open: c, High: c, Low: c, Close: c})
+	}
+
+	rule, err := ParseRule([]byte(`{"op": "and", "rules": [{"op": "lte", "field": "j", "value": 30}, {"op": "event", "event": {"kinds": ["newhigh"], "days": 1}}]}`))
+	if err != nil {
+		t.Fatalf("fail to ParseRule(): %s", err.Error())
+	}
+	assert.True(t, rule.NeedsCandles())
+
+	got, err := rule.Eval(facts)
+	assert.NoError(t, err)
+	assert.True(t, got)
+
+	fired := rule.FiredEvents(facts)
+	assert.Equal(t, []stock.Event{{Date: "2024-099", Kind: stock.EventNewHigh, Direction: stock.DirectionBullish, Value: 100}}, fired)
+}
+
+func TestRuleTechnicals(t *testing.T) {
+	rule, err := ParseRule([]byte(`{"op": "and", "rules": [{"op": "gt", "field": "adx", "value": 25}, {"op": "lte", "field": "atr", "value": 1}]}`))
+	if err != nil {
+		t.Fatalf("fail to ParseRule(): %s", err.Error())
+	}
+	assert.True(t, rule.NeedsTechnicals())
+	assert.False(t, rule.NeedsCandles())
+	assert.False(t, DefaultRule().NeedsTechnicals())
+
+	// True range of 1 throughout, flat so without trend.
+	facts := newTestFacts(t)
+	facts.SetTechnicals(stock.DailyData2OHLCV(rangeDaily(60, 1.0)))
+	assert.InDelta(t, 1.0, facts.Values["atr"], 1e-9)
+	assert.InDelta(t, 10.0, facts.Values["kcmid"], 1e-9)
+	assert.InDelta(t, 1.0, facts.Values["volumeratio"], 1e-9)
+	got, err := rule.Eval(facts)
+	assert.NoError(t, err)
+	assert.False(t, got)
+
+	// Fields still warming up are left out.
+	facts = newTestFacts(t)
+	facts.SetTechnicals(stock.DailyData2OHLCV(rangeDaily(10, 1.0)))
+	assert.NotContains(t, facts.Values, "atr")
+	got, err = rule.Eval(facts)
+	assert.NoError(t, err)
+	assert.False(t, got)
+}
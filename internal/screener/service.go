@@ -0,0 +1,132 @@
+package screener
+
+import (
+	"fmt"
+	"slices"
+
+	"example.com/stocker-back/internal/stock"
+)
+
+// PatternCriterion matches a ticker when any of Kinds fired within the last Days candles.
+// Empty Kinds matches any pattern; empty Direction matches any direction.
+type PatternCriterion struct {
+	Kinds       []stock.PatternKind    `json:"kinds"`
+	Direction   stock.PatternDirection `json:"direction"`
+	Days        int                    `json:"days"`
+	MinStrength float64                `json:"minstrength"`
+}
+
+// validate checks kinds and direction are known and days within the candles screened.
+func (c PatternCriterion) validate() error {
+	for _, kind := range c.Kinds {
+		if !slices.Contains(stock.PatternKinds, kind) {
+			return fmt.Errorf("%w: unknown pattern %q", ErrInvalidRule, kind)
+		}
+	}
+	if !slices.Contains([]stock.PatternDirection{"", stock.DirectionBullish, stock.DirectionBearish, stock.DirectionNeutral}, c.Direction) { //nolint:lll
+		return fmt.Errorf("%w: unknown direction %q", ErrInvalidRule, c.Direction)
+	}
+	return validateDays(c.Days)
+}
+
+// Match returns the most recent pattern satisfying the criterion over candles in date order.
+func (c PatternCriterion) Match(candles []stock.OHLC) (stock.Pattern, bool) {
+	days := max(c.Days, 1)
+	patterns := stock.DetectPatternsRecent(candles, days)
+
+	for idx := len(patterns) - 1; idx >= 0; idx-- {
+		p := patterns[idx]
+		if len(c.Kinds) > 0 && !slices.Contains(c.Kinds, p.Kind) {
+			continue
+		}
+		if c.Direction != "" && c.Direction != p.Direction {
+			continue
+		}
+		if p.Strength < c.MinStrength {
+			continue
+		}
+		return p, true
+	}
+
+	return stock.Pattern{}, false
+}
+
+// DivergenceCriterion matches a ticker when a divergence on any of Indicators ended within
+// the last Days candles. Empty Indicators matches any indicator; empty Direction any direction.
+// Swings are confirmed a few bars late, so Days should exceed the swing bars of stock.DefaultDivergenceParams.
+type DivergenceCriterion struct {
+	Indicators    []string               `json:"indicators"`
+	Direction     stock.PatternDirection `json:"direction"`
+	Days          int                    `json:"days"`
+	IncludeHidden bool                   `json:"includehidden"`
+}
+
+// Match returns the most recent divergence satisfying the criterion over candles in date order.
+func (c DivergenceCriterion) Match(candles []stock.OHLC) (stock.Divergence, bool) {
+	days := max(c.Days, 1)
+	if len(candles) == 0 {
+		return stock.Divergence{}, false
+	}
+	since := candles[max(len(candles)-days, 0)].Date
+
+	divergences := stock.ComputeDivergences(candles)
+	for idx := len(divergences) - 1; idx >= 0; idx-- {
+		d := divergences[idx]
+		if d.End < since {
+			break
+		}
+		if len(c.Indicators) > 0 && !slices.Contains(c.Indicators, d.Indicator) {
+			continue
+		}
+		if c.Direction != "" && c.Direction != d.Direction {
+			continue
+		}
+		if d.Hidden && !c.IncludeHidden {
+			continue
+		}
+		return d, true
+	}
+
+	return stock.Divergence{}, false
+}
+
+// EventCriterion matches a ticker when any of Kinds fired within the last Days candles.
+// Empty Kinds matches any event.
+type EventCriterion struct {
+	Kinds []stock.EventKind `json:"kinds"`
+	Days  int               `json:"days"`
+}
+
+// validate checks kinds are known and days within the candles screened.
+func (c EventCriterion) validate() error {
+	for _, kind := range c.Kinds {
+		if !slices.Contains(stock.EventKinds, kind) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidRule, kind)
+		}
+	}
+	return validateDays(c.Days)
+}
+
+// Match returns events satisfying the criterion over candles in date order, latest last.
+func (c EventCriterion) Match(candles []stock.OHLC) ([]stock.Event, bool) {
+	events := stock.DetectEventsRecent(candles, max(c.Days, 1))
+
+	matched := make([]stock.Event, 0)
+	for _, event := range events {
+		if len(c.Kinds) > 0 && !slices.Contains(c.Kinds, event.Kind) {
+			continue
+		}
+		matched = append(matched, event)
+	}
+
+	return matched, len(matched) > 0
+}
+
+// validateDays checks criterion days look back no further than the
+// stock.IndicatorsLookback candles screens and backtests match on.
+func validateDays(days int) error {
+	if days < 0 || days > stock.IndicatorsLookback {
+		return fmt.Errorf("%w: days must be within [0, %d]", ErrInvalidRule, stock.IndicatorsLookback)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package screener
+
+import (
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+// ComputeHitHistory summarises appearances of ticker given the run dates of the screen
+// (any order) and the dates of its snapshots.
+func ComputeHitHistory(name, ticker string, runDates []string, snapshots []Snapshot) HitHistory {
+	runs := slices.Clone(runDates)
+	slices.Sort(runs)
+	runs = slices.Compact(runs)
+
+	hitDates := lo.Uniq(lo.Map(snapshots, func(s Snapshot, _ int) string {
+		return s.Date
+	}))
+	slices.Sort(hitDates)
+
+	history := HitHistory{
+		Name:            name,
+		Ticker:          ticker,
+		LastSeen:        "",
+		ConsecutiveDays: 0,
+		TotalDays:       len(hitDates),
+		Runs:            len(runs),
+	}
+	if len(hitDates) > 0 {
+		history.LastSeen = hitDates[len(hitDates)-1]
+	}
+
+	for idx := len(runs) - 1; idx >= 0; idx-- {
+		if _, found := slices.BinarySearch(hitDates, runs[idx]); !found {
+			break
+		}
+		history.ConsecutiveDays++
+	}
+
+	return history
+}
+
+// ComputeDiff lists tickers in curr but not in prev as entered, and the reverse as left.
+func ComputeDiff(name, date, prevDate string, prev, curr []Snapshot) Diff {
+	toTicker := func(s Snapshot, _ int) string {
+		return s.Ticker
+	}
+	prevTickers := lo.Map(prev, toTicker)
+	currTickers := lo.Map(curr, toTicker)
+
+	left, entered := lo.Difference(prevTickers, currTickers)
+	slices.Sort(entered)
+	slices.Sort(left)
+
+	return Diff{
+		Name:     name,
+		Date:     date,
+		PrevDate: prevDate,
+		Entered:  entered,
+		Left:     left,
+	}
+}
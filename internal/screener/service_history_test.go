@@ -0,0 +1,48 @@
+//nolint:testpackage,lll //ignore
+package screener
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeHitHistory(t *testing.T) {
+	runs := []string{"2024-01-05", "2024-01-04", "2024-01-03", "2024-01-02", "2024-01-01"}
+	snapshot := func(date string) Snapshot {
+		return Snapshot{Name: "", Date: date, Ticker: "1.600000", Kdj: 10}
+	}
+
+	got := ComputeHitHistory("", "1.600000", runs, []Snapshot{snapshot("2024-01-01"), snapshot("2024-01-03"), snapshot("2024-01-04"), snapshot("2024-01-05")})
+	assert.Equal(t, HitHistory{Name: "", Ticker: "1.600000", LastSeen: "2024-01-05", ConsecutiveDays: 3, TotalDays: 4, Runs: 5}, got)
+
+	// Not in the latest run.
+	got = ComputeHitHistory("", "1.600000", runs, []Snapshot{snapshot("2024-01-02")})
+	assert.Equal(t, "2024-01-02", got.LastSeen)
+	assert.Equal(t, 0, got.ConsecutiveDays)
+
+	// Never seen.
+	got = ComputeHitHistory("", "1.600000", runs, nil)
+	assert.Equal(t, "", got.LastSeen)
+	assert.Equal(t, 0, got.TotalDays)
+}
+
+func TestComputeDiff(t *testing.T) {
+	snapshots := func(date string, tickers ...string) []Snapshot {
+		output := make([]Snapshot, 0, len(tickers))
+		for _, ticker := range tickers {
+			output = append(output, Snapshot{Name: "x", Date: date, Ticker: ticker, Kdj: 0})
+		}
+		return output
+	}
+
+	got := ComputeDiff("x", "2024-01-02", "2024-01-01", snapshots("2024-01-01", "a", "b", "c"), snapshots("2024-01-02", "d", "b", "a"))
+
+	assert.Equal(t, []string{"d"}, got.Entered)
+	assert.Equal(t, []string{"c"}, got.Left)
+	assert.Equal(t, "2024-01-01", got.PrevDate)
+
+	got = ComputeDiff("x", "2024-01-01", "", nil, snapshots("2024-01-01", "b", "a"))
+	assert.Equal(t, []string{"a", "b"}, got.Entered)
+	assert.Empty(t, got.Left)
+}
@@ -0,0 +1,131 @@
+//nolint:gomnd //ignore
+package screener
+
+import (
+	"fmt"
+	"math"
+
+	"example.com/stocker-back/internal/stock"
+)
+
+// DefaultPlanParams returns 1% of capital risked per trade, stops 2 ATRs below entry
+// or 1% below support, targets at resistance at least twice the risk away, else at
+// twice the risk, and lots of 100.
+// Capital is left to the caller.
+func DefaultPlanParams() PlanParams {
+	return PlanParams{
+		Capital:        0.0,
+		RiskPct:        1.0,
+		Stop:           StopATR,
+		ATRMultiple:    2.0,
+		SupportBuffer:  1.0,
+		TargetMultiple: 2.0,
+		LotSize:        100.0,
+	}
+}
+
+// WithDefaults fills zero params but Capital with DefaultPlanParams.
+func (p PlanParams) WithDefaults() PlanParams {
+	defaults := DefaultPlanParams()
+	if p.RiskPct == 0.0 {
+		p.RiskPct = defaults.RiskPct
+	}
+	if p.Stop == "" {
+		p.Stop = defaults.Stop
+	}
+	if p.ATRMultiple == 0.0 {
+		p.ATRMultiple = defaults.ATRMultiple
+	}
+	if p.SupportBuffer == 0.0 {
+		p.SupportBuffer = defaults.SupportBuffer
+	}
+	if p.TargetMultiple == 0.0 {
+		p.TargetMultiple = defaults.TargetMultiple
+	}
+	if p.LotSize == 0.0 {
+		p.LotSize = defaults.LotSize
+	}
+	return p
+}
+
+// Validate checks capital, multiples and buffer are positive and RiskPct is a percentage.
+func (p PlanParams) Validate() error {
+	if p.Capital <= 0.0 {
+		return fmt.Errorf("%w: capital must be positive", ErrInvalidPlan)
+	}
+	if p.RiskPct <= 0.0 || p.RiskPct > 100.0 {
+		return fmt.Errorf("%w: riskpct must be within (0, 100]", ErrInvalidPlan)
+	}
+	if p.Stop != StopATR && p.Stop != StopSupport {
+		return fmt.Errorf("%w: unknown stop %q", ErrInvalidPlan, p.Stop)
+	}
+	if p.ATRMultiple <= 0.0 || p.SupportBuffer <= 0.0 || p.TargetMultiple <= 0.0 || p.LotSize <= 0.0 {
+		return fmt.Errorf("%w: atrmultiple, supportbuffer, targetmultiple and lotsize must be positive", ErrInvalidPlan) //nolint:lll
+	}
+	return nil
+}
+
+// ComputePlan plans a trade entered at the last close of dailyData, in ascending date,
+// with ATR(14) and support/resistance levels of stock.ComputeLevels over it.
+func ComputePlan(dailyData []stock.DailyData, params PlanParams) (Plan, error) {
+	params = params.WithDefaults()
+	if err := params.Validate(); err != nil {
+		return Plan{}, err
+	}
+	atrParams := stock.DefaultATRParams()
+	if len(dailyData) <= atrParams.Warmup() {
+		return Plan{}, stock.ErrNotEnoughCandles
+	}
+
+	atrs, err := stock.ComputeATRWith(stock.DailyData2OHLC(dailyData), atrParams)
+	if err != nil {
+		return Plan{}, err
+	}
+	levels, err := stock.ComputeLevels(stock.DailyData2OHLCV(dailyData))
+	if err != nil {
+		return Plan{}, err
+	}
+
+	last := dailyData[len(dailyData)-1]
+	plan := Plan{
+		Date:         last.Date,
+		Entry:        last.Close,
+		ATR:          atrs[len(atrs)-1].Atr,
+		Stop:         0.0,
+		StopMethod:   StopATR,
+		Target:       0.0,
+		TargetSource: "",
+		RewardRisk:   0.0,
+		Shares:       0.0,
+		Cost:         0.0,
+		Risk:         0.0,
+		RiskPct:      0.0,
+	}
+
+	plan.Stop = math.Max(plan.Entry-params.ATRMultiple*plan.ATR, 0.0)
+	if params.Stop == StopSupport && levels.Support != nil {
+		if stop := levels.Support.Price * (1.0 - params.SupportBuffer/100.0); stop > 0.0 && stop < plan.Entry {
+			plan.Stop, plan.StopMethod = stop, StopSupport
+		}
+	}
+	riskPerShare := plan.Entry - plan.Stop
+	if riskPerShare <= 0.0 {
+		return Plan{}, fmt.Errorf("%w: no range to place a stop below %g", ErrInvalidPlan, plan.Entry)
+	}
+
+	// Resistance closer than the multiple would cap the reward below it.
+	plan.Target, plan.TargetSource = plan.Entry+params.TargetMultiple*riskPerShare, "multiple"
+	if r := levels.Resistance; r != nil && (r.Price-plan.Entry)/riskPerShare >= params.TargetMultiple {
+		plan.Target, plan.TargetSource = r.Price, string(stock.LevelResistance)
+	}
+	plan.RewardRisk = (plan.Target - plan.Entry) / riskPerShare
+
+	lotsRisked := math.Floor(params.Capital * params.RiskPct / 100.0 / riskPerShare / params.LotSize)
+	lotsAfforded := math.Floor(params.Capital / plan.Entry / params.LotSize)
+	plan.Shares = math.Min(lotsRisked, lotsAfforded) * params.LotSize
+	plan.Cost = plan.Shares * plan.Entry
+	plan.Risk = plan.Shares * riskPerShare
+	plan.RiskPct = 100.0 * plan.Risk / params.Capital
+
+	return plan, nil
+}
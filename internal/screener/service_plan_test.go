@@ -0,0 +1,108 @@
+//nolint:testpackage,lll //ignore
+package screener
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/stock"
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// rangeDaily returns days of daily data closing at 10 within a range of width.
+func rangeDaily(days int, width float64) []stock.DailyData {
+	output := make([]stock.DailyData, 0, days)
+	for t := range days {
+		d := stock.NewEmptyDailyData()
+		d.Ticker, d.Date = "1.600000", stocktest.Date(t)
+		d.Open, d.High, d.Low, d.Close, d.Volume = 10.0, 10.0+width/2.0, 10.0-width/2.0, 10.0, 1e6
+		output = append(output, d)
+	}
+	return output
+}
+
+func TestComputePlanATR(t *testing.T) {
+	params := DefaultPlanParams()
+	params.Capital = 100000.0
+
+	plan, err := ComputePlan(rangeDaily(30, 1.0), params)
+	assert.NoError(t, err)
+
+	// True range of 1 throughout, stop 2 ATRs below entry risking 1000 of capital.
+	assert.Equal(t, "2024-01-30 00:00:00.000Z", plan.Date)
+	assert.InDelta(t, 10.0, plan.Entry, 1e-9)
+	assert.InDelta(t, 1.0, plan.ATR, 1e-9)
+	assert.Equal(t, StopATR, plan.StopMethod)
+	assert.InDelta(t, 8.0, plan.Stop, 1e-9)
+	assert.InDelta(t, 500.0, plan.Shares, 1e-9)
+	assert.InDelta(t, 5000.0, plan.Cost, 1e-9)
+	assert.InDelta(t, 1000.0, plan.Risk, 1e-9)
+	assert.InDelta(t, 1.0, plan.RiskPct, 1e-9)
+
+	// Resistance within the range is closer than twice the risk.
+	assert.Equal(t, "multiple", plan.TargetSource)
+	assert.InDelta(t, 14.0, plan.Target, 1e-9)
+	assert.InDelta(t, 2.0, plan.RewardRisk, 1e-9)
+
+	// Far enough for a smaller multiple, resistance is the target.
+	params.TargetMultiple = 0.1
+	plan, err = ComputePlan(rangeDaily(30, 1.0), params)
+	assert.NoError(t, err)
+	levels, err := stock.ComputeLevels(stock.DailyData2OHLCV(rangeDaily(30, 1.0)))
+	assert.NoError(t, err)
+	assert.Equal(t, "resistance", plan.TargetSource)
+	assert.InDelta(t, levels.Resistance.Price, plan.Target, 1e-9)
+	assert.InDelta(t, (plan.Target-10.0)/2.0, plan.RewardRisk, 1e-9)
+}
+
+func TestComputePlanSupport(t *testing.T) {
+	params := DefaultPlanParams()
+	params.Capital, params.Stop = 100000.0, StopSupport
+
+	plan, err := ComputePlan(rangeDaily(30, 1.0), params)
+	assert.NoError(t, err)
+
+	levels, err := stock.ComputeLevels(stock.DailyData2OHLCV(rangeDaily(30, 1.0)))
+	assert.NoError(t, err)
+	stop := levels.Support.Price * 0.99
+	assert.Equal(t, StopSupport, plan.StopMethod)
+	assert.InDelta(t, stop, plan.Stop, 1e-9)
+	assert.InDelta(t, 100.0*float64(int(1000.0/(10.0-stop)/100.0)), plan.Shares, 1e-9)
+	assert.LessOrEqual(t, plan.Risk, 1000.0)
+}
+
+func TestComputePlanLots(t *testing.T) {
+	params := DefaultPlanParams()
+	params.Capital = 1000.0
+
+	// 1% of 1000 risks less than a lot at 2 per share.
+	plan, err := ComputePlan(rangeDaily(30, 1.0), params)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.0, plan.Shares, 1e-9)
+	assert.InDelta(t, 0.0, plan.Risk, 1e-9)
+
+	// Risking all of it, capital affords a single lot.
+	params.RiskPct = 100.0
+	plan, err = ComputePlan(rangeDaily(30, 1.0), params)
+	assert.NoError(t, err)
+	assert.InDelta(t, 100.0, plan.Shares, 1e-9)
+	assert.InDelta(t, 20.0, plan.RiskPct, 1e-9)
+}
+
+func TestComputePlanErrors(t *testing.T) {
+	_, err := ComputePlan(rangeDaily(30, 1.0), DefaultPlanParams())
+	assert.ErrorIs(t, err, ErrInvalidPlan)
+
+	params := DefaultPlanParams()
+	params.Capital = 100000.0
+	_, err = ComputePlan(rangeDaily(10, 1.0), params)
+	assert.ErrorIs(t, err, stock.ErrNotEnoughCandles)
+
+	// Without range, there is nowhere to place a stop.
+	_, err = ComputePlan(rangeDaily(30, 0.0), params)
+	assert.ErrorIs(t, err, ErrInvalidPlan)
+
+	params.Stop = "trailing"
+	_, err = ComputePlan(rangeDaily(30, 1.0), params)
+	assert.ErrorIs(t, err, ErrInvalidPlan)
+}
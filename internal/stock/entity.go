@@ -118,6 +118,45 @@ func OHLC2Low(candles []OHLC) []float64 {
 	})
 }
 
+// SharesPerLot is the A-share board lot; eastmoney reports volume in lots (手).
+const SharesPerLot = 100
+
+// OHLCV is valueobject for daily prices together with traded volume and value.
+type OHLCV struct {
+	Date     string  `db:"date" json:"date"`
+	Open     float64 `db:"open" json:"open"`
+	High     float64 `db:"high" json:"high"`
+	Low      float64 `db:"low" json:"low"`
+	Close    float64 `db:"close" json:"close"`
+	Volume   float64 `db:"volume" json:"volume"`
+	Value    float64 `db:"value" json:"value"`
+	Turnover float64 `db:"turnover" json:"turnover"`
+}
+
+func OHLCV2OHLC(candles []OHLCV) []OHLC {
+	return lo.Map(candles, func(candle OHLCV, _ int) OHLC {
+		return OHLC{
+			Date:  candle.Date,
+			Open:  candle.Open,
+			High:  candle.High,
+			Low:   candle.Low,
+			Close: candle.Close,
+		}
+	})
+}
+
+func OHLCV2Volume(candles []OHLCV) []float64 {
+	return lo.Map(candles, func(candle OHLCV, _ int) float64 {
+		return candle.Volume
+	})
+}
+
+func OHLCV2Value(candles []OHLCV) []float64 {
+	return lo.Map(candles, func(candle OHLCV, _ int) float64 {
+		return candle.Value
+	})
+}
+
 // DailyData is the aggregate valueobject for all daily time serie data.
 type DailyData struct {
 	Ticker     string  `db:"ticker" json:"ticker"`
@@ -162,3 +201,84 @@ func (d *DailyData) ToMap() (map[string]interface{}, error) {
 	}
 	return m, nil
 }
+
+func DailyData2OHLC(data []DailyData) []OHLC {
+	return lo.Map(data, func(d DailyData, _ int) OHLC {
+		return OHLC{
+			Date:  d.Date,
+			Open:  d.Open,
+			High:  d.High,
+			Low:   d.Low,
+			Close: d.Close,
+		}
+	})
+}
+
+func DailyData2OHLCV(data []DailyData) []OHLCV {
+	return lo.Map(data, func(d DailyData, _ int) OHLCV {
+		return OHLCV{
+			Date:     d.Date,
+			Open:     d.Open,
+			High:     d.High,
+			Low:      d.Low,
+			Close:    d.Close,
+			Volume:   d.Volume,
+			Value:    d.Value,
+			Turnover: d.Turnover,
+		}
+	})
+}
+
+// Indicators is the aggregate valueobject of indicator state for a ticker on a given day.
+// Candles counts the daily candles seen up to Date, used to tell warm-up state apart.
+type Indicators struct {
+	Ticker  string `db:"ticker" json:"ticker"`
+	Date    string `db:"date" json:"date"`
+	Candles int    `db:"candles" json:"candles"`
+	SMA
+	MACD
+	RSI
+	KDJ
+}
+
+func (i *Indicators) ToMap() (map[string]interface{}, error) {
+	var m map[string]interface{}
+	b, err := json.Marshal(*i)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Series is valueobject holding an indicator series with the index of its first
+// valid value; earlier values are warm-up placeholders.
+type Series[T any] struct {
+	Values     []T `json:"values"`
+	FirstValid int `json:"firstvalid"`
+}
+
+// NewSeries wraps values whose warm-up ends at firstValid.
+func NewSeries[T any](values []T, firstValid int) Series[T] {
+	return Series[T]{
+		Values:     values,
+		FirstValid: min(max(firstValid, 0), len(values)),
+	}
+}
+
+// Valid reports whether the value at idx is past warm-up.
+func (s Series[T]) Valid(idx int) bool {
+	return idx >= s.FirstValid && idx < len(s.Values)
+}
+
+// Last returns the last value and whether it is past warm-up.
+func (s Series[T]) Last() (T, bool) {
+	var last T
+	if len(s.Values) == 0 {
+		return last, false
+	}
+
+	return s.Values[len(s.Values)-1], s.Valid(len(s.Values) - 1)
+}
@@ -4,10 +4,15 @@ package stock
 type Repository interface {
 	GetStockByTicker(ticker string) (Stock, error)
 	GetStocks() ([]Stock, error)
+	GetStocksByTickers(tickers []string) ([]Stock, error)
 	GetStocksBySector(sector string) ([]Stock, error)
 	GetDailyDataAll() (map[string][]DailyData, error)
 	GetDailyDataLastByTicker(ticker string) (DailyData, error)
 	GetDailyDataLastAll() ([]DailyData, error)
+	GetDailyDataLastByTickers(tickers []string) ([]DailyData, error)
+	GetDailyDataByTicker(ticker string, limit int) ([]DailyData, error)
+	GetDailyDataByTickerSince(ticker string, since string) ([]DailyData, error)
+	GetIndicatorsLastAll() ([]Indicators, error)
 
 	CreateStock(stock Stock) error
 
@@ -15,6 +20,8 @@ type Repository interface {
 	UpdateStocks(stocks []Stock) error
 
 	CreateDailyData(dailydata []DailyData) error
+	CreateIndicators(indicators []Indicators) error
 
 	DeleteStockByTicker(ticker string) error
+	DeleteIndicatorsAll() error
 }
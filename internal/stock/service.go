@@ -2,9 +2,19 @@
 package stock
 
 import (
+	"errors"
+	"math"
+
 	"github.com/samber/lo"
 )
 
+var (
+	// ErrInvalidPeriod is returned when an indicator is given a non-positive period.
+	ErrInvalidPeriod = errors.New("indicator period must be positive")
+	// ErrNotEnoughCandles is returned when the series is shorter than the period.
+	ErrNotEnoughCandles = errors.New("not enough candles for indicator period")
+)
+
 // SMA is valueobject holding all SMA values for a given day.
 type SMA struct {
 	Sma5   float64 `db:"sma5" json:"sma5"`
@@ -129,7 +139,89 @@ func computeSMAOne(closes []float64, lastSMA float64, n float64) float64 {
 	return lastSMA + (closes[len(closes)-1]-closes[len(closes)-int(n)-1])/n
 }
 
+// ComputeSMAWith calculates SMA of close prices for an arbitrary window n.
+func ComputeSMAWith(candles []OHLC, n int) ([]float64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	if len(candles) < n {
+		return nil, ErrNotEnoughCandles
+	}
+
+	return computeSMA(OHLC2Close(candles), float64(n))
+}
+
+// ComputeSMAOneWith calculates SMA of window n for the last candle from previous SMA.
+func ComputeSMAOneWith(candles []OHLC, lastSMA float64, n int) (float64, error) {
+	if n <= 0 {
+		return 0.0, ErrInvalidPeriod
+	}
+	if len(candles) <= n {
+		return 0.0, ErrNotEnoughCandles
+	}
+
+	return computeSMAOne(OHLC2Close(candles), lastSMA, float64(n)), nil
+}
+
+// ComputeEMAWith calculates EMA of close prices for an arbitrary window n.
+func ComputeEMAWith(candles []OHLC, n int) ([]float64, error) {
+	if n <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	return computeEMA(OHLC2Close(candles), float64(n)), nil
+}
+
+// ComputeEMAOneWith calculates EMA of window n for a single close from previous EMA.
+func ComputeEMAOneWith(thisClose, lastEMA float64, n int) (float64, error) {
+	if n <= 0 {
+		return 0.0, ErrInvalidPeriod
+	}
+
+	return computeEMAOne(thisClose, lastEMA, float64(n)), nil
+}
+
+// computeEMA calculates EMA with smoothing 2/(n+1), seeded by the first value.
+func computeEMA(values []float64, n float64) []float64 {
+	ema := make([]float64, len(values))
+	for idx, value := range values {
+		if idx == 0 {
+			ema[idx] = computeEMAOne(value, value, n)
+			continue
+		}
+		ema[idx] = computeEMAOne(value, ema[idx-1], n)
+	}
+
+	return ema
+}
+
+// computeEMAOne calculates a single EMA from previous value.
+func computeEMAOne(value, lastEMA, n float64) float64 {
+	return (lastEMA*(n-1.0) + value*2.0) / (n + 1.0)
+}
+
+// ComputeSMASeries calculates SMA of window n, valid once n closes are seen.
+func ComputeSMASeries(candles []OHLC, n int) (Series[float64], error) {
+	sma, err := ComputeSMAWith(candles, n)
+	if err != nil {
+		return Series[float64]{}, err
+	}
+
+	return NewSeries(sma, n-1), nil
+}
+
+// ComputeEMASeries calculates EMA of window n, valid once n closes are seen.
+func ComputeEMASeries(candles []OHLC, n int) (Series[float64], error) {
+	ema, err := ComputeEMAWith(candles, n)
+	if err != nil {
+		return Series[float64]{}, err
+	}
+
+	return NewSeries(ema, n-1), nil
+}
+
 // MACD is valueobject holding all values for MACD for a given day.
+// Ema12 and Ema26 hold the fast and slow EMA, named after the default preset.
 type MACD struct {
 	Ema12 float64 `db:"ema12" json:"ema12"`
 	Ema26 float64 `db:"ema26" json:"ema26"`
@@ -138,81 +230,136 @@ type MACD struct {
 	Hist  float64 `db:"hist" json:"hist"`
 }
 
+// MACDParams holds the periods of fast EMA, slow EMA and signal line.
+type MACDParams struct {
+	Fast   int `json:"fast"`
+	Slow   int `json:"slow"`
+	Signal int `json:"signal"`
+}
+
+// DefaultMACDParams returns the classic MACD(12,26,9) preset.
+func DefaultMACDParams() MACDParams {
+	return MACDParams{
+		Fast:   12,
+		Slow:   26,
+		Signal: 9,
+	}
+}
+
+func (p MACDParams) validate() error {
+	if p.Fast <= 0 || p.Slow <= 0 || p.Signal <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of MACD, once the signal line has settled on the slow EMA.
+func (p MACDParams) Warmup() int {
+	return max(p.Fast, p.Slow) + p.Signal - 2
+}
+
 // ComputeMACD wraps computeMACD to take typed input output.
 func ComputeMACD(candles []OHLC) []MACD {
+	macd, _ := ComputeMACDWith(candles, DefaultMACDParams())
+	return macd
+}
+
+// ComputeMACDWith calculates MACD of close prices for given periods.
+func ComputeMACDWith(candles []OHLC, params MACDParams) ([]MACD, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
 	closes := OHLC2Close(candles)
 	macd := make([]MACD, len(closes))
 
-	ema12, ema26, diff, dea, hist := computeMACD(closes)
+	emaFast, emaSlow, diff, dea, hist := computeMACDWith(
+		closes, float64(params.Fast), float64(params.Slow), float64(params.Signal),
+	)
 
 	for idx := range closes {
-		macd[idx].Ema12 = ema12[idx]
-		macd[idx].Ema26 = ema26[idx]
+		macd[idx].Ema12 = emaFast[idx]
+		macd[idx].Ema26 = emaSlow[idx]
 		macd[idx].Diff = diff[idx]
 		macd[idx].Dea = dea[idx]
 		macd[idx].Hist = hist[idx]
 	}
 
-	return macd
+	return macd, nil
+}
+
+// ComputeMACDSeries calculates MACD for given periods with its warm-up.
+func ComputeMACDSeries(candles []OHLC, params MACDParams) (Series[MACD], error) {
+	macd, err := ComputeMACDWith(candles, params)
+	if err != nil {
+		return Series[MACD]{}, err
+	}
+
+	return NewSeries(macd, params.Warmup()), nil
 }
 
 // computeMACD calculates all MACD for a given time seris close prices.
 func computeMACD(closes []float64) ([]float64, []float64, []float64, []float64, []float64) {
-	var ema12 []float64
-	var ema26 []float64
-	var diff []float64
-	var dea []float64
-	var hist []float64
-
-	var thisEma12 float64
-	var thisEma26 float64
-	var thisDiff float64
-	var thisDea float64
-	for idx, close := range closes {
-		if idx == 0 {
-			thisEma12 = (close*11.0 + close*2.0) / 13.0
-			thisEma26 = (close*25.0 + close*2.0) / 27.0
-			thisDiff = thisEma12 - thisEma26
-			thisDea = (thisDiff*8.0 + thisDiff*2.0) / 10.0
-		} else {
-			thisEma12 = (ema12[idx-1]*11.0 + close*2.0) / 13.0
-			thisEma26 = (ema26[idx-1]*25.0 + close*2.0) / 27.0
-			thisDiff = thisEma12 - thisEma26
-			thisDea = (dea[idx-1]*8.0 + thisDiff*2.0) / 10.0
-		}
+	return computeMACDWith(closes, 12.0, 26.0, 9.0)
+}
 
-		ema12 = append(ema12, thisEma12)
-		ema26 = append(ema26, thisEma26)
-		diff = append(diff, thisDiff)
-		dea = append(dea, thisDea)
-		hist = append(hist, 2.0*(thisDiff-thisDea))
+// computeMACDWith calculates all MACD for given close prices and periods.
+func computeMACDWith(closes []float64, fast, slow, signal float64) ([]float64, []float64, []float64, []float64, []float64) { //nolint:lll
+	emaFast := computeEMA(closes, fast)
+	emaSlow := computeEMA(closes, slow)
+	diff := make([]float64, len(closes))
+	for idx := range closes {
+		diff[idx] = emaFast[idx] - emaSlow[idx]
+	}
+	dea := computeEMA(diff, signal)
+	hist := make([]float64, len(closes))
+	for idx := range closes {
+		hist[idx] = 2.0 * (diff[idx] - dea[idx])
 	}
 
-	return ema12, ema26, diff, dea, hist
+	return emaFast, emaSlow, diff, dea, hist
 }
 
 // ComputeMACDOne wraps computeMACDOne to return typed MACD object.
 func ComputeMACDOne(thisClose float64, lastMACD MACD) MACD {
-	ema12, ema26, diff, dea, hist := computeMACDOne(thisClose, lastMACD.Ema12, lastMACD.Ema26, lastMACD.Dea)
+	macd, _ := ComputeMACDOneWith(thisClose, lastMACD, DefaultMACDParams())
+	return macd
+}
+
+// ComputeMACDOneWith calculates a single MACD from previous values for given periods.
+func ComputeMACDOneWith(thisClose float64, lastMACD MACD, params MACDParams) (MACD, error) {
+	if err := params.validate(); err != nil {
+		return MACD{}, err
+	}
+
+	emaFast, emaSlow, diff, dea, hist := computeMACDOneWith(
+		thisClose, lastMACD.Ema12, lastMACD.Ema26, lastMACD.Dea,
+		float64(params.Fast), float64(params.Slow), float64(params.Signal),
+	)
 
 	return MACD{
-		Ema12: ema12,
-		Ema26: ema26,
+		Ema12: emaFast,
+		Ema26: emaSlow,
 		Diff:  diff,
 		Dea:   dea,
 		Hist:  hist,
-	}
+	}, nil
 }
 
 // computeMACDOne calculates a single MACD from previous values.
 func computeMACDOne(thisClose, lastEma12, lastEma26, lastDea float64) (float64, float64, float64, float64, float64) {
-	ema12 := (lastEma12*11.0 + thisClose*2.0) / 13.0
-	ema26 := (lastEma26*25.0 + thisClose*2.0) / 27.0
-	diff := ema12 - ema26
-	dea := (lastDea*8.0 + diff*2.0) / 10.0
+	return computeMACDOneWith(thisClose, lastEma12, lastEma26, lastDea, 12.0, 26.0, 9.0)
+}
+
+// computeMACDOneWith calculates a single MACD from previous values for given periods.
+func computeMACDOneWith(thisClose, lastEmaFast, lastEmaSlow, lastDea, fast, slow, signal float64) (float64, float64, float64, float64, float64) { //nolint:lll
+	emaFast := computeEMAOne(thisClose, lastEmaFast, fast)
+	emaSlow := computeEMAOne(thisClose, lastEmaSlow, slow)
+	diff := emaFast - emaSlow
+	dea := computeEMAOne(diff, lastDea, signal)
 	hist := 2.0 * (diff - dea)
 
-	return ema12, ema26, diff, dea, hist
+	return emaFast, emaSlow, diff, dea, hist
 }
 
 // RSI is valueobject holding all values for RSI for a given day.
@@ -222,12 +369,46 @@ type RSI struct {
 	RsLoss float64 `db:"rsloss" json:"rsloss"`
 }
 
+// RSIParams holds the smoothing period of RSI.
+type RSIParams struct {
+	N int `json:"n"`
+}
+
+// DefaultRSIParams returns the RSI(6) preset.
+func DefaultRSIParams() RSIParams {
+	return RSIParams{
+		N: 6,
+	}
+}
+
+func (p RSIParams) validate() error {
+	if p.N <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of RSI, once N price changes are seen.
+func (p RSIParams) Warmup() int {
+	return p.N
+}
+
 // ComputeRSI wraps computeRSI to produce RSI typed objects.
 func ComputeRSI(candles []OHLC) []RSI {
+	rsi, _ := ComputeRSIWith(candles, DefaultRSIParams())
+	return rsi
+}
+
+// ComputeRSIWith calculates RSI of close prices for given period.
+func ComputeRSIWith(candles []OHLC, params RSIParams) ([]RSI, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
 	closes := OHLC2Close(candles)
 	output := make([]RSI, len(closes))
 
-	rsi, rsGains, rsLosses := computeRSI(closes)
+	rsi, rsGains, rsLosses := computeRSIWith(closes, float64(params.N))
 
 	for idx := range closes {
 		output[idx].Rsi = rsi[idx]
@@ -235,17 +416,35 @@ func ComputeRSI(candles []OHLC) []RSI {
 		output[idx].RsLoss = rsLosses[idx]
 	}
 
-	return output
+	return output, nil
+}
+
+// ComputeRSISeries calculates RSI for given period with its warm-up.
+func ComputeRSISeries(candles []OHLC, params RSIParams) (Series[RSI], error) {
+	rsi, err := ComputeRSIWith(candles, params)
+	if err != nil {
+		return Series[RSI]{}, err
+	}
+
+	return NewSeries(rsi, params.Warmup()), nil
 }
 
 // computeRSI calculates all RSI for a given time seris close prices.
 func computeRSI(closes []float64) ([]float64, []float64, []float64) {
-	n := 6.0
+	return computeRSIWith(closes, 6.0)
+}
+
+// computeRSIWith calculates all RSI for given close prices and period.
+func computeRSIWith(closes []float64, n float64) ([]float64, []float64, []float64) {
 	pastN := n - 1.0
 	rsi := make([]float64, len(closes))
 	rsGains := make([]float64, len(closes))
 	rsLosses := make([]float64, len(closes))
 
+	if len(closes) == 0 {
+		return rsi, rsGains, rsLosses
+	}
+
 	rsi[0] = 0.0
 	rsGains[0] = 0.0
 	rsLosses[0] = 0.0
@@ -264,9 +463,11 @@ func computeRSI(closes []float64) ([]float64, []float64, []float64) {
 		rsGains[idx] = thisRsGain
 		rsLosses[idx] = thisRsLoss
 
-		if thisRsGain == 0.0 || thisRsLoss == 0.0 {
-			rsi[idx] = 0.0
-		} else {
+		switch {
+		case thisRsLoss == 0.0 && thisRsGain > 0.0:
+			// Only gains over the period, RS is infinite.
+			rsi[idx] = 100.0
+		case thisRsGain != 0.0 && thisRsLoss != 0.0:
 			rs := thisRsGain / thisRsLoss
 			rsi[idx] = (rs / (1.0 + rs)) * 100.0
 		}
@@ -277,18 +478,32 @@ func computeRSI(closes []float64) ([]float64, []float64, []float64) {
 
 // ComputeRSIOne wraps computeRSIOne to return typed object.
 func ComputeRSIOne(thisClose, lastClose float64, lastRSI RSI) RSI {
-	lastRsi, lastRsGain, lastRsLoss := computeRSIOne(thisClose, lastClose, lastRSI.RsGain, lastRSI.RsLoss)
+	rsi, _ := ComputeRSIOneWith(thisClose, lastClose, lastRSI, DefaultRSIParams())
+	return rsi
+}
 
-	return RSI{
-		Rsi:    lastRsi,
-		RsGain: lastRsGain,
-		RsLoss: lastRsLoss,
+// ComputeRSIOneWith calculates a single RSI from previous values for given period.
+func ComputeRSIOneWith(thisClose, lastClose float64, lastRSI RSI, params RSIParams) (RSI, error) {
+	if err := params.validate(); err != nil {
+		return RSI{}, err
 	}
+
+	rsi, rsGain, rsLoss := computeRSIOneWith(thisClose, lastClose, lastRSI.RsGain, lastRSI.RsLoss, float64(params.N))
+
+	return RSI{
+		Rsi:    rsi,
+		RsGain: rsGain,
+		RsLoss: rsLoss,
+	}, nil
 }
 
 // computeRSIOne calculates a single RSI from previous values.
 func computeRSIOne(thisClose, lastClose, lastRsGain, lastRsLoss float64) (float64, float64, float64) {
-	n := 6.0
+	return computeRSIOneWith(thisClose, lastClose, lastRsGain, lastRsLoss, 6.0)
+}
+
+// computeRSIOneWith calculates a single RSI from previous values for given period.
+func computeRSIOneWith(thisClose, lastClose, lastRsGain, lastRsLoss, n float64) (float64, float64, float64) {
 	pastN := n - 1.0
 
 	gain := 0.0
@@ -304,7 +519,11 @@ func computeRSIOne(thisClose, lastClose, lastRsGain, lastRsLoss float64) (float6
 
 	thisRsGain := (lastRsGain*pastN + gain) / n
 	thisRsLoss := (lastRsLoss*pastN + loss) / n
-	if thisRsGain != 0.0 || thisRsLoss != 0.0 {
+	switch {
+	case thisRsLoss == 0.0 && thisRsGain > 0.0:
+		// Only gains over the period, RS is infinite.
+		rsi = 100.0
+	case thisRsGain != 0.0 && thisRsLoss != 0.0:
 		rs := thisRsGain / thisRsLoss
 		rsi = (rs / (1.0 + rs)) * 100.0
 	}
@@ -320,14 +539,53 @@ type KDJ struct {
 	J   float64 `db:"j" json:"j"`
 }
 
+// KDJParams holds the RSV window N and the K/D smoothing periods M1/M2.
+type KDJParams struct {
+	N  int `json:"n"`
+	M1 int `json:"m1"`
+	M2 int `json:"m2"`
+}
+
+// DefaultKDJParams returns the KDJ(9,3,3) preset.
+func DefaultKDJParams() KDJParams {
+	return KDJParams{
+		N:  9,
+		M1: 3,
+		M2: 3,
+	}
+}
+
+func (p KDJParams) validate() error {
+	if p.N <= 0 || p.M1 <= 0 || p.M2 <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of KDJ: RSV needs N days, then K and D
+// need M1 and M2 more days to move off their seed of 50.
+func (p KDJParams) Warmup() int {
+	return p.N - 1 + p.M1 + p.M2
+}
+
 // ComputeKDJ wraps computeKDJ to produce typed objects.
 func ComputeKDJ(candles []OHLC) []KDJ {
+	kdj, _ := ComputeKDJWith(candles, DefaultKDJParams())
+	return kdj
+}
+
+// ComputeKDJWith calculates KDJ for given periods.
+func ComputeKDJWith(candles []OHLC, params KDJParams) ([]KDJ, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
 	closes := OHLC2Close(candles)
 	highs := OHLC2High(candles)
 	lows := OHLC2Low(candles)
 	kdj := make([]KDJ, len(closes))
 
-	rsv, k, d, j := computeKDJ(closes, highs, lows)
+	rsv, k, d, j := computeKDJWith(closes, highs, lows, params.N, float64(params.M1), float64(params.M2))
 
 	for idx := range closes {
 		kdj[idx].Rsv = rsv[idx]
@@ -335,55 +593,37 @@ func ComputeKDJ(candles []OHLC) []KDJ {
 		kdj[idx].D = d[idx]
 		kdj[idx].J = j[idx]
 	}
-	return kdj
+
+	return kdj, nil
+}
+
+// ComputeKDJSeries calculates KDJ for given periods with its warm-up.
+func ComputeKDJSeries(candles []OHLC, params KDJParams) (Series[KDJ], error) {
+	kdj, err := ComputeKDJWith(candles, params)
+	if err != nil {
+		return Series[KDJ]{}, err
+	}
+
+	return NewSeries(kdj, params.Warmup()), nil
 }
 
 // computeKDJ calculates all KDJ for a given time seris close prices.
 func computeKDJ(closes, highs, lows []float64) ([]float64, []float64, []float64, []float64) {
+	return computeKDJWith(closes, highs, lows, 9, 3.0, 3.0)
+}
+
+// computeKDJWith calculates all KDJ for given prices and periods.
+func computeKDJWith(closes, highs, lows []float64, n int, m1, m2 float64) ([]float64, []float64, []float64, []float64) { //nolint:lll
 	rsv := make([]float64, len(closes))
 	k := make([]float64, len(closes))
 	d := make([]float64, len(closes))
 	j := make([]float64, len(closes))
 
-	getRange := func(idx int, values []float64) []float64 {
-		var start int
-		diff := idx + 1 - 9
-		if diff > 0 {
-			start = diff
-		} else {
-			start = 0
-		}
-		end := idx + 1
-
-		return values[start:end]
-	}
-
-	reduceLowest := func(values []float64) float64 {
-		lowest := 9999.0
-		for _, value := range values {
-			if lowest < value {
-				continue
-			}
-			lowest = value
-		}
-		return lowest
-	}
-
-	reduceHighest := func(values []float64) float64 {
-		highest := -1.0
-		for _, value := range values {
-			if highest > value {
-				continue
-			}
-			highest = value
-		}
-		return highest
-	}
-
 	for idx := range closes {
+		start := max(idx+1-n, 0)
 		c := closes[idx]
-		l := reduceLowest(getRange(idx, lows))
-		h := reduceHighest(getRange(idx, highs))
+		l := lo.Min(lows[start : idx+1])
+		h := lo.Max(highs[start : idx+1])
 		thisRSV := ((c - l) / (h - l)) * 100.0
 		rsv[idx] = thisRSV
 
@@ -396,8 +636,8 @@ func computeKDJ(closes, highs, lows []float64) ([]float64, []float64, []float64,
 			thisD = 50.0
 			thisJ = 50.0
 		} else {
-			thisK = (2.0*k[idx-1] + thisRSV) / 3.0
-			thisD = (2.0*d[idx-1] + thisK) / 3.0
+			thisK = ((m1-1.0)*k[idx-1] + thisRSV) / m1
+			thisD = ((m2-1.0)*d[idx-1] + thisK) / m2
 			thisJ = 3.0*thisK - 2.0*thisD
 		}
 
@@ -411,55 +651,403 @@ func computeKDJ(closes, highs, lows []float64) ([]float64, []float64, []float64,
 
 // ComputeKDJOne wraps computeKDJ to return typed object.
 func ComputeKDJOne(candles []OHLC, lastK, lastD float64) KDJ {
+	kdj, _ := ComputeKDJOneWith(candles, lastK, lastD, DefaultKDJParams())
+	return kdj
+}
+
+// ComputeKDJOneWith calculates a single KDJ from previous values for given periods.
+func ComputeKDJOneWith(candles []OHLC, lastK, lastD float64, params KDJParams) (KDJ, error) {
+	if err := params.validate(); err != nil {
+		return KDJ{}, err
+	}
+	if len(candles) < params.N {
+		return KDJ{}, ErrNotEnoughCandles
+	}
+
 	closes := OHLC2Close(candles)
 	highs := OHLC2High(candles)
 	lows := OHLC2Low(candles)
 
-	rsv, k, d, j := computeKDJOne(closes, highs, lows, lastK, lastD)
+	rsv, k, d, j := computeKDJOneWith(closes, highs, lows, lastK, lastD, params.N, float64(params.M1), float64(params.M2))
 
 	return KDJ{
 		Rsv: rsv,
 		K:   k,
 		D:   d,
 		J:   j,
-	}
+	}, nil
 }
 
 // computeKDJOne calculates a single KDJ from previous values.
 func computeKDJOne(closes, highs, lows []float64, lastK, lastD float64) (float64, float64, float64, float64) {
-	n := 9
-
-	reduceLowest := func(values []float64) float64 {
-		lowest := 9999.0
-		for _, value := range values {
-			if lowest < value {
-				continue
-			}
-			lowest = value
-		}
-		return lowest
-	}
-
-	reduceHighest := func(values []float64) float64 {
-		highest := -1.0
-		for _, value := range values {
-			if highest > value {
-				continue
-			}
-			highest = value
-		}
-		return highest
-	}
+	return computeKDJOneWith(closes, highs, lows, lastK, lastD, 9, 3.0, 3.0)
+}
 
+// computeKDJOneWith calculates a single KDJ from previous values for given periods.
+func computeKDJOneWith(closes, highs, lows []float64, lastK, lastD float64, n int, m1, m2 float64) (float64, float64, float64, float64) { //nolint:lll
 	total := len(closes)
 
 	c := closes[total-1]
-	l := reduceLowest(lows[total-n:])
-	h := reduceHighest(highs[total-n:])
+	l := lo.Min(lows[total-n:])
+	h := lo.Max(highs[total-n:])
 	thisRSV := ((c - l) / (h - l)) * 100.0
-	thisK := (2.0*lastK + thisRSV) / 3.0
-	thisD := (2.0*lastD + thisK) / 3.0
+	thisK := ((m1-1.0)*lastK + thisRSV) / m1
+	thisD := ((m2-1.0)*lastD + thisK) / m2
 	thisJ := 3.0*thisK - 2.0*thisD
 
 	return thisRSV, thisK, thisD, thisJ
 }
+
+// Bollinger is valueobject holding all values for Bollinger Bands for a given day.
+type Bollinger struct {
+	Mid       float64 `db:"mid" json:"mid"`
+	Upper     float64 `db:"upper" json:"upper"`
+	Lower     float64 `db:"lower" json:"lower"`
+	PercentB  float64 `db:"percentb" json:"percentb"`
+	Bandwidth float64 `db:"bandwidth" json:"bandwidth"`
+}
+
+// BollingerParams holds the window N and the band width K in standard deviations.
+type BollingerParams struct {
+	N int     `json:"n"`
+	K float64 `json:"k"`
+}
+
+// DefaultBollingerParams returns the BOLL(20,2) preset.
+func DefaultBollingerParams() BollingerParams {
+	return BollingerParams{
+		N: 20,
+		K: 2.0,
+	}
+}
+
+func (p BollingerParams) validate() error {
+	if p.N <= 0 || p.K <= 0.0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of Bollinger Bands.
+func (p BollingerParams) Warmup() int {
+	return p.N - 1
+}
+
+// ComputeBollinger wraps computeBollinger to produce typed objects.
+func ComputeBollinger(candles []OHLC) []Bollinger {
+	boll, _ := ComputeBollingerWith(candles, DefaultBollingerParams())
+	return boll
+}
+
+// ComputeBollingerWith calculates Bollinger Bands of close prices for given params.
+func ComputeBollingerWith(candles []OHLC, params BollingerParams) ([]Bollinger, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	closes := OHLC2Close(candles)
+	boll := make([]Bollinger, len(closes))
+
+	mid, upper, lower, percentB, bandwidth := computeBollinger(closes, params.N, params.K)
+
+	for idx := range closes {
+		boll[idx].Mid = mid[idx]
+		boll[idx].Upper = upper[idx]
+		boll[idx].Lower = lower[idx]
+		boll[idx].PercentB = percentB[idx]
+		boll[idx].Bandwidth = bandwidth[idx]
+	}
+
+	return boll, nil
+}
+
+// ComputeBollingerSeries calculates Bollinger Bands for given params with its warm-up.
+func ComputeBollingerSeries(candles []OHLC, params BollingerParams) (Series[Bollinger], error) {
+	bollinger, err := ComputeBollingerWith(candles, params)
+	if err != nil {
+		return Series[Bollinger]{}, err
+	}
+
+	return NewSeries(bollinger, params.Warmup()), nil
+}
+
+// computeBollinger calculates all Bollinger Bands for given close prices.
+// The first n-1 days use the closes available so far.
+func computeBollinger(closes []float64, n int, k float64) ([]float64, []float64, []float64, []float64, []float64) { //nolint:lll
+	mid := make([]float64, len(closes))
+	upper := make([]float64, len(closes))
+	lower := make([]float64, len(closes))
+	percentB := make([]float64, len(closes))
+	bandwidth := make([]float64, len(closes))
+
+	for idx := range closes {
+		start := max(idx+1-n, 0)
+		mid[idx], upper[idx], lower[idx], percentB[idx], bandwidth[idx] = computeBollingerOne(closes[start:idx+1], k)
+	}
+
+	return mid, upper, lower, percentB, bandwidth
+}
+
+// ComputeBollingerOne wraps computeBollingerOne to return typed object.
+func ComputeBollingerOne(candles []OHLC) Bollinger {
+	boll, _ := ComputeBollingerOneWith(candles, DefaultBollingerParams())
+	return boll
+}
+
+// ComputeBollingerOneWith calculates Bollinger Bands for the last candle given params.
+func ComputeBollingerOneWith(candles []OHLC, params BollingerParams) (Bollinger, error) {
+	if err := params.validate(); err != nil {
+		return Bollinger{}, err
+	}
+	if len(candles) < params.N {
+		return Bollinger{}, ErrNotEnoughCandles
+	}
+
+	closes := OHLC2Close(candles)
+	mid, upper, lower, percentB, bandwidth := computeBollingerOne(closes[len(closes)-params.N:], params.K)
+
+	return Bollinger{
+		Mid:       mid,
+		Upper:     upper,
+		Lower:     lower,
+		PercentB:  percentB,
+		Bandwidth: bandwidth,
+	}, nil
+}
+
+// computeBollingerOne calculates Bollinger Bands for the last close of the window,
+// using population standard deviation.
+func computeBollingerOne(window []float64, k float64) (float64, float64, float64, float64, float64) {
+	n := float64(len(window))
+	mid := lo.Sum(window) / n
+
+	variance := 0.0
+	for _, value := range window {
+		variance += (value - mid) * (value - mid)
+	}
+	std := math.Sqrt(variance / n)
+
+	upper := mid + k*std
+	lower := mid - k*std
+
+	// Flat window puts the close right on the middle band.
+	percentB := 0.5
+	if upper != lower {
+		percentB = (window[len(window)-1] - lower) / (upper - lower)
+	}
+	bandwidth := 0.0
+	if mid != 0.0 {
+		bandwidth = (upper - lower) / mid
+	}
+
+	return mid, upper, lower, percentB, bandwidth
+}
+
+// ATR is valueobject holding true range and Average True Range for a given day.
+type ATR struct {
+	TR  float64 `db:"tr" json:"tr"`
+	Atr float64 `db:"atr" json:"atr"`
+}
+
+// ATRParams holds the Wilder smoothing period of ATR.
+type ATRParams struct {
+	N int `json:"n"`
+}
+
+// DefaultATRParams returns the ATR(14) preset.
+func DefaultATRParams() ATRParams {
+	return ATRParams{
+		N: 14,
+	}
+}
+
+func (p ATRParams) validate() error {
+	if p.N <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of ATR, once N true ranges are seen.
+func (p ATRParams) Warmup() int {
+	return p.N
+}
+
+// ComputeATR wraps computeATR to produce typed objects.
+func ComputeATR(candles []OHLC) []ATR {
+	atr, _ := ComputeATRWith(candles, DefaultATRParams())
+	return atr
+}
+
+// ComputeATRWith calculates ATR for given period.
+func ComputeATRWith(candles []OHLC, params ATRParams) ([]ATR, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	tr, atr := computeATR(OHLC2Close(candles), OHLC2High(candles), OHLC2Low(candles), float64(params.N))
+
+	output := make([]ATR, len(candles))
+	for idx := range candles {
+		output[idx].TR = tr[idx]
+		output[idx].Atr = atr[idx]
+	}
+
+	return output, nil
+}
+
+// ComputeATRSeries calculates ATR for given params with its warm-up.
+func ComputeATRSeries(candles []OHLC, params ATRParams) (Series[ATR], error) {
+	atr, err := ComputeATRWith(candles, params)
+	if err != nil {
+		return Series[ATR]{}, err
+	}
+
+	return NewSeries(atr, params.Warmup()), nil
+}
+
+// computeATR calculates all true ranges and ATR, seeded by the first day's high-low range.
+func computeATR(closes, highs, lows []float64, n float64) ([]float64, []float64) {
+	tr := make([]float64, len(closes))
+	atr := make([]float64, len(closes))
+
+	for idx := range closes {
+		if idx == 0 {
+			tr[idx] = highs[idx] - lows[idx]
+			atr[idx] = tr[idx]
+			continue
+		}
+		tr[idx], atr[idx] = computeATROne(highs[idx], lows[idx], closes[idx-1], atr[idx-1], n)
+	}
+
+	return tr, atr
+}
+
+// ComputeATROne wraps computeATROne to return typed object.
+func ComputeATROne(candle OHLC, lastClose float64, lastATR ATR) ATR {
+	atr, _ := ComputeATROneWith(candle, lastClose, lastATR, DefaultATRParams())
+	return atr
+}
+
+// ComputeATROneWith calculates a single ATR from previous values for given period.
+func ComputeATROneWith(candle OHLC, lastClose float64, lastATR ATR, params ATRParams) (ATR, error) {
+	if err := params.validate(); err != nil {
+		return ATR{}, err
+	}
+
+	tr, atr := computeATROne(candle.High, candle.Low, lastClose, lastATR.Atr, float64(params.N))
+
+	return ATR{
+		TR:  tr,
+		Atr: atr,
+	}, nil
+}
+
+// computeATROne calculates a single true range and ATR from previous values.
+func computeATROne(high, low, lastClose, lastAtr, n float64) (float64, float64) {
+	tr := computeTrueRange(high, low, lastClose)
+	atr := ((n-1.0)*lastAtr + tr) / n
+
+	return tr, atr
+}
+
+// computeTrueRange calculates the greatest of high-low and the gaps from previous close.
+func computeTrueRange(high, low, lastClose float64) float64 {
+	return max(high-low, math.Abs(high-lastClose), math.Abs(low-lastClose))
+}
+
+// Keltner is valueobject holding all values for Keltner Channels for a given day.
+type Keltner struct {
+	Mid   float64 `db:"mid" json:"mid"`
+	Upper float64 `db:"upper" json:"upper"`
+	Lower float64 `db:"lower" json:"lower"`
+	Atr   float64 `db:"atr" json:"atr"`
+}
+
+// KeltnerParams holds the EMA period of the middle line, the ATR period and the ATR multiplier.
+type KeltnerParams struct {
+	N    int     `json:"n"`
+	ATRN int     `json:"atrn"`
+	Mult float64 `json:"mult"`
+}
+
+// DefaultKeltnerParams returns the KC(20,10,2) preset.
+func DefaultKeltnerParams() KeltnerParams {
+	return KeltnerParams{
+		N:    20,
+		ATRN: 10,
+		Mult: 2.0,
+	}
+}
+
+func (p KeltnerParams) validate() error {
+	if p.N <= 0 || p.ATRN <= 0 || p.Mult <= 0.0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of Keltner Channels.
+func (p KeltnerParams) Warmup() int {
+	return max(p.N-1, p.ATRN)
+}
+
+// ComputeKeltner wraps computeKeltner to produce typed objects.
+func ComputeKeltner(candles []OHLC) []Keltner {
+	kc, _ := ComputeKeltnerWith(candles, DefaultKeltnerParams())
+	return kc
+}
+
+// ComputeKeltnerWith calculates Keltner Channels for given params.
+func ComputeKeltnerWith(candles []OHLC, params KeltnerParams) ([]Keltner, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	closes := OHLC2Close(candles)
+	mid := computeEMA(closes, float64(params.N))
+	_, atr := computeATR(closes, OHLC2High(candles), OHLC2Low(candles), float64(params.ATRN))
+
+	kc := make([]Keltner, len(closes))
+	for idx := range closes {
+		kc[idx].Mid = mid[idx]
+		kc[idx].Upper = mid[idx] + params.Mult*atr[idx]
+		kc[idx].Lower = mid[idx] - params.Mult*atr[idx]
+		kc[idx].Atr = atr[idx]
+	}
+
+	return kc, nil
+}
+
+// ComputeKeltnerSeries calculates Keltner Channels for given params with its warm-up.
+func ComputeKeltnerSeries(candles []OHLC, params KeltnerParams) (Series[Keltner], error) {
+	keltner, err := ComputeKeltnerWith(candles, params)
+	if err != nil {
+		return Series[Keltner]{}, err
+	}
+
+	return NewSeries(keltner, params.Warmup()), nil
+}
+
+// ComputeKeltnerOne calculates a single Keltner Channels from previous values.
+func ComputeKeltnerOne(candle OHLC, lastClose float64, lastKeltner Keltner) Keltner {
+	kc, _ := ComputeKeltnerOneWith(candle, lastClose, lastKeltner, DefaultKeltnerParams())
+	return kc
+}
+
+// ComputeKeltnerOneWith calculates a single Keltner Channels from previous values for given params.
+func ComputeKeltnerOneWith(candle OHLC, lastClose float64, lastKeltner Keltner, params KeltnerParams) (Keltner, error) {
+	if err := params.validate(); err != nil {
+		return Keltner{}, err
+	}
+
+	mid := computeEMAOne(candle.Close, lastKeltner.Mid, float64(params.N))
+	_, atr := computeATROne(candle.High, candle.Low, lastClose, lastKeltner.Atr, float64(params.ATRN))
+
+	return Keltner{
+		Mid:   mid,
+		Upper: mid + params.Mult*atr,
+		Lower: mid - params.Mult*atr,
+		Atr:   atr,
+	}, nil
+}
@@ -0,0 +1,253 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// SwingKind tells a swing high from a swing low.
+type SwingKind string
+
+const (
+	SwingHigh SwingKind = "high"
+	SwingLow  SwingKind = "low"
+)
+
+// Swing is valueobject of a local price extreme. Price is the high (low) of a swing high (low)
+// and Close the close of that day; Index is the position in the candles it was found in.
+type Swing struct {
+	Index int       `json:"-"`
+	Date  string    `json:"date"`
+	Kind  SwingKind `json:"kind"`
+	Price float64   `json:"price"`
+	Close float64   `json:"close"`
+}
+
+// SwingParams holds the number of bars on each side a swing must exceed.
+type SwingParams struct {
+	N int `json:"n"`
+}
+
+// DefaultSwingParams returns the 3-bar swing preset.
+func DefaultSwingParams() SwingParams {
+	return SwingParams{
+		N: 3,
+	}
+}
+
+// ComputeSwings wraps ComputeSwingsWith with the default bars.
+func ComputeSwings(candles []OHLC) []Swing {
+	swings, _ := ComputeSwingsWith(candles, DefaultSwingParams())
+	return swings
+}
+
+// ComputeSwingsWith finds swing highs and lows in date order. A swing must be strictly
+// above (below) the N bars before it and not below (above) the N bars after it, hence
+// the last N candles never hold a confirmed swing.
+func ComputeSwingsWith(candles []OHLC, params SwingParams) ([]Swing, error) {
+	if params.N <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	swings := make([]Swing, 0)
+	for idx := params.N; idx < len(candles)-params.N; idx++ {
+		isHigh, isLow := true, true
+		for off := 1; off <= params.N; off++ {
+			before, after := candles[idx-off], candles[idx+off]
+			if candles[idx].High <= before.High || candles[idx].High < after.High {
+				isHigh = false
+			}
+			if candles[idx].Low >= before.Low || candles[idx].Low > after.Low {
+				isLow = false
+			}
+		}
+		if isHigh {
+			swings = append(swings, Swing{
+				Index: idx,
+				Date:  candles[idx].Date,
+				Kind:  SwingHigh,
+				Price: candles[idx].High,
+				Close: candles[idx].Close,
+			})
+		}
+		if isLow {
+			swings = append(swings, Swing{
+				Index: idx,
+				Date:  candles[idx].Date,
+				Kind:  SwingLow,
+				Price: candles[idx].Low,
+				Close: candles[idx].Close,
+			})
+		}
+	}
+
+	return swings, nil
+}
+
+// Indicator names the oscillators divergence is looked for on.
+const (
+	IndicatorMACD = "macd"
+	IndicatorRSI  = "rsi"
+	IndicatorKDJ  = "kdj"
+)
+
+// Divergence is valueobject of price and indicator moving apart between two swings.
+// Regular divergence (Hidden false) hints reversal: bullish when close makes a lower low
+// while the indicator makes a higher low. Hidden divergence hints continuation: bullish
+// when close makes a higher low while the indicator makes a lower low. Bearish mirrors on highs.
+type Divergence struct {
+	Start          string           `json:"start"`
+	End            string           `json:"end"`
+	Indicator      string           `json:"indicator"`
+	Direction      PatternDirection `json:"direction"`
+	Hidden         bool             `json:"hidden"`
+	PriceStart     float64          `json:"pricestart"`
+	PriceEnd       float64          `json:"priceend"`
+	IndicatorStart float64          `json:"indicatorstart"`
+	IndicatorEnd   float64          `json:"indicatorend"`
+}
+
+// DivergenceParams holds the swing bars and the bar span allowed between the two swings.
+type DivergenceParams struct {
+	Swing   int `json:"swing"`
+	MinSpan int `json:"minspan"`
+	MaxSpan int `json:"maxspan"`
+}
+
+// DefaultDivergenceParams returns swings of 3 bars compared 5 to 60 bars apart.
+func DefaultDivergenceParams() DivergenceParams {
+	return DivergenceParams{
+		Swing:   3,
+		MinSpan: 5,
+		MaxSpan: 60,
+	}
+}
+
+func (p DivergenceParams) validate() error {
+	if p.Swing <= 0 || p.MinSpan <= 0 || p.MaxSpan < p.MinSpan {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// ComputeDivergences finds divergences of MACD histogram, RSI and KDJ J against close with
+// default periods, skipping each indicator's warm-up, ordered by end date.
+func ComputeDivergences(candles []OHLC) []Divergence {
+	params := DefaultDivergenceParams()
+
+	macd := withWarmup(lo.Map(ComputeMACD(candles), func(m MACD, _ int) float64 {
+		return m.Hist
+	}), DefaultMACDParams().Warmup())
+	rsi := withWarmup(lo.Map(ComputeRSI(candles), func(r RSI, _ int) float64 {
+		return r.Rsi
+	}), DefaultRSIParams().Warmup())
+	kdj := withWarmup(lo.Map(ComputeKDJ(candles), func(k KDJ, _ int) float64 {
+		return k.J
+	}), DefaultKDJParams().Warmup())
+
+	divergences := make([]Divergence, 0)
+	for _, input := range []struct {
+		name   string
+		values []float64
+	}{
+		{IndicatorMACD, macd},
+		{IndicatorRSI, rsi},
+		{IndicatorKDJ, kdj},
+	} {
+		found, _ := FindDivergencesWith(candles, input.values, input.name, params)
+		divergences = append(divergences, found...)
+	}
+
+	slices.SortStableFunc(divergences, func(a, b Divergence) int {
+		return strings.Compare(a.End, b.End)
+	})
+
+	return divergences
+}
+
+// FindDivergencesWith compares consecutive swings of the same kind against indicator values
+// aligned with candles. NaN indicator values at either swing are skipped.
+func FindDivergencesWith(candles []OHLC, indicator []float64, name string, params DivergenceParams) ([]Divergence, error) { //nolint:lll
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	if len(indicator) != len(candles) {
+		return nil, ErrNotEnoughCandles
+	}
+
+	swings, err := ComputeSwingsWith(candles, SwingParams{N: params.Swing})
+	if err != nil {
+		return nil, err
+	}
+
+	divergences := make([]Divergence, 0)
+	for _, kind := range []SwingKind{SwingLow, SwingHigh} {
+		same := lo.Filter(swings, func(s Swing, _ int) bool {
+			return s.Kind == kind
+		})
+		for idx := 1; idx < len(same); idx++ {
+			prev, this := same[idx-1], same[idx]
+			span := this.Index - prev.Index
+			if span < params.MinSpan || span > params.MaxSpan {
+				continue
+			}
+			indPrev, indThis := indicator[prev.Index], indicator[this.Index]
+			if math.IsNaN(indPrev) || math.IsNaN(indThis) {
+				continue
+			}
+			if divergence, ok := compareSwings(kind, prev, this, indPrev, indThis); ok {
+				divergence.Indicator = name
+				divergences = append(divergences, divergence)
+			}
+		}
+	}
+
+	slices.SortStableFunc(divergences, func(a, b Divergence) int {
+		return strings.Compare(a.End, b.End)
+	})
+
+	return divergences, nil
+}
+
+func compareSwings(kind SwingKind, prev, this Swing, indPrev, indThis float64) (Divergence, bool) {
+	divergence := Divergence{
+		Start:          prev.Date,
+		End:            this.Date,
+		PriceStart:     prev.Close,
+		PriceEnd:       this.Close,
+		IndicatorStart: indPrev,
+		IndicatorEnd:   indThis,
+	}
+
+	priceUp, indUp := this.Close > prev.Close, indThis > indPrev
+	priceDown, indDown := this.Close < prev.Close, indThis < indPrev
+
+	switch {
+	case kind == SwingLow && priceDown && indUp:
+		divergence.Direction = DirectionBullish
+	case kind == SwingLow && priceUp && indDown:
+		divergence.Direction = DirectionBullish
+		divergence.Hidden = true
+	case kind == SwingHigh && priceUp && indDown:
+		divergence.Direction = DirectionBearish
+	case kind == SwingHigh && priceDown && indUp:
+		divergence.Direction = DirectionBearish
+		divergence.Hidden = true
+	default:
+		return Divergence{}, false
+	}
+
+	return divergence, true
+}
+
+// withWarmup blanks values before warmup as NaN.
+func withWarmup(values []float64, warmup int) []float64 {
+	for idx := range min(warmup, len(values)) {
+		values[idx] = math.NaN()
+	}
+	return values
+}
@@ -0,0 +1,102 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"math"
+	"testing"
+
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// closesToCandles builds daily candles whose high, low and close all equal given closes.
+func closesToCandles(closes []float64) []OHLC {
+	candles := make([]OHLC, len(closes))
+	for idx, c := range closes {
+		candles[idx] = OHLC{
+			Date:  stocktest.Date(idx),
+			Open:  c,
+			High:  c,
+			Low:   c,
+			Close: c,
+		}
+	}
+	return candles
+}
+
+func TestComputeSwings(t *testing.T) {
+	candles := closesToCandles([]float64{5, 4, 3, 2, 3, 4, 5, 6, 7, 6, 5, 4, 3})
+
+	got := ComputeSwings(candles)
+
+	assert.Equal(t, []Swing{
+		{Index: 3, Date: candles[3].Date, Kind: SwingLow, Price: 2, Close: 2},
+		{Index: 8, Date: candles[8].Date, Kind: SwingHigh, Price: 7, Close: 7},
+	}, got)
+
+	_, err := ComputeSwingsWith(candles, SwingParams{N: 0})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestFindDivergences(t *testing.T) {
+	// Two swing lows 8 bars apart: close makes a lower low.
+	closes := []float64{10, 9, 8, 7, 8, 9, 10, 9, 8, 7, 6.5, 7.5, 8.5, 9.5, 10}
+	candles := closesToCandles(closes)
+	indicator := make([]float64, len(closes))
+	for idx := range indicator {
+		indicator[idx] = 50.0
+	}
+
+	// Indicator makes a higher low: regular bullish.
+	indicator[3], indicator[10] = 20.0, 30.0
+	got, err := FindDivergencesWith(candles, indicator, IndicatorRSI, DefaultDivergenceParams())
+	if err != nil {
+		t.Fatal("fail to run FindDivergencesWith()")
+	}
+	assert.Equal(t, []Divergence{{
+		Start:          candles[3].Date,
+		End:            candles[10].Date,
+		Indicator:      IndicatorRSI,
+		Direction:      DirectionBullish,
+		Hidden:         false,
+		PriceStart:     7.0,
+		PriceEnd:       6.5,
+		IndicatorStart: 20.0,
+		IndicatorEnd:   30.0,
+	}}, got)
+
+	// Indicator confirms price: no divergence.
+	indicator[10] = 10.0
+	got, _ = FindDivergencesWith(candles, indicator, IndicatorRSI, DefaultDivergenceParams())
+	assert.Empty(t, got)
+
+	// Warm-up values are skipped.
+	indicator[3], indicator[10] = math.NaN(), 30.0
+	got, _ = FindDivergencesWith(candles, indicator, IndicatorRSI, DefaultDivergenceParams())
+	assert.Empty(t, got)
+
+	// Swings too far apart are not compared.
+	indicator[3] = 20.0
+	got, _ = FindDivergencesWith(candles, indicator, IndicatorRSI, DivergenceParams{Swing: 3, MinSpan: 1, MaxSpan: 5})
+	assert.Empty(t, got)
+
+	_, err = FindDivergencesWith(candles, indicator[:3], IndicatorRSI, DefaultDivergenceParams())
+	assert.ErrorIs(t, err, ErrNotEnoughCandles)
+}
+
+func TestComputeDivergencesOnRealData(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	got := ComputeDivergences(ohlc)
+
+	for idx, d := range got {
+		assert.Less(t, d.Start, d.End)
+		assert.Contains(t, []string{IndicatorMACD, IndicatorRSI, IndicatorKDJ}, d.Indicator)
+		if idx > 0 {
+			assert.LessOrEqual(t, got[idx-1].End, d.End)
+		}
+	}
+}
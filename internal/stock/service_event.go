@@ -0,0 +1,172 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"github.com/samber/lo"
+)
+
+// EventKind names a crossover or breakout event.
+type EventKind string
+
+const (
+	EventMACDGoldenCross   EventKind = "macdgoldencross"
+	EventMACDDeathCross    EventKind = "macddeathcross"
+	EventKDGoldenCross     EventKind = "kdgoldencross"
+	EventKDDeathCross      EventKind = "kddeathcross"
+	EventCrossAboveSMA20   EventKind = "crossabovesma20"
+	EventCrossBelowSMA20   EventKind = "crossbelowsma20"
+	EventCrossAboveSMA60   EventKind = "crossabovesma60"
+	EventCrossBelowSMA60   EventKind = "crossbelowsma60"
+	EventRSIExitOversold   EventKind = "rsiexitoversold"
+	EventRSIExitOverbought EventKind = "rsiexitoverbought"
+	EventNewHigh           EventKind = "newhigh"
+	EventNewLow            EventKind = "newlow"
+)
+
+// EventKinds lists the events detected.
+var EventKinds = []EventKind{
+	EventMACDGoldenCross, EventMACDDeathCross, EventKDGoldenCross, EventKDDeathCross,
+	EventCrossAboveSMA20, EventCrossBelowSMA20, EventCrossAboveSMA60, EventCrossBelowSMA60,
+	EventRSIExitOversold, EventRSIExitOverbought, EventNewHigh, EventNewLow,
+}
+
+// Event is valueobject of an event firing on Date; Value is the indicator or price
+// crossing, e.g. DIFF for MACD crosses, K for KD crosses and close for SMA crosses.
+type Event struct {
+	Date      string           `json:"date"`
+	Kind      EventKind        `json:"kind"`
+	Direction PatternDirection `json:"direction"`
+	Value     float64          `json:"value"`
+}
+
+// EventParams holds RSI bounds and the window of new highs/lows.
+type EventParams struct {
+	Oversold   float64 `json:"oversold"`
+	Overbought float64 `json:"overbought"`
+	HighLow    int     `json:"highlow"`
+}
+
+// DefaultEventParams returns RSI(6) bounds of 20/80 and 20-day highs/lows.
+func DefaultEventParams() EventParams {
+	return EventParams{
+		Oversold:   20.0,
+		Overbought: 80.0,
+		HighLow:    20,
+	}
+}
+
+func (p EventParams) validate() error {
+	if p.HighLow <= 0 || p.Oversold >= p.Overbought {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// DetectEvents finds events over all candles, in date order.
+func DetectEvents(candles []OHLC) []Event {
+	events, _ := DetectEventsWith(candles, len(candles), DefaultEventParams())
+	return events
+}
+
+// DetectEventsRecent finds events firing on the last `days` candles.
+func DetectEventsRecent(candles []OHLC, days int) []Event {
+	events, _ := DetectEventsWith(candles, days, DefaultEventParams())
+	return events
+}
+
+// DetectEventsWith finds events firing on the last `days` candles for given params.
+// Crosses are only reported once both lines are past their warm-up.
+func DetectEventsWith(candles []OHLC, days int, params EventParams) ([]Event, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	closes := OHLC2Close(candles)
+	macd := ComputeMACD(candles)
+	kdj := ComputeKDJ(candles)
+	rsi := ComputeRSI(candles)
+	sma20, _ := ComputeSMAWith(candles, 20)
+	sma60, _ := ComputeSMAWith(candles, 60)
+
+	diff := lo.Map(macd, func(m MACD, _ int) float64 { return m.Diff })
+	dea := lo.Map(macd, func(m MACD, _ int) float64 { return m.Dea })
+	k := lo.Map(kdj, func(m KDJ, _ int) float64 { return m.K })
+	d := lo.Map(kdj, func(m KDJ, _ int) float64 { return m.D })
+	rsis := lo.Map(rsi, func(r RSI, _ int) float64 { return r.Rsi })
+
+	events := make([]Event, 0)
+	for idx := max(len(candles)-days, 1); idx < len(candles); idx++ {
+		date := candles[idx].Date
+		add := func(kind EventKind, direction PatternDirection, value float64) {
+			events = append(events, Event{
+				Date:      date,
+				Kind:      kind,
+				Direction: direction,
+				Value:     value,
+			})
+		}
+
+		if idx > DefaultMACDParams().Warmup() {
+			switch crossOf(diff, dea, idx) {
+			case 1:
+				add(EventMACDGoldenCross, DirectionBullish, diff[idx])
+			case -1:
+				add(EventMACDDeathCross, DirectionBearish, diff[idx])
+			}
+		}
+		if idx > DefaultKDJParams().Warmup() {
+			switch crossOf(k, d, idx) {
+			case 1:
+				add(EventKDGoldenCross, DirectionBullish, k[idx])
+			case -1:
+				add(EventKDDeathCross, DirectionBearish, k[idx])
+			}
+		}
+		if sma20 != nil && idx > 20 {
+			switch crossOf(closes, sma20, idx) {
+			case 1:
+				add(EventCrossAboveSMA20, DirectionBullish, closes[idx])
+			case -1:
+				add(EventCrossBelowSMA20, DirectionBearish, closes[idx])
+			}
+		}
+		if sma60 != nil && idx > 60 {
+			switch crossOf(closes, sma60, idx) {
+			case 1:
+				add(EventCrossAboveSMA60, DirectionBullish, closes[idx])
+			case -1:
+				add(EventCrossBelowSMA60, DirectionBearish, closes[idx])
+			}
+		}
+		if idx > DefaultRSIParams().Warmup() {
+			if rsis[idx-1] < params.Oversold && rsis[idx] >= params.Oversold {
+				add(EventRSIExitOversold, DirectionBullish, rsis[idx])
+			}
+			if rsis[idx-1] > params.Overbought && rsis[idx] <= params.Overbought {
+				add(EventRSIExitOverbought, DirectionBearish, rsis[idx])
+			}
+		}
+		if idx >= params.HighLow {
+			window := candles[idx-params.HighLow : idx]
+			if candles[idx].High > lo.Max(OHLC2High(window)) {
+				add(EventNewHigh, DirectionBullish, candles[idx].High)
+			}
+			if candles[idx].Low < lo.Min(OHLC2Low(window)) {
+				add(EventNewLow, DirectionBearish, candles[idx].Low)
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// crossOf returns 1 when fast crosses above slow at idx, -1 when it crosses below, 0 otherwise.
+func crossOf(fast, slow []float64, idx int) int {
+	switch {
+	case fast[idx-1] <= slow[idx-1] && fast[idx] > slow[idx]:
+		return 1
+	case fast[idx-1] >= slow[idx-1] && fast[idx] < slow[idx]:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+func hasEvent(events []Event, kind EventKind) bool {
+	for _, e := range events {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDetectEventsTurn(t *testing.T) {
+	// A long decline then a sharp rally crosses MACD, KD and SMA20 upwards and makes a new high.
+	candles := closesToCandles(stocktest.DeclineRally())
+
+	all := DetectEvents(candles)
+	assert.True(t, hasEvent(all, EventMACDGoldenCross))
+	assert.True(t, hasEvent(all, EventKDGoldenCross))
+	assert.True(t, hasEvent(all, EventCrossAboveSMA20))
+	assert.True(t, hasEvent(all, EventCrossAboveSMA60))
+	assert.True(t, hasEvent(all, EventNewHigh))
+	assert.True(t, hasEvent(all, EventNewLow))
+	assert.True(t, hasEvent(all, EventRSIExitOversold))
+	assert.False(t, hasEvent(all, EventMACDDeathCross))
+
+	for idx, e := range all {
+		if idx > 0 {
+			assert.LessOrEqual(t, all[idx-1].Date, e.Date)
+		}
+	}
+}
+
+func TestDetectEventsRecent(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	all := DetectEvents(ohlc)
+	recent := DetectEventsRecent(ohlc, 10)
+
+	since := ohlc[len(ohlc)-10].Date
+	var tail []Event
+	for _, e := range all {
+		if e.Date >= since {
+			tail = append(tail, e)
+		}
+	}
+	assert.Equal(t, tail, recent)
+
+	_, err = DetectEventsWith(ohlc, 10, EventParams{Oversold: 80, Overbought: 20, HighLow: 20})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
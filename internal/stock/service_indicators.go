@@ -0,0 +1,94 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"github.com/samber/lo"
+)
+
+// IndicatorsLookback is the number of candles ComputeIndicatorsOne needs, i.e.
+// the longest SMA window plus the new day.
+const IndicatorsLookback = 121
+
+// ComputeIndicators calculates the indicator state of every candle from scratch.
+func ComputeIndicators(ticker string, candles []OHLC) ([]Indicators, error) {
+	sma, err := ComputeSMA(candles)
+	if err != nil {
+		return nil, err
+	}
+	macd := ComputeMACD(candles)
+	rsi := ComputeRSI(candles)
+	kdj := ComputeKDJ(candles)
+
+	indicators := make([]Indicators, len(candles))
+	for idx := range candles {
+		indicators[idx] = Indicators{
+			Ticker:  ticker,
+			Date:    candles[idx].Date,
+			Candles: idx + 1,
+			SMA:     sma[idx],
+			MACD:    macd[idx],
+			RSI:     rsi[idx],
+			KDJ:     kdj[idx],
+		}
+	}
+
+	return indicators, nil
+}
+
+// ComputeIndicatorsOne advances lastIndicators by the last candle, which must be
+// the day right after lastIndicators.Date.
+func ComputeIndicatorsOne(candles []OHLC, lastIndicators Indicators) (Indicators, error) {
+	if len(candles) < IndicatorsLookback || !lastIndicators.CanAdvance() {
+		return Indicators{}, ErrNotEnoughCandles
+	}
+
+	total := len(candles)
+	thisClose := candles[total-1].Close
+	lastClose := candles[total-2].Close
+
+	return Indicators{
+		Ticker:  lastIndicators.Ticker,
+		Date:    candles[total-1].Date,
+		Candles: lastIndicators.Candles + 1,
+		SMA:     ComputeSMAOne(candles, lastIndicators.SMA),
+		MACD:    ComputeMACDOne(thisClose, lastIndicators.MACD),
+		RSI:     ComputeRSIOne(thisClose, lastClose, lastIndicators.RSI),
+		KDJ:     ComputeKDJOne(candles, lastIndicators.K, lastIndicators.D),
+	}, nil
+}
+
+// CanAdvance reports whether the state was computed with enough history for
+// every SMA window to be a real rolling average.
+func (i Indicators) CanAdvance() bool {
+	return i.Candles >= IndicatorsLookback-1
+}
+
+// ValidKDJ reports whether KDJ of the state is past the default warm-up.
+func (i Indicators) ValidKDJ() bool {
+	return i.Candles > DefaultKDJParams().Warmup()
+}
+
+// AdvanceIndicators calculates the indicator states for candles after
+// lastIndicators.Date, incrementally when candles hold enough history before
+// it. It returns false when the caller must rebuild from full history.
+func AdvanceIndicators(candles []OHLC, lastIndicators Indicators) ([]Indicators, bool) {
+	lastIdx := lo.IndexOf(lo.Map(candles, func(candle OHLC, _ int) string {
+		return candle.Date
+	}), lastIndicators.Date)
+	if lastIdx == -1 || lastIdx < IndicatorsLookback-2 || !lastIndicators.CanAdvance() {
+		return nil, false
+	}
+
+	output := make([]Indicators, 0, len(candles)-(lastIdx+1))
+	last := lastIndicators
+	for idx := lastIdx + 1; idx < len(candles); idx++ {
+		next, err := ComputeIndicatorsOne(candles[:idx+1], last)
+		if err != nil {
+			return nil, false
+		}
+		output = append(output, next)
+		last = next
+	}
+
+	return output, true
+}
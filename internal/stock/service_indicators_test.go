@@ -0,0 +1,82 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdvanceIndicators(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	full, err := ComputeIndicators("1.000001", ohlc)
+	if err != nil {
+		t.Fatal("fail to run ComputeIndicators()")
+	}
+
+	start := 180
+	got, ok := AdvanceIndicators(ohlc, full[start])
+	assert.True(t, ok)
+	assert.Len(t, got, len(ohlc)-(start+1))
+
+	for idx, indicators := range got {
+		want := full[start+1+idx]
+		assert.Equal(t, want.Date, indicators.Date)
+		assert.Equal(t, want.Candles, indicators.Candles)
+		assert.InDelta(t, want.Sma120, indicators.Sma120, 1e-9)
+		assert.InDelta(t, want.Sma5, indicators.Sma5, 1e-9)
+		assert.InDelta(t, want.Hist, indicators.Hist, 1e-9)
+		assert.InDelta(t, want.Rsi, indicators.Rsi, 1e-9)
+		assert.InDelta(t, want.J, indicators.J, 1e-9)
+	}
+}
+
+func TestAdvanceIndicatorsGainsOnly(t *testing.T) {
+	closes := make([]float64, 200)
+	for idx := range closes {
+		closes[idx] = 10.0 + 0.1*float64(idx)
+	}
+	candles := closesToCandles(closes)
+
+	full, err := ComputeIndicators("1.000001", candles)
+	assert.NoError(t, err)
+
+	// Without a loss since the first close, a full rebuild and advancing agree on 100.
+	start := 150
+	got, ok := AdvanceIndicators(candles, full[start])
+	assert.True(t, ok)
+	for idx, indicators := range got {
+		assert.InDelta(t, 100.0, full[start+1+idx].Rsi, 1e-9)
+		assert.InDelta(t, full[start+1+idx].Rsi, indicators.Rsi, 1e-9)
+	}
+}
+
+func TestAdvanceIndicatorsNeedsRebuild(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	full, err := ComputeIndicators("1.000001", ohlc[:100])
+	if err != nil {
+		t.Fatal("fail to run ComputeIndicators()")
+	}
+
+	// State computed before SMA120 had enough history.
+	_, ok := AdvanceIndicators(ohlc, full[len(full)-1])
+	assert.False(t, ok)
+
+	// State date missing from the candles.
+	last := full[len(full)-1]
+	last.Date = "1999-01-01"
+	last.Candles = 200
+	_, ok = AdvanceIndicators(ohlc, last)
+	assert.False(t, ok)
+
+	assert.False(t, full[5].ValidKDJ())
+	assert.True(t, full[50].ValidKDJ())
+}
@@ -0,0 +1,361 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/samber/lo"
+)
+
+// LevelSource names the method a price level is derived from.
+type LevelSource string
+
+const (
+	LevelSwing     LevelSource = "swing"
+	LevelClassic   LevelSource = "classic"
+	LevelFibonacci LevelSource = "fibonacci"
+	LevelCamarilla LevelSource = "camarilla"
+	LevelBand      LevelSource = "band"
+	LevelVolume    LevelSource = "volume"
+)
+
+// Structural tells levels traded at over the history, swings, bands and volume nodes,
+// from pivots derived from the last day alone, which lie within its range of close.
+func (s LevelSource) Structural() bool {
+	return s == LevelSwing || s == LevelBand || s == LevelVolume
+}
+
+// LevelKind tells support below close from resistance above it.
+type LevelKind string
+
+const (
+	LevelSupport    LevelKind = "support"
+	LevelResistance LevelKind = "resistance"
+)
+
+// Level is valueobject of a support or resistance price. Strength counts the swing
+// touches of clustered levels, the share of volume of volume nodes and is 1 otherwise.
+type Level struct {
+	Price    float64     `json:"price"`
+	Kind     LevelKind   `json:"kind"`
+	Source   LevelSource `json:"source"`
+	Label    string      `json:"label"`
+	Strength float64     `json:"strength"`
+}
+
+// Pivots is valueobject of pivot point levels derived from a single day.
+// R4 and S4 are only set by Camarilla pivots.
+type Pivots struct {
+	P  float64 `json:"p"`
+	R1 float64 `json:"r1"`
+	R2 float64 `json:"r2"`
+	R3 float64 `json:"r3"`
+	R4 float64 `json:"r4"`
+	S1 float64 `json:"s1"`
+	S2 float64 `json:"s2"`
+	S3 float64 `json:"s3"`
+	S4 float64 `json:"s4"`
+}
+
+// ComputePivotsClassic calculates floor trader pivots for the next session.
+func ComputePivotsClassic(candle OHLC) Pivots {
+	p := (candle.High + candle.Low + candle.Close) / 3.0
+	rng := candle.High - candle.Low
+	return Pivots{
+		P:  p,
+		R1: 2.0*p - candle.Low,
+		R2: p + rng,
+		R3: candle.High + 2.0*(p-candle.Low),
+		S1: 2.0*p - candle.High,
+		S2: p - rng,
+		S3: candle.Low - 2.0*(candle.High-p),
+	}
+}
+
+// ComputePivotsFibonacci calculates pivots spaced by Fibonacci ratios of the day range.
+func ComputePivotsFibonacci(candle OHLC) Pivots {
+	p := (candle.High + candle.Low + candle.Close) / 3.0
+	rng := candle.High - candle.Low
+	return Pivots{
+		P:  p,
+		R1: p + 0.382*rng,
+		R2: p + 0.618*rng,
+		R3: p + rng,
+		S1: p - 0.382*rng,
+		S2: p - 0.618*rng,
+		S3: p - rng,
+	}
+}
+
+// ComputePivotsCamarilla calculates Camarilla pivots around close.
+func ComputePivotsCamarilla(candle OHLC) Pivots {
+	p := (candle.High + candle.Low + candle.Close) / 3.0
+	rng := candle.High - candle.Low
+	return Pivots{
+		P:  p,
+		R1: candle.Close + rng*1.1/12.0,
+		R2: candle.Close + rng*1.1/6.0,
+		R3: candle.Close + rng*1.1/4.0,
+		R4: candle.Close + rng*1.1/2.0,
+		S1: candle.Close - rng*1.1/12.0,
+		S2: candle.Close - rng*1.1/6.0,
+		S3: candle.Close - rng*1.1/4.0,
+		S4: candle.Close - rng*1.1/2.0,
+	}
+}
+
+// Levels is valueobject of support/resistance analysis as of Date. Support and
+// Resistance are the nearest structural levels below and above close.
+type Levels struct {
+	Date       string  `json:"date"`
+	Close      float64 `json:"close"`
+	Classic    Pivots  `json:"classic"`
+	Fibonacci  Pivots  `json:"fibonacci"`
+	Camarilla  Pivots  `json:"camarilla"`
+	Levels     []Level `json:"levels"`
+	Support    *Level  `json:"support"`
+	Resistance *Level  `json:"resistance"`
+}
+
+// DistanceToSupport returns percentage of close above the nearest support, false if none.
+func (l Levels) DistanceToSupport() (float64, bool) {
+	if l.Support == nil || l.Close == 0.0 {
+		return 0.0, false
+	}
+	return 100.0 * (l.Close - l.Support.Price) / l.Close, true
+}
+
+// DistanceToResistance returns percentage of close below the nearest resistance, false if none.
+func (l Levels) DistanceToResistance() (float64, bool) {
+	if l.Resistance == nil || l.Close == 0.0 {
+		return 0.0, false
+	}
+	return 100.0 * (l.Resistance.Price - l.Close) / l.Close, true
+}
+
+// LevelsParams holds the history used and the settings of each level source.
+// Tolerance is the relative price gap within which swings cluster into one level.
+type LevelsParams struct {
+	Lookback  int     `json:"lookback"`
+	Swing     int     `json:"swing"`
+	Tolerance float64 `json:"tolerance"`
+	MinTouch  int     `json:"mintouch"`
+	BandShort int     `json:"bandshort"`
+	BandLong  int     `json:"bandlong"`
+	Bins      int     `json:"bins"`
+	Nodes     int     `json:"nodes"`
+}
+
+// DefaultLevelsParams returns the preset over 120 days of history.
+func DefaultLevelsParams() LevelsParams {
+	return LevelsParams{
+		Lookback:  120,
+		Swing:     3,
+		Tolerance: 0.015,
+		MinTouch:  2,
+		BandShort: 20,
+		BandLong:  60,
+		Bins:      20,
+		Nodes:     3,
+	}
+}
+
+func (p LevelsParams) validate() error {
+	if p.Lookback <= 0 || p.Swing <= 0 || p.MinTouch <= 0 || p.BandShort <= 0 || p.BandLong <= 0 || p.Bins <= 0 || p.Nodes <= 0 { //nolint:lll
+		return ErrInvalidPeriod
+	}
+	if p.Tolerance < 0.0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// ComputeLevels wraps ComputeLevelsWith with the default settings.
+func ComputeLevels(candles []OHLCV) (Levels, error) {
+	return ComputeLevelsWith(candles, DefaultLevelsParams())
+}
+
+// ComputeLevelsWith derives support/resistance levels as of the last candle from
+// clustered swings, pivot points, recent high/low bands and high-volume price nodes.
+func ComputeLevelsWith(candles []OHLCV, params LevelsParams) (Levels, error) {
+	if err := params.validate(); err != nil {
+		return Levels{}, err
+	}
+	if len(candles) == 0 {
+		return Levels{}, ErrNotEnoughCandles
+	}
+
+	window := candles[max(len(candles)-params.Lookback, 0):]
+	ohlc := OHLCV2OHLC(window)
+	last := ohlc[len(ohlc)-1]
+
+	output := Levels{
+		Date:      last.Date,
+		Close:     last.Close,
+		Classic:   ComputePivotsClassic(last),
+		Fibonacci: ComputePivotsFibonacci(last),
+		Camarilla: ComputePivotsCamarilla(last),
+	}
+
+	levels := make([]Level, 0)
+	levels = append(levels, clusterSwingLevels(ohlc, params)...)
+	levels = append(levels, pivotLevels(output.Classic, LevelClassic)...)
+	levels = append(levels, pivotLevels(output.Fibonacci, LevelFibonacci)...)
+	levels = append(levels, pivotLevels(output.Camarilla, LevelCamarilla)...)
+	levels = append(levels, bandLevels(ohlc, params.BandShort)...)
+	levels = append(levels, bandLevels(ohlc, params.BandLong)...)
+	levels = append(levels, volumeLevels(window, params)...)
+
+	for idx := range levels {
+		if levels[idx].Price < last.Close {
+			levels[idx].Kind = LevelSupport
+		} else {
+			levels[idx].Kind = LevelResistance
+		}
+	}
+	slices.SortStableFunc(levels, func(a, b Level) int {
+		switch {
+		case a.Price < b.Price:
+			return -1
+		case a.Price > b.Price:
+			return 1
+		}
+		return 0
+	})
+	output.Levels = levels
+
+	for idx := range levels {
+		level := levels[idx]
+		if !level.Source.Structural() {
+			continue
+		}
+		if level.Kind == LevelSupport {
+			output.Support = &level
+		} else if output.Resistance == nil && level.Price > last.Close {
+			output.Resistance = &level
+		}
+	}
+
+	return output, nil
+}
+
+// clusterSwingLevels groups swing prices lying within tolerance of the lowest price
+// of their group, keeping groups touched at least MinTouch times.
+func clusterSwingLevels(candles []OHLC, params LevelsParams) []Level {
+	swings, _ := ComputeSwingsWith(candles, SwingParams{N: params.Swing})
+	prices := lo.Map(swings, func(s Swing, _ int) float64 {
+		return s.Price
+	})
+	slices.Sort(prices)
+
+	levels := make([]Level, 0)
+	for start := 0; start < len(prices); {
+		end := start + 1
+		for end < len(prices) && prices[end] <= prices[start]*(1.0+params.Tolerance) {
+			end++
+		}
+		if touches := end - start; touches >= params.MinTouch {
+			levels = append(levels, Level{
+				Price:    lo.Sum(prices[start:end]) / float64(touches),
+				Source:   LevelSwing,
+				Label:    "cluster",
+				Strength: float64(touches),
+			})
+		}
+		start = end
+	}
+
+	return levels
+}
+
+func pivotLevels(pivots Pivots, source LevelSource) []Level {
+	labelled := []struct {
+		label string
+		price float64
+	}{
+		{"P", pivots.P},
+		{"R1", pivots.R1}, {"R2", pivots.R2}, {"R3", pivots.R3}, {"R4", pivots.R4},
+		{"S1", pivots.S1}, {"S2", pivots.S2}, {"S3", pivots.S3}, {"S4", pivots.S4},
+	}
+
+	levels := make([]Level, 0, len(labelled))
+	for _, l := range labelled {
+		// P is shared by all pivot methods, keep it once.
+		if l.price == 0.0 || (l.label == "P" && source != LevelClassic) {
+			continue
+		}
+		levels = append(levels, Level{
+			Price:    l.price,
+			Source:   source,
+			Label:    l.label,
+			Strength: 1.0,
+		})
+	}
+
+	return levels
+}
+
+// bandLevels returns the highest high and lowest low of the n candles before the last,
+// so that a band is not the last candle's own range.
+func bandLevels(candles []OHLC, n int) []Level {
+	if len(candles) < 2 {
+		return []Level{}
+	}
+	suffix := strconv.Itoa(n)
+	prior := candles[:len(candles)-1]
+	window := prior[max(len(prior)-n, 0):]
+	return []Level{
+		{Price: lo.Max(OHLC2High(window)), Source: LevelBand, Label: "high" + suffix, Strength: 1.0},
+		{Price: lo.Min(OHLC2Low(window)), Source: LevelBand, Label: "low" + suffix, Strength: 1.0},
+	}
+}
+
+// volumeLevels buckets typical prices of candles into equal-width bins and returns the
+// volume-weighted price of the Nodes bins traded most, with their share of volume.
+func volumeLevels(candles []OHLCV, params LevelsParams) []Level {
+	high := lo.Max(lo.Map(candles, func(c OHLCV, _ int) float64 { return c.High }))
+	low := lo.Min(lo.Map(candles, func(c OHLCV, _ int) float64 { return c.Low }))
+	totalVolume := lo.Sum(OHLCV2Volume(candles))
+	if high == low || totalVolume == 0.0 {
+		return []Level{}
+	}
+
+	type bin struct {
+		volume float64
+		weight float64
+	}
+	bins := make([]bin, params.Bins)
+	width := (high - low) / float64(params.Bins)
+	for _, c := range candles {
+		tp := (c.High + c.Low + c.Close) / 3.0
+		idx := min(int((tp-low)/width), params.Bins-1)
+		bins[idx].volume += c.Volume
+		bins[idx].weight += c.Volume * tp
+	}
+
+	slices.SortStableFunc(bins, func(a, b bin) int {
+		switch {
+		case a.volume > b.volume:
+			return -1
+		case a.volume < b.volume:
+			return 1
+		}
+		return 0
+	})
+
+	levels := make([]Level, 0, params.Nodes)
+	for _, b := range bins[:min(params.Nodes, len(bins))] {
+		if b.volume == 0.0 {
+			break
+		}
+		levels = append(levels, Level{
+			Price:    b.weight / b.volume,
+			Source:   LevelVolume,
+			Label:    "node",
+			Strength: b.volume / totalVolume,
+		})
+	}
+
+	return levels
+}
@@ -0,0 +1,121 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputePivots(t *testing.T) {
+	candle := OHLC{Date: "2024-01-01 00:00:00.000Z", Open: 10, High: 12, Low: 8, Close: 11}
+
+	classic := ComputePivotsClassic(candle)
+	assert.InDelta(t, 31.0/3.0, classic.P, 1e-9)
+	assert.InDelta(t, 2.0*31.0/3.0-8.0, classic.R1, 1e-9)
+	assert.InDelta(t, 31.0/3.0+4.0, classic.R2, 1e-9)
+	assert.InDelta(t, 2.0*31.0/3.0-12.0, classic.S1, 1e-9)
+	assert.InDelta(t, 31.0/3.0-4.0, classic.S2, 1e-9)
+	assert.Zero(t, classic.R4)
+
+	fib := ComputePivotsFibonacci(candle)
+	assert.InDelta(t, 31.0/3.0+0.382*4.0, fib.R1, 1e-9)
+	assert.InDelta(t, 31.0/3.0-4.0, fib.S3, 1e-9)
+
+	cam := ComputePivotsCamarilla(candle)
+	assert.InDelta(t, 11.0+4.0*1.1/12.0, cam.R1, 1e-9)
+	assert.InDelta(t, 11.0-4.0*1.1/2.0, cam.S4, 1e-9)
+}
+
+func TestClusterSwingLevels(t *testing.T) {
+	// Two troughs near 5 and one peak at 9.
+	candles := closesToCandles([]float64{8, 7, 6, 5, 6, 7, 8, 9, 8, 7, 6, 5.05, 6, 7, 8})
+
+	got := clusterSwingLevels(candles, DefaultLevelsParams())
+
+	assert.Len(t, got, 1)
+	assert.InDelta(t, 5.025, got[0].Price, 1e-9)
+	assert.InDelta(t, 2.0, got[0].Strength, 1e-9)
+	assert.Equal(t, LevelSwing, got[0].Source)
+}
+
+func TestBandLevelsNewLow(t *testing.T) {
+	closes := make([]float64, 30)
+	for idx := range closes {
+		closes[idx] = 10.0 + 0.1*float64(idx%3)
+	}
+	closes[len(closes)-1] = 9.0
+	candles := closesToCandles(closes)
+
+	// The bands are those of the candles before the new low, above the close.
+	got := bandLevels(candles, 20)
+	if assert.Len(t, got, 2) {
+		assert.InDelta(t, 10.2, got[0].Price, 1e-9)
+		assert.InDelta(t, 10.0, got[1].Price, 1e-9)
+	}
+	assert.Empty(t, bandLevels(candles[:1], 20))
+
+	ohlcv := lo.Map(candles, func(c OHLC, _ int) OHLCV {
+		return OHLCV{Date: c.Date, Open: c.Open, High: c.High, Low: c.Low, Close: c.Close, Volume: 1e6} //nolint:exhaustruct
+	})
+	levels, err := ComputeLevels(ohlcv)
+	assert.NoError(t, err)
+	if levels.Support != nil {
+		assert.Less(t, levels.Support.Price, 9.0)
+	}
+}
+
+func TestComputeLevels(t *testing.T) {
+	ohlcv, err := loadOHLCV()
+	if err != nil {
+		t.Fatalf("fail to loadOHLCV")
+	}
+
+	got, err := ComputeLevels(ohlcv)
+	if err != nil {
+		t.Fatal("fail to run ComputeLevels()")
+	}
+
+	last := ohlcv[len(ohlcv)-1]
+	assert.Equal(t, last.Date, got.Date)
+	assert.Equal(t, ComputePivotsClassic(OHLCV2OHLC(ohlcv)[len(ohlcv)-1]), got.Classic)
+
+	for idx, level := range got.Levels {
+		if level.Price < last.Close {
+			assert.Equal(t, LevelSupport, level.Kind)
+		} else {
+			assert.Equal(t, LevelResistance, level.Kind)
+		}
+		if idx > 0 {
+			assert.LessOrEqual(t, got.Levels[idx-1].Price, level.Price)
+		}
+	}
+
+	if dist, ok := got.DistanceToSupport(); ok {
+		assert.Positive(t, dist)
+		assert.True(t, got.Support.Source.Structural())
+		for _, level := range got.Levels {
+			if level.Kind == LevelSupport && level.Source.Structural() {
+				assert.LessOrEqual(t, level.Price, got.Support.Price)
+			}
+		}
+	}
+	if dist, ok := got.DistanceToResistance(); ok {
+		assert.Positive(t, dist)
+		assert.True(t, got.Resistance.Source.Structural())
+	}
+
+	// Camarilla S1/R1 hug close, the nearest levels are taken from the history instead.
+	candles := OHLCV2OHLC(ohlcv)
+	camarilla := ComputePivotsCamarilla(candles[len(candles)-1])
+	if got.Support != nil {
+		assert.Less(t, got.Support.Price, camarilla.S1)
+	}
+	if got.Resistance != nil {
+		assert.Greater(t, got.Resistance.Price, camarilla.R1)
+	}
+
+	_, err = ComputeLevels(nil)
+	assert.ErrorIs(t, err, ErrNotEnoughCandles)
+}
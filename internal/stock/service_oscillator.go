@@ -0,0 +1,449 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"math"
+
+	"github.com/samber/lo"
+)
+
+// Oscillators below leave their warm-up days as NaN instead of seeding them;
+// each params type reports the first valid index through Warmup.
+
+// FirstValid returns the index of the first non-NaN value, or len(values) if none.
+func FirstValid(values []float64) int {
+	for idx, value := range values {
+		if !math.IsNaN(value) {
+			return idx
+		}
+	}
+	return len(values)
+}
+
+// CCIParams holds the window of Commodity Channel Index.
+type CCIParams struct {
+	N int `json:"n"`
+}
+
+// DefaultCCIParams returns the CCI(14) preset.
+func DefaultCCIParams() CCIParams {
+	return CCIParams{
+		N: 14,
+	}
+}
+
+// Warmup returns the first valid index of CCI.
+func (p CCIParams) Warmup() int {
+	return p.N - 1
+}
+
+// ComputeCCI wraps ComputeCCIWith with the default window.
+func ComputeCCI(candles []OHLC) []float64 {
+	cci, _ := ComputeCCIWith(candles, DefaultCCIParams())
+	return cci
+}
+
+// ComputeCCIWith calculates CCI as (TP - MA(TP)) / (0.015 * AVEDEV(TP)) for given window.
+func ComputeCCIWith(candles []OHLC, params CCIParams) ([]float64, error) {
+	if params.N <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	tp := lo.Map(candles, func(candle OHLC, _ int) float64 {
+		return (candle.High + candle.Low + candle.Close) / 3.0
+	})
+
+	cci := nanSlice(len(tp))
+	for idx := params.Warmup(); idx < len(tp); idx++ {
+		window := tp[idx+1-params.N : idx+1]
+		mean := lo.Sum(window) / float64(params.N)
+		meanDev := lo.SumBy(window, func(value float64) float64 {
+			return math.Abs(value - mean)
+		}) / float64(params.N)
+		if meanDev == 0.0 {
+			cci[idx] = 0.0
+			continue
+		}
+		cci[idx] = (tp[idx] - mean) / (0.015 * meanDev)
+	}
+
+	return cci, nil
+}
+
+// ComputeCCISeries calculates CCI for given params with its warm-up.
+func ComputeCCISeries(candles []OHLC, params CCIParams) (Series[float64], error) {
+	cci, err := ComputeCCIWith(candles, params)
+	if err != nil {
+		return Series[float64]{}, err
+	}
+
+	return NewSeries(cci, params.Warmup()), nil
+}
+
+// WRParams holds the window of Williams %R.
+type WRParams struct {
+	N int `json:"n"`
+}
+
+// DefaultWRParams returns the WR(10) preset.
+func DefaultWRParams() WRParams {
+	return WRParams{
+		N: 10,
+	}
+}
+
+// Warmup returns the first valid index of WR.
+func (p WRParams) Warmup() int {
+	return p.N - 1
+}
+
+// ComputeWR wraps ComputeWRWith with the default window.
+func ComputeWR(candles []OHLC) []float64 {
+	wr, _ := ComputeWRWith(candles, DefaultWRParams())
+	return wr
+}
+
+// ComputeWRWith calculates Williams %R for given window, using the 0..100 scale
+// of Chinese charting tools where 100 is the most oversold.
+func ComputeWRWith(candles []OHLC, params WRParams) ([]float64, error) {
+	if params.N <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	wr := nanSlice(len(candles))
+	for idx := params.Warmup(); idx < len(candles); idx++ {
+		window := candles[idx+1-params.N : idx+1]
+		h := lo.Max(OHLC2High(window))
+		l := lo.Min(OHLC2Low(window))
+		if h == l {
+			wr[idx] = 50.0
+			continue
+		}
+		wr[idx] = 100.0 * (h - candles[idx].Close) / (h - l)
+	}
+
+	return wr, nil
+}
+
+// ComputeWRSeries calculates Williams %R for given params with its warm-up.
+func ComputeWRSeries(candles []OHLC, params WRParams) (Series[float64], error) {
+	wr, err := ComputeWRWith(candles, params)
+	if err != nil {
+		return Series[float64]{}, err
+	}
+
+	return NewSeries(wr, params.Warmup()), nil
+}
+
+// StochRSI is valueobject holding Stochastic RSI lines for a given day.
+type StochRSI struct {
+	K float64 `db:"k" json:"k"`
+	D float64 `db:"d" json:"d"`
+}
+
+// StochRSIParams holds the RSI period, stochastic window and K/D smoothing windows.
+type StochRSIParams struct {
+	RSI   int `json:"rsi"`
+	Stoch int `json:"stoch"`
+	K     int `json:"k"`
+	D     int `json:"d"`
+}
+
+// DefaultStochRSIParams returns the StochRSI(14,14,3,3) preset.
+func DefaultStochRSIParams() StochRSIParams {
+	return StochRSIParams{
+		RSI:   14,
+		Stoch: 14,
+		K:     3,
+		D:     3,
+	}
+}
+
+func (p StochRSIParams) validate() error {
+	if p.RSI <= 0 || p.Stoch <= 0 || p.K <= 0 || p.D <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of K; RSI itself is valid once it has seen RSI changes.
+func (p StochRSIParams) Warmup() int {
+	return p.RSI + p.Stoch - 1 + p.K - 1
+}
+
+// ComputeStochRSI wraps ComputeStochRSIWith with the default periods.
+func ComputeStochRSI(candles []OHLC) []StochRSI {
+	stochRSI, _ := ComputeStochRSIWith(candles, DefaultStochRSIParams())
+	return stochRSI
+}
+
+// ComputeStochRSIWith calculates the stochastic of RSI smoothed into K and D for given periods.
+func ComputeStochRSIWith(candles []OHLC, params StochRSIParams) ([]StochRSI, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	rsi, _, _ := computeRSIWith(OHLC2Close(candles), float64(params.RSI))
+	for idx := range min(params.RSI, len(rsi)) {
+		rsi[idx] = math.NaN()
+	}
+
+	stoch := nanSlice(len(rsi))
+	for idx := params.RSI + params.Stoch - 1; idx < len(rsi); idx++ {
+		window := rsi[idx+1-params.Stoch : idx+1]
+		h := lo.Max(window)
+		l := lo.Min(window)
+		if h == l {
+			stoch[idx] = 50.0
+			continue
+		}
+		stoch[idx] = 100.0 * (rsi[idx] - l) / (h - l)
+	}
+
+	k := rollingMean(stoch, params.K)
+	d := rollingMean(k, params.D)
+
+	output := make([]StochRSI, len(rsi))
+	for idx := range rsi {
+		output[idx].K = k[idx]
+		output[idx].D = d[idx]
+	}
+
+	return output, nil
+}
+
+// ComputeStochRSISeries calculates Stochastic RSI for given params with its warm-up.
+func ComputeStochRSISeries(candles []OHLC, params StochRSIParams) (Series[StochRSI], error) {
+	stochRSI, err := ComputeStochRSIWith(candles, params)
+	if err != nil {
+		return Series[StochRSI]{}, err
+	}
+
+	return NewSeries(stochRSI, params.Warmup()), nil
+}
+
+// ROC is valueobject holding Rate of Change and its moving average for a given day.
+type ROC struct {
+	Roc float64 `db:"roc" json:"roc"`
+	Ma  float64 `db:"ma" json:"ma"`
+}
+
+// ROCParams holds the lookback N and the moving average window M of ROC.
+type ROCParams struct {
+	N int `json:"n"`
+	M int `json:"m"`
+}
+
+// DefaultROCParams returns the ROC(12,6) preset.
+func DefaultROCParams() ROCParams {
+	return ROCParams{
+		N: 12,
+		M: 6,
+	}
+}
+
+// Warmup returns the first valid index of ROC.
+func (p ROCParams) Warmup() int {
+	return p.N
+}
+
+// ComputeROC wraps ComputeROCWith with the default periods.
+func ComputeROC(candles []OHLC) []ROC {
+	roc, _ := ComputeROCWith(candles, DefaultROCParams())
+	return roc
+}
+
+// ComputeROCWith calculates percentage change over N days and its M-day average.
+func ComputeROCWith(candles []OHLC, params ROCParams) ([]ROC, error) {
+	if params.N <= 0 || params.M <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	closes := OHLC2Close(candles)
+	roc := nanSlice(len(closes))
+	for idx := params.Warmup(); idx < len(closes); idx++ {
+		roc[idx] = 100.0 * (closes[idx] - closes[idx-params.N]) / closes[idx-params.N]
+	}
+	ma := rollingMean(roc, params.M)
+
+	output := make([]ROC, len(closes))
+	for idx := range closes {
+		output[idx].Roc = roc[idx]
+		output[idx].Ma = ma[idx]
+	}
+
+	return output, nil
+}
+
+// ComputeROCSeries calculates ROC for given params with its warm-up.
+func ComputeROCSeries(candles []OHLC, params ROCParams) (Series[ROC], error) {
+	roc, err := ComputeROCWith(candles, params)
+	if err != nil {
+		return Series[ROC]{}, err
+	}
+
+	return NewSeries(roc, params.Warmup()), nil
+}
+
+// TRIX is valueobject holding TRIX and its moving average for a given day.
+type TRIX struct {
+	Trix float64 `db:"trix" json:"trix"`
+	Ma   float64 `db:"ma" json:"ma"`
+}
+
+// TRIXParams holds the triple EMA period N and the moving average window M of TRIX.
+type TRIXParams struct {
+	N int `json:"n"`
+	M int `json:"m"`
+}
+
+// DefaultTRIXParams returns the TRIX(12,9) preset.
+func DefaultTRIXParams() TRIXParams {
+	return TRIXParams{
+		N: 12,
+		M: 9,
+	}
+}
+
+// Warmup returns the first valid index of TRIX, once each of the three EMAs had N days to settle.
+func (p TRIXParams) Warmup() int {
+	return 3*(p.N-1) + 1
+}
+
+// ComputeTRIX wraps ComputeTRIXWith with the default periods.
+func ComputeTRIX(candles []OHLC) []TRIX {
+	trix, _ := ComputeTRIXWith(candles, DefaultTRIXParams())
+	return trix
+}
+
+// ComputeTRIXWith calculates the one-day percentage change of triple smoothed EMA.
+func ComputeTRIXWith(candles []OHLC, params TRIXParams) ([]TRIX, error) {
+	if params.N <= 0 || params.M <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	n := float64(params.N)
+	tr := computeEMA(computeEMA(computeEMA(OHLC2Close(candles), n), n), n)
+
+	trix := nanSlice(len(tr))
+	for idx := max(params.Warmup(), 1); idx < len(tr); idx++ {
+		trix[idx] = 100.0 * (tr[idx] - tr[idx-1]) / tr[idx-1]
+	}
+	ma := rollingMean(trix, params.M)
+
+	output := make([]TRIX, len(tr))
+	for idx := range tr {
+		output[idx].Trix = trix[idx]
+		output[idx].Ma = ma[idx]
+	}
+
+	return output, nil
+}
+
+// ComputeTRIXSeries calculates TRIX for given params with its warm-up.
+func ComputeTRIXSeries(candles []OHLC, params TRIXParams) (Series[TRIX], error) {
+	trix, err := ComputeTRIXWith(candles, params)
+	if err != nil {
+		return Series[TRIX]{}, err
+	}
+
+	return NewSeries(trix, params.Warmup()), nil
+}
+
+// BIAS is valueobject holding the three BIAS (乖离率) lines for a given day.
+type BIAS struct {
+	Bias1 float64 `db:"bias1" json:"bias1"`
+	Bias2 float64 `db:"bias2" json:"bias2"`
+	Bias3 float64 `db:"bias3" json:"bias3"`
+}
+
+// BIASParams holds the moving average windows of the three BIAS lines.
+type BIASParams struct {
+	N1 int `json:"n1"`
+	N2 int `json:"n2"`
+	N3 int `json:"n3"`
+}
+
+// DefaultBIASParams returns the BIAS(6,12,24) preset.
+func DefaultBIASParams() BIASParams {
+	return BIASParams{
+		N1: 6,
+		N2: 12,
+		N3: 24,
+	}
+}
+
+// Warmup returns the first valid index of the shortest BIAS line.
+func (p BIASParams) Warmup() int {
+	return min(p.N1, p.N2, p.N3) - 1
+}
+
+// ComputeBIAS wraps ComputeBIASWith with the default windows.
+func ComputeBIAS(candles []OHLC) []BIAS {
+	bias, _ := ComputeBIASWith(candles, DefaultBIASParams())
+	return bias
+}
+
+// ComputeBIASWith calculates the percentage distance of close from its moving averages.
+func ComputeBIASWith(candles []OHLC, params BIASParams) ([]BIAS, error) {
+	if params.N1 <= 0 || params.N2 <= 0 || params.N3 <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	closes := OHLC2Close(candles)
+	bias1 := computeBIAS(closes, params.N1)
+	bias2 := computeBIAS(closes, params.N2)
+	bias3 := computeBIAS(closes, params.N3)
+
+	output := make([]BIAS, len(closes))
+	for idx := range closes {
+		output[idx].Bias1 = bias1[idx]
+		output[idx].Bias2 = bias2[idx]
+		output[idx].Bias3 = bias3[idx]
+	}
+
+	return output, nil
+}
+
+// ComputeBIASSeries calculates BIAS for given params with its warm-up.
+func ComputeBIASSeries(candles []OHLC, params BIASParams) (Series[BIAS], error) {
+	bias, err := ComputeBIASWith(candles, params)
+	if err != nil {
+		return Series[BIAS]{}, err
+	}
+
+	return NewSeries(bias, params.Warmup()), nil
+}
+
+func computeBIAS(closes []float64, n int) []float64 {
+	ma := rollingMean(closes, n)
+	bias := nanSlice(len(closes))
+	for idx := range closes {
+		if math.IsNaN(ma[idx]) {
+			continue
+		}
+		bias[idx] = 100.0 * (closes[idx] - ma[idx]) / ma[idx]
+	}
+
+	return bias
+}
+
+// rollingMean calculates the mean of the last n values, NaN until a full
+// window of valid values is available.
+func rollingMean(values []float64, n int) []float64 {
+	mean := nanSlice(len(values))
+	for idx := n - 1; idx < len(values); idx++ {
+		mean[idx] = lo.Sum(values[idx+1-n:idx+1]) / float64(n)
+	}
+
+	return mean
+}
+
+func nanSlice(length int) []float64 {
+	values := make([]float64, length)
+	for idx := range values {
+		values[idx] = math.NaN()
+	}
+
+	return values
+}
@@ -0,0 +1,131 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+// loadJSONWithNaN loads golden values where warm-up days are stored as null.
+func loadJSONWithNaN(filename string) (map[string][]float64, error) {
+	jsonFile, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer jsonFile.Close()
+
+	bytes, _ := io.ReadAll(jsonFile)
+
+	var raw map[string][]*float64
+	if err = json.Unmarshal(bytes, &raw); err != nil {
+		return nil, err
+	}
+
+	output := make(map[string][]float64, len(raw))
+	for key, values := range raw {
+		output[key] = lo.Map(values, func(value *float64, _ int) float64 {
+			if value == nil {
+				return math.NaN()
+			}
+			return *value
+		})
+	}
+
+	return output, nil
+}
+
+func TestComputeCCIAndWR(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSONWithNaN("testdata/test_oscillator.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSONWithNaN")
+	}
+
+	cci := ComputeCCI(ohlc)
+	assert.InDeltaSlice(t, gold["cci"], cci, 1e-9)
+	assert.Equal(t, DefaultCCIParams().Warmup(), FirstValid(cci))
+
+	wr := ComputeWR(ohlc)
+	assert.InDeltaSlice(t, gold["wr"], wr, 1e-9)
+	assert.Equal(t, DefaultWRParams().Warmup(), FirstValid(wr))
+}
+
+func TestComputeStochRSI(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSONWithNaN("testdata/test_oscillator.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSONWithNaN")
+	}
+
+	got := ComputeStochRSI(ohlc)
+	gotK := lo.Map(got, func(s StochRSI, _ int) float64 { return s.K })
+	gotD := lo.Map(got, func(s StochRSI, _ int) float64 { return s.D })
+
+	assert.InDeltaSlice(t, gold["stochrsik"], gotK, 1e-9)
+	assert.InDeltaSlice(t, gold["stochrsid"], gotD, 1e-9)
+	assert.Equal(t, DefaultStochRSIParams().Warmup(), FirstValid(gotK))
+}
+
+func TestComputeROCAndTRIX(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSONWithNaN("testdata/test_oscillator.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSONWithNaN")
+	}
+
+	roc := ComputeROC(ohlc)
+	gotROC := lo.Map(roc, func(r ROC, _ int) float64 { return r.Roc })
+	assert.InDeltaSlice(t, gold["roc"], gotROC, 1e-9)
+	assert.InDeltaSlice(t, gold["rocma"], lo.Map(roc, func(r ROC, _ int) float64 { return r.Ma }), 1e-9)
+	assert.Equal(t, DefaultROCParams().Warmup(), FirstValid(gotROC))
+
+	trix := ComputeTRIX(ohlc)
+	gotTRIX := lo.Map(trix, func(r TRIX, _ int) float64 { return r.Trix })
+	assert.InDeltaSlice(t, gold["trix"], gotTRIX, 1e-9)
+	assert.InDeltaSlice(t, gold["trixma"], lo.Map(trix, func(r TRIX, _ int) float64 { return r.Ma }), 1e-9)
+	assert.Equal(t, DefaultTRIXParams().Warmup(), FirstValid(gotTRIX))
+}
+
+func TestComputeBIAS(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSONWithNaN("testdata/test_oscillator.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSONWithNaN")
+	}
+
+	got := ComputeBIAS(ohlc)
+
+	assert.InDeltaSlice(t, gold["bias1"], lo.Map(got, func(b BIAS, _ int) float64 { return b.Bias1 }), 1e-9)
+	assert.InDeltaSlice(t, gold["bias2"], lo.Map(got, func(b BIAS, _ int) float64 { return b.Bias2 }), 1e-9)
+	assert.InDeltaSlice(t, gold["bias3"], lo.Map(got, func(b BIAS, _ int) float64 { return b.Bias3 }), 1e-9)
+}
+
+func TestComputeOscillatorShortSeries(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	short := ohlc[:5]
+	assert.Equal(t, len(short), FirstValid(ComputeCCI(short)))
+	assert.Equal(t, len(short), FirstValid(lo.Map(ComputeTRIX(short), func(r TRIX, _ int) float64 { return r.Trix })))
+	assert.Equal(t, len(short), FirstValid(lo.Map(ComputeStochRSI(short), func(s StochRSI, _ int) float64 { return s.K })))
+}
@@ -0,0 +1,329 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"math"
+)
+
+// PatternKind names a candlestick pattern.
+type PatternKind string
+
+const (
+	PatternDoji               PatternKind = "doji"
+	PatternHammer             PatternKind = "hammer"
+	PatternShootingStar       PatternKind = "shootingstar"
+	PatternEngulfing          PatternKind = "engulfing"
+	PatternHarami             PatternKind = "harami"
+	PatternMorningStar        PatternKind = "morningstar"
+	PatternEveningStar        PatternKind = "eveningstar"
+	PatternThreeWhiteSoldiers PatternKind = "threewhitesoldiers"
+	PatternGap                PatternKind = "gap"
+)
+
+// PatternKinds lists the patterns detected.
+var PatternKinds = []PatternKind{
+	PatternDoji, PatternHammer, PatternShootingStar, PatternEngulfing, PatternHarami,
+	PatternMorningStar, PatternEveningStar, PatternThreeWhiteSoldiers, PatternGap,
+}
+
+// PatternDirection is the price direction a pattern suggests.
+type PatternDirection string
+
+const (
+	DirectionBullish PatternDirection = "bullish"
+	DirectionBearish PatternDirection = "bearish"
+	DirectionNeutral PatternDirection = "neutral"
+)
+
+// patternTrendDays is the lookback used to tell the trend preceding reversal patterns.
+const patternTrendDays = 5
+
+// PatternLookback is the number of candles before a day needed to detect any pattern on it.
+const PatternLookback = patternTrendDays + 1
+
+// Pattern is valueobject of a candlestick pattern ending on Date. Strength is in [0,1].
+type Pattern struct {
+	Date      string           `json:"date"`
+	Kind      PatternKind      `json:"kind"`
+	Direction PatternDirection `json:"direction"`
+	Strength  float64          `json:"strength"`
+	Bars      int              `json:"bars"`
+}
+
+// DetectPatterns scans candles for candlestick patterns, in date order.
+func DetectPatterns(candles []OHLC) []Pattern {
+	return DetectPatternsRecent(candles, len(candles))
+}
+
+// DetectPatternsRecent scans only the last `days` candles for patterns ending on them.
+func DetectPatternsRecent(candles []OHLC, days int) []Pattern {
+	patterns := make([]Pattern, 0)
+	for idx := max(len(candles)-days, 0); idx < len(candles); idx++ {
+		patterns = append(patterns, detectPatternsOne(candles, idx)...)
+	}
+
+	return patterns
+}
+
+// detectPatternsOne detects all patterns ending on candles[idx].
+func detectPatternsOne(candles []OHLC, idx int) []Pattern {
+	detectors := []func([]OHLC, int) (Pattern, bool){
+		detectDoji,
+		detectHammer,
+		detectShootingStar,
+		detectEngulfing,
+		detectHarami,
+		detectStar,
+		detectThreeWhiteSoldiers,
+		detectGap,
+	}
+
+	patterns := make([]Pattern, 0)
+	for _, detect := range detectors {
+		if pattern, ok := detect(candles, idx); ok {
+			pattern.Date = candles[idx].Date
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+type candleShape struct {
+	body  float64
+	rng   float64
+	upper float64
+	lower float64
+	bull  bool
+	bear  bool
+}
+
+func shapeOf(candle OHLC) candleShape {
+	return candleShape{
+		body:  math.Abs(candle.Close - candle.Open),
+		rng:   candle.High - candle.Low,
+		upper: candle.High - max(candle.Open, candle.Close),
+		lower: min(candle.Open, candle.Close) - candle.Low,
+		bull:  candle.Close > candle.Open,
+		bear:  candle.Close < candle.Open,
+	}
+}
+
+// priorTrend returns the close change over patternTrendDays before idx, or false without enough history.
+func priorTrend(candles []OHLC, idx int) (float64, bool) {
+	if idx < PatternLookback {
+		return 0.0, false
+	}
+	return candles[idx-1].Close - candles[idx-1-patternTrendDays].Close, true
+}
+
+func clamp01(value float64) float64 {
+	return max(0.0, min(1.0, value))
+}
+
+func detectDoji(candles []OHLC, idx int) (Pattern, bool) {
+	s := shapeOf(candles[idx])
+	if s.rng == 0.0 || s.body > 0.1*s.rng {
+		return Pattern{}, false
+	}
+
+	return Pattern{
+		Kind:      PatternDoji,
+		Direction: DirectionNeutral,
+		Strength:  clamp01(1.0 - s.body/(0.1*s.rng)),
+		Bars:      1,
+	}, true
+}
+
+// detectHammer finds a long lower shadow after a decline.
+func detectHammer(candles []OHLC, idx int) (Pattern, bool) {
+	s := shapeOf(candles[idx])
+	trend, ok := priorTrend(candles, idx)
+	if !ok || trend >= 0.0 || s.body == 0.0 {
+		return Pattern{}, false
+	}
+	if s.lower < 2.0*s.body || s.upper > 0.1*s.rng {
+		return Pattern{}, false
+	}
+
+	return Pattern{
+		Kind:      PatternHammer,
+		Direction: DirectionBullish,
+		Strength:  clamp01(s.lower / (4.0 * s.body)),
+		Bars:      1,
+	}, true
+}
+
+// detectShootingStar finds a long upper shadow after an advance.
+func detectShootingStar(candles []OHLC, idx int) (Pattern, bool) {
+	s := shapeOf(candles[idx])
+	trend, ok := priorTrend(candles, idx)
+	if !ok || trend <= 0.0 || s.body == 0.0 {
+		return Pattern{}, false
+	}
+	if s.upper < 2.0*s.body || s.lower > 0.1*s.rng {
+		return Pattern{}, false
+	}
+
+	return Pattern{
+		Kind:      PatternShootingStar,
+		Direction: DirectionBearish,
+		Strength:  clamp01(s.upper / (4.0 * s.body)),
+		Bars:      1,
+	}, true
+}
+
+// detectEngulfing finds a body that fully covers the opposite-colored body before it.
+func detectEngulfing(candles []OHLC, idx int) (Pattern, bool) {
+	if idx < 1 {
+		return Pattern{}, false
+	}
+	prev, this := candles[idx-1], candles[idx]
+	sp, s := shapeOf(prev), shapeOf(this)
+	if sp.body == 0.0 || s.body <= sp.body {
+		return Pattern{}, false
+	}
+
+	var direction PatternDirection
+	switch {
+	case sp.bear && s.bull && this.Open <= prev.Close && this.Close >= prev.Open:
+		direction = DirectionBullish
+	case sp.bull && s.bear && this.Open >= prev.Close && this.Close <= prev.Open:
+		direction = DirectionBearish
+	default:
+		return Pattern{}, false
+	}
+
+	return Pattern{
+		Kind:      PatternEngulfing,
+		Direction: direction,
+		Strength:  clamp01(s.body/sp.body - 1.0),
+		Bars:      2,
+	}, true
+}
+
+// detectHarami finds a small body inside the long opposite-colored body before it.
+func detectHarami(candles []OHLC, idx int) (Pattern, bool) {
+	if idx < 1 {
+		return Pattern{}, false
+	}
+	prev, this := candles[idx-1], candles[idx]
+	sp, s := shapeOf(prev), shapeOf(this)
+	if sp.body == 0.0 || sp.body < 0.5*sp.rng || s.body >= 0.5*sp.body {
+		return Pattern{}, false
+	}
+	if max(this.Open, this.Close) > max(prev.Open, prev.Close) || min(this.Open, this.Close) < min(prev.Open, prev.Close) { //nolint:lll
+		return Pattern{}, false
+	}
+
+	var direction PatternDirection
+	switch {
+	case sp.bear && s.bull:
+		direction = DirectionBullish
+	case sp.bull && s.bear:
+		direction = DirectionBearish
+	default:
+		return Pattern{}, false
+	}
+
+	return Pattern{
+		Kind:      PatternHarami,
+		Direction: direction,
+		Strength:  clamp01(1.0 - s.body/(0.5*sp.body)),
+		Bars:      2,
+	}, true
+}
+
+// detectStar finds morning and evening stars: a long body, a small star body
+// beyond it, and a reversal body closing past the first body's midpoint.
+func detectStar(candles []OHLC, idx int) (Pattern, bool) {
+	if idx < 2 {
+		return Pattern{}, false
+	}
+	first, star, last := candles[idx-2], candles[idx-1], candles[idx]
+	s1, s2, s3 := shapeOf(first), shapeOf(star), shapeOf(last)
+	if s1.body == 0.0 || s1.body < 0.5*s1.rng || s2.body > 0.3*s1.body {
+		return Pattern{}, false
+	}
+	mid := (first.Open + first.Close) / 2.0
+
+	switch {
+	case s1.bear && s3.bull && max(star.Open, star.Close) <= first.Close && last.Close > mid:
+		return Pattern{
+			Kind:      PatternMorningStar,
+			Direction: DirectionBullish,
+			Strength:  clamp01((last.Close - mid) / (first.Open - mid)),
+			Bars:      3,
+		}, true
+	case s1.bull && s3.bear && min(star.Open, star.Close) >= first.Close && last.Close < mid:
+		return Pattern{
+			Kind:      PatternEveningStar,
+			Direction: DirectionBearish,
+			Strength:  clamp01((mid - last.Close) / (mid - first.Open)),
+			Bars:      3,
+		}, true
+	}
+
+	return Pattern{}, false
+}
+
+// detectThreeWhiteSoldiers finds three rising bullish bodies, each opening
+// inside the previous body and closing near its high.
+func detectThreeWhiteSoldiers(candles []OHLC, idx int) (Pattern, bool) {
+	if idx < 2 {
+		return Pattern{}, false
+	}
+
+	strength := 1.0
+	for i := idx - 2; i <= idx; i++ {
+		s := shapeOf(candles[i])
+		if !s.bull || s.upper > 0.3*s.body {
+			return Pattern{}, false
+		}
+		if i > idx-2 {
+			prev := candles[i-1]
+			if candles[i].Close <= prev.Close || candles[i].Open < prev.Open || candles[i].Open > prev.Close {
+				return Pattern{}, false
+			}
+		}
+		strength = min(strength, clamp01(s.body/s.rng))
+	}
+
+	return Pattern{
+		Kind:      PatternThreeWhiteSoldiers,
+		Direction: DirectionBullish,
+		Strength:  strength,
+		Bars:      3,
+	}, true
+}
+
+// detectGap finds a day trading entirely above or below the previous day's range.
+// Strength reaches 1 for a gap of 5% of previous close.
+func detectGap(candles []OHLC, idx int) (Pattern, bool) {
+	if idx < 1 {
+		return Pattern{}, false
+	}
+	prev, this := candles[idx-1], candles[idx]
+	if prev.Close == 0.0 {
+		return Pattern{}, false
+	}
+
+	switch {
+	case this.Low > prev.High:
+		return Pattern{
+			Kind:      PatternGap,
+			Direction: DirectionBullish,
+			Strength:  clamp01((this.Low - prev.High) / prev.Close / 0.05),
+			Bars:      2,
+		}, true
+	case this.High < prev.Low:
+		return Pattern{
+			Kind:      PatternGap,
+			Direction: DirectionBearish,
+			Strength:  clamp01((prev.Low - this.High) / prev.Close / 0.05),
+			Bars:      2,
+		}, true
+	}
+
+	return Pattern{}, false
+}
@@ -0,0 +1,117 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"testing"
+
+	"example.com/stocker-back/internal/stock/stocktest"
+	"github.com/stretchr/testify/assert"
+)
+
+// candlesFrom builds dated candles from {open, high, low, close} rows.
+func candlesFrom(rows [][4]float64) []OHLC {
+	candles := make([]OHLC, len(rows))
+	for idx, row := range rows {
+		candles[idx] = OHLC{
+			Date:  stocktest.Date(idx),
+			Open:  row[0],
+			High:  row[1],
+			Low:   row[2],
+			Close: row[3],
+		}
+	}
+	return candles
+}
+
+// declining returns n bearish candles stepping down by 1 from start without gaps.
+func declining(start float64, n int) [][4]float64 {
+	rows := make([][4]float64, n)
+	for idx := range rows {
+		o := start - float64(idx)
+		rows[idx] = [4]float64{o, o + 0.2, o - 1.2, o - 1.0}
+	}
+	return rows
+}
+
+// rising returns n bullish candles stepping up by 1 from start without gaps.
+func rising(start float64, n int) [][4]float64 {
+	rows := make([][4]float64, n)
+	for idx := range rows {
+		o := start + float64(idx)
+		rows[idx] = [4]float64{o, o + 1.5, o - 0.2, o + 1.0}
+	}
+	return rows
+}
+
+func findPattern(patterns []Pattern, kind PatternKind) (Pattern, bool) {
+	for _, p := range patterns {
+		if p.Kind == kind {
+			return p, true
+		}
+	}
+	return Pattern{}, false
+}
+
+func TestDetectPatterns(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      [][4]float64
+		kind      PatternKind
+		direction PatternDirection
+	}{
+		{"doji", append(rising(10, 6), [4]float64{16, 17, 15, 16.05}), PatternDoji, DirectionNeutral},
+		{"hammer", append(declining(20, 6), [4]float64{14, 14.1, 11, 14.5}), PatternHammer, DirectionBullish},
+		{"shootingstar", append(rising(10, 6), [4]float64{16, 19, 15.95, 15.5}), PatternShootingStar, DirectionBearish},
+		{"bullish engulfing", append(declining(20, 6), [4]float64{13.8, 16, 13.5, 15.8}), PatternEngulfing, DirectionBullish},
+		{"bearish engulfing", append(rising(10, 6), [4]float64{16.2, 16.5, 13.9, 14.2}), PatternEngulfing, DirectionBearish},
+		{"bullish harami", append(declining(20, 6), [4]float64{14.3, 14.6, 14.2, 14.5}), PatternHarami, DirectionBullish},
+		{"morningstar", append(declining(20, 5), [4]float64{15, 15.1, 12.9, 13}, [4]float64{12.8, 13, 12.5, 12.7}, [4]float64{13, 14.6, 12.9, 14.5}), PatternMorningStar, DirectionBullish},
+		{"eveningstar", append(rising(10, 5), [4]float64{15, 17.1, 14.9, 17}, [4]float64{17.2, 17.5, 17.1, 17.3}, [4]float64{17, 17.1, 15.4, 15.5}), PatternEveningStar, DirectionBearish},
+		{"threewhitesoldiers", [][4]float64{{10, 11.1, 9.9, 11}, {10.5, 12.1, 10.4, 12}, {11.5, 13.1, 11.4, 13}}, PatternThreeWhiteSoldiers, DirectionBullish},
+		{"gap up", [][4]float64{{10, 10.5, 9.8, 10.4}, {11, 11.5, 10.8, 11.2}}, PatternGap, DirectionBullish},
+		{"gap down", [][4]float64{{10, 10.5, 9.8, 10.4}, {9.5, 9.6, 9.0, 9.2}}, PatternGap, DirectionBearish},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candles := candlesFrom(tt.rows)
+			got, ok := findPattern(DetectPatternsRecent(candles, 1), tt.kind)
+			if !ok {
+				t.Fatalf("expect %s on last candle", tt.kind)
+			}
+			assert.Equal(t, tt.direction, got.Direction)
+			assert.Equal(t, candles[len(candles)-1].Date, got.Date)
+			assert.GreaterOrEqual(t, got.Strength, 0.0)
+			assert.LessOrEqual(t, got.Strength, 1.0)
+		})
+	}
+}
+
+func TestDetectPatternsNeedTrend(t *testing.T) {
+	// Hammer shape without preceding decline is not a hammer.
+	candles := candlesFrom(append(rising(10, 6), [4]float64{16, 16.1, 13, 16.5}))
+	_, ok := findPattern(DetectPatternsRecent(candles, 1), PatternHammer)
+	assert.False(t, ok)
+
+	// Not enough history to tell the trend.
+	candles = candlesFrom([][4]float64{{14, 14.1, 11, 14.5}})
+	_, ok = findPattern(DetectPatterns(candles), PatternHammer)
+	assert.False(t, ok)
+}
+
+func TestDetectPatternsOnRealData(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	all := DetectPatterns(ohlc)
+	recent := DetectPatternsRecent(ohlc, 10)
+
+	// Recent hits are the tail of all hits.
+	assert.Equal(t, all[len(all)-len(recent):], recent)
+	for _, p := range all {
+		assert.GreaterOrEqual(t, p.Strength, 0.0)
+		assert.LessOrEqual(t, p.Strength, 1.0)
+	}
+}
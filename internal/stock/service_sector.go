@@ -0,0 +1,109 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ReturnDays are the lookbacks of returns computed by ComputeReturns.
+var ReturnDays = []int{5, 20, 60}
+
+// ReturnWindow is how many daily closes ComputeReturns needs for the longest lookback.
+var ReturnWindow = lo.Max(ReturnDays) + 1
+
+// ReturnField names the return over n days, e.g. `ret20`.
+func ReturnField(n int) string {
+	return "ret" + strconv.Itoa(n)
+}
+
+// ComputeReturns computes the percentage change of close over each of ReturnDays from
+// closes in ascending date, keyed by ReturnField; lookbacks longer than history are left out.
+func ComputeReturns(closes []float64) map[string]float64 {
+	output := make(map[string]float64, len(ReturnDays))
+	for _, n := range ReturnDays {
+		if len(closes) <= n {
+			continue
+		}
+		last, base := closes[len(closes)-1], closes[len(closes)-1-n]
+		if base == 0.0 {
+			continue
+		}
+		output[ReturnField(n)] = 100.0 * (last - base) / base
+	}
+	return output
+}
+
+// SectorStats is valueobject of aggregates of a sector. Medians of PE and PB leave out
+// non-positive values; Returns averages ComputeReturns over stocks having each lookback.
+type SectorStats struct {
+	Sector    string             `json:"sector"`
+	Count     int                `json:"count"`
+	MedianPE  float64            `json:"medianpe"`
+	MedianPB  float64            `json:"medianpb"`
+	MedianROE float64            `json:"medianroe"`
+	Returns   map[string]float64 `json:"returns"`
+}
+
+// ComputeSectorStats aggregates stocks by sector, with returns keyed by ticker as from
+// ComputeReturns; stocks without sector are left out. Output is sorted by sector.
+func ComputeSectorStats(stocks []Stock, returns map[string]map[string]float64) []SectorStats {
+	grouped := lo.GroupBy(
+		lo.Filter(stocks, func(s Stock, _ int) bool { return s.Sector != "" }),
+		func(s Stock) string { return s.Sector },
+	)
+
+	output := make([]SectorStats, 0, len(grouped))
+	for sector, members := range grouped {
+		stats := SectorStats{
+			Sector: sector,
+			Count:  len(members),
+			MedianPE: median(lo.FilterMap(members, func(s Stock, _ int) (float64, bool) {
+				return s.PricePerEarning, s.PricePerEarning > 0.0
+			})),
+			MedianPB: median(lo.FilterMap(members, func(s Stock, _ int) (float64, bool) {
+				return s.PricePerBook, s.PricePerBook > 0.0
+			})),
+			MedianROE: median(lo.Map(members, func(s Stock, _ int) float64 {
+				return s.ROE
+			})),
+			Returns: make(map[string]float64, len(ReturnDays)),
+		}
+
+		for _, n := range ReturnDays {
+			field := ReturnField(n)
+			values := lo.FilterMap(members, func(s Stock, _ int) (float64, bool) {
+				value, ok := returns[s.Ticker][field]
+				return value, ok
+			})
+			if len(values) > 0 {
+				stats.Returns[field] = lo.Sum(values) / float64(len(values))
+			}
+		}
+
+		output = append(output, stats)
+	}
+
+	slices.SortFunc(output, func(a, b SectorStats) int {
+		return strings.Compare(a.Sector, b.Sector)
+	})
+
+	return output
+}
+
+// median returns the median of values, 0 if empty.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0.0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2.0
+	}
+	return sorted[mid]
+}
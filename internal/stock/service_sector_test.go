@@ -0,0 +1,58 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeReturns(t *testing.T) {
+	closes := make([]float64, 21)
+	for idx := range closes {
+		closes[idx] = 10.0 + float64(idx)
+	}
+
+	returns := ComputeReturns(closes)
+	assert.InDelta(t, 100.0*5.0/25.0, returns["ret5"], 1e-9)
+	assert.InDelta(t, 100.0*20.0/10.0, returns["ret20"], 1e-9)
+	assert.NotContains(t, returns, "ret60")
+}
+
+func TestComputeSectorStats(t *testing.T) {
+	newStock := func(ticker, sector string, pe, pb, roe float64) Stock {
+		s := NewEmptyStock()
+		s.Ticker, s.Sector, s.PricePerEarning, s.PricePerBook, s.ROE = ticker, sector, pe, pb, roe
+		return s
+	}
+	stocks := []Stock{
+		newStock("a", "银行", 5, 0.5, 10),
+		newStock("b", "银行", 7, 0.7, 12),
+		newStock("c", "银行", -3, 0.6, -2),
+		newStock("d", "白酒", 30, 8, 25),
+		newStock("e", "", 10, 1, 5),
+	}
+	returns := map[string]map[string]float64{
+		"a": {"ret5": 1, "ret20": 4, "ret60": 9},
+		"b": {"ret5": 3, "ret20": 6},
+		"d": {"ret5": -2},
+	}
+
+	stats := ComputeSectorStats(stocks, returns)
+	assert.Len(t, stats, 2)
+
+	bank := stats[1]
+	assert.Equal(t, "银行", bank.Sector)
+	assert.Equal(t, 3, bank.Count)
+	// Loss-making PE is left out of the median.
+	assert.InDelta(t, 6.0, bank.MedianPE, 1e-9)
+	assert.InDelta(t, 0.6, bank.MedianPB, 1e-9)
+	assert.InDelta(t, 10.0, bank.MedianROE, 1e-9)
+	assert.InDelta(t, 2.0, bank.Returns["ret5"], 1e-9)
+	assert.InDelta(t, 5.0, bank.Returns["ret20"], 1e-9)
+	assert.InDelta(t, 9.0, bank.Returns["ret60"], 1e-9)
+
+	liquor := stats[0]
+	assert.Equal(t, "白酒", liquor.Sector)
+	assert.NotContains(t, liquor.Returns, "ret20")
+}
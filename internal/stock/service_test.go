@@ -307,6 +307,21 @@ func TestComputeRSIOne(t *testing.T) {
 	assert.Equal(t, gold["rsLoss"], gotRsLoss)
 }
 
+func TestComputeRSIOneBounds(t *testing.T) {
+	// Only gains since the loss decayed to nothing.
+	rsi, rsGain, rsLoss := computeRSIOne(11.0, 10.0, 0.5, 0.0)
+	assert.InDelta(t, 100.0, rsi, 1e-9)
+	assert.Greater(t, rsGain, 0.0)
+	assert.InDelta(t, 0.0, rsLoss, 1e-9)
+
+	// Flat from the start, neither gains nor losses.
+	rsi, _, _ = computeRSIOne(10.0, 10.0, 0.0, 0.0)
+	assert.InDelta(t, 0.0, rsi, 1e-9)
+
+	rsi, _, _ = computeRSIOne(9.0, 10.0, 0.0, 0.0)
+	assert.InDelta(t, 0.0, rsi, 1e-9)
+}
+
 func TestComputeKDJ(t *testing.T) {
 	ohlc, err := loadOHLC()
 	if err != nil {
@@ -360,3 +375,263 @@ func TestComputeKDJOne(t *testing.T) {
 	assert.Equal(t, gold["d"], gotD)
 	assert.Equal(t, gold["j"], gotJ)
 }
+
+func TestComputeWithParams(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_params.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	sma, err := ComputeSMAWith(ohlc, 7)
+	if err != nil {
+		t.Fatal("fail to run ComputeSMAWith()")
+	}
+	assert.InDeltaSlice(t, gold["sma7"], sma, 1e-9)
+
+	ema, err := ComputeEMAWith(ohlc, 10)
+	if err != nil {
+		t.Fatal("fail to run ComputeEMAWith()")
+	}
+	assert.InDeltaSlice(t, gold["ema10"], ema, 1e-9)
+
+	rsi, err := ComputeRSIWith(ohlc, RSIParams{N: 14})
+	if err != nil {
+		t.Fatal("fail to run ComputeRSIWith()")
+	}
+	assert.InDeltaSlice(t, gold["rsi14"], lo.Map(rsi, func(r RSI, _ int) float64 { return r.Rsi }), 1e-9)
+
+	macd, err := ComputeMACDWith(ohlc, MACDParams{Fast: 8, Slow: 21, Signal: 5})
+	if err != nil {
+		t.Fatal("fail to run ComputeMACDWith()")
+	}
+	assert.InDeltaSlice(t, gold["macdDiff"], lo.Map(macd, func(m MACD, _ int) float64 { return m.Diff }), 1e-9)
+	assert.InDeltaSlice(t, gold["macdDea"], lo.Map(macd, func(m MACD, _ int) float64 { return m.Dea }), 1e-9)
+	assert.InDeltaSlice(t, gold["macdHist"], lo.Map(macd, func(m MACD, _ int) float64 { return m.Hist }), 1e-9)
+
+	kdj, err := ComputeKDJWith(ohlc, KDJParams{N: 14, M1: 3, M2: 3})
+	if err != nil {
+		t.Fatal("fail to run ComputeKDJWith()")
+	}
+	assert.InDeltaSlice(t, gold["k"], lo.Map(kdj, func(k KDJ, _ int) float64 { return k.K }), 1e-9)
+	assert.InDeltaSlice(t, gold["d"], lo.Map(kdj, func(k KDJ, _ int) float64 { return k.D }), 1e-9)
+	assert.InDeltaSlice(t, gold["j"], lo.Map(kdj, func(k KDJ, _ int) float64 { return k.J }), 1e-9)
+}
+
+func TestComputeOneWithParams(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	total := len(ohlc)
+	params := MACDParams{Fast: 8, Slow: 21, Signal: 5}
+	macd, _ := ComputeMACDWith(ohlc, params)
+	gotMACD, err := ComputeMACDOneWith(ohlc[total-1].Close, macd[total-2], params)
+	if err != nil {
+		t.Fatal("fail to run ComputeMACDOneWith()")
+	}
+	assert.InDelta(t, macd[total-1].Hist, gotMACD.Hist, 1e-12)
+
+	rsi, _ := ComputeRSIWith(ohlc, RSIParams{N: 14})
+	gotRSI, err := ComputeRSIOneWith(ohlc[total-1].Close, ohlc[total-2].Close, rsi[total-2], RSIParams{N: 14})
+	if err != nil {
+		t.Fatal("fail to run ComputeRSIOneWith()")
+	}
+	assert.InDelta(t, rsi[total-1].Rsi, gotRSI.Rsi, 1e-12)
+
+	kdj, _ := ComputeKDJWith(ohlc, KDJParams{N: 14, M1: 3, M2: 3})
+	gotKDJ, err := ComputeKDJOneWith(ohlc, kdj[total-2].K, kdj[total-2].D, KDJParams{N: 14, M1: 3, M2: 3})
+	if err != nil {
+		t.Fatal("fail to run ComputeKDJOneWith()")
+	}
+	assert.InDelta(t, kdj[total-1].J, gotKDJ.J, 1e-12)
+}
+
+func TestComputeWithInvalidParams(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	_, err = ComputeSMAWith(ohlc, 0)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = ComputeSMAWith(ohlc[:5], 10)
+	assert.ErrorIs(t, err, ErrNotEnoughCandles)
+	_, err = ComputeMACDWith(ohlc, MACDParams{Fast: 0, Slow: 26, Signal: 9})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = ComputeRSIWith(ohlc, RSIParams{N: -1})
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = ComputeKDJOneWith(ohlc[:5], 50.0, 50.0, DefaultKDJParams())
+	assert.ErrorIs(t, err, ErrNotEnoughCandles)
+}
+
+func TestComputeBollinger(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_bollinger.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	closes := OHLC2Close(ohlc)
+	gotMid, gotUpper, gotLower, gotPercentB, gotBandwidth := computeBollinger(closes, 20, 2.0)
+
+	assert.InDeltaSlice(t, gold["mid"], gotMid, 1e-9)
+	assert.InDeltaSlice(t, gold["upper"], gotUpper, 1e-9)
+	assert.InDeltaSlice(t, gold["lower"], gotLower, 1e-9)
+	assert.InDeltaSlice(t, gold["percentb"], gotPercentB, 1e-9)
+	assert.InDeltaSlice(t, gold["bandwidth"], gotBandwidth, 1e-9)
+}
+
+func TestComputeBollingerOne(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_bollinger.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	total := len(ohlc)
+
+	for _, idx := range lo.RangeFrom(0, 10) {
+		got := ComputeBollingerOne(ohlc[:total-idx])
+		assert.InDelta(t, gold["mid"][total-(idx+1)], got.Mid, 1e-9)
+		assert.InDelta(t, gold["upper"][total-(idx+1)], got.Upper, 1e-9)
+		assert.InDelta(t, gold["lower"][total-(idx+1)], got.Lower, 1e-9)
+		assert.InDelta(t, gold["percentb"][total-(idx+1)], got.PercentB, 1e-9)
+	}
+}
+
+func TestComputeATR(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_atr.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	gotTR, gotATR := computeATR(OHLC2Close(ohlc), OHLC2High(ohlc), OHLC2Low(ohlc), 14.0)
+
+	assert.InDeltaSlice(t, gold["tr"], gotTR, 1e-9)
+	assert.InDeltaSlice(t, gold["atr"], gotATR, 1e-9)
+}
+
+func TestComputeATROne(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_atr.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	total := len(ohlc)
+
+	for _, idx := range lo.RangeFrom(1, 10) {
+		lastATR := ATR{TR: gold["tr"][total-(idx+1)], Atr: gold["atr"][total-(idx+1)]}
+		got := ComputeATROne(ohlc[total-idx], ohlc[total-(idx+1)].Close, lastATR)
+		assert.InDelta(t, gold["tr"][total-idx], got.TR, 1e-9)
+		assert.InDelta(t, gold["atr"][total-idx], got.Atr, 1e-9)
+	}
+}
+
+func TestComputeKeltner(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_keltner.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	kc := ComputeKeltner(ohlc)
+
+	assert.InDeltaSlice(t, gold["mid"], lo.Map(kc, func(k Keltner, _ int) float64 { return k.Mid }), 1e-9)
+	assert.InDeltaSlice(t, gold["upper"], lo.Map(kc, func(k Keltner, _ int) float64 { return k.Upper }), 1e-9)
+	assert.InDeltaSlice(t, gold["lower"], lo.Map(kc, func(k Keltner, _ int) float64 { return k.Lower }), 1e-9)
+}
+
+func TestComputeKeltnerOne(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_keltner.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	kc := ComputeKeltner(ohlc)
+	total := len(ohlc)
+
+	for _, idx := range lo.RangeFrom(1, 10) {
+		got := ComputeKeltnerOne(ohlc[total-idx], ohlc[total-(idx+1)].Close, kc[total-(idx+1)])
+		assert.InDelta(t, gold["mid"][total-idx], got.Mid, 1e-9)
+		assert.InDelta(t, gold["upper"][total-idx], got.Upper, 1e-9)
+		assert.InDelta(t, gold["lower"][total-idx], got.Lower, 1e-9)
+	}
+}
+
+func TestComputeSeriesWarmup(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+
+	kdj, err := ComputeKDJSeries(ohlc, DefaultKDJParams())
+	if err != nil {
+		t.Fatal("fail to run ComputeKDJSeries()")
+	}
+	assert.Equal(t, 14, kdj.FirstValid)
+	assert.False(t, kdj.Valid(0))
+	assert.True(t, kdj.Valid(14))
+	last, ok := kdj.Last()
+	assert.True(t, ok)
+	assert.Equal(t, kdj.Values[len(ohlc)-1], last)
+
+	shortKDJ, err := ComputeKDJSeries(ohlc[:10], DefaultKDJParams())
+	if err != nil {
+		t.Fatal("fail to run ComputeKDJSeries()")
+	}
+	_, ok = shortKDJ.Last()
+	assert.False(t, ok)
+	assert.Equal(t, 10, shortKDJ.FirstValid)
+
+	rsi, _ := ComputeRSISeries(ohlc, DefaultRSIParams())
+	assert.Equal(t, 6, rsi.FirstValid)
+	macd, _ := ComputeMACDSeries(ohlc, DefaultMACDParams())
+	assert.Equal(t, 33, macd.FirstValid)
+	sma, _ := ComputeSMASeries(ohlc, 20)
+	assert.Equal(t, 19, sma.FirstValid)
+	boll, _ := ComputeBollingerSeries(ohlc, DefaultBollingerParams())
+	assert.Equal(t, 19, boll.FirstValid)
+	atr, _ := ComputeATRSeries(ohlc, DefaultATRParams())
+	assert.Equal(t, 14, atr.FirstValid)
+	kc, _ := ComputeKeltnerSeries(ohlc, DefaultKeltnerParams())
+	assert.Equal(t, 19, kc.FirstValid)
+	cci, _ := ComputeCCISeries(ohlc, DefaultCCIParams())
+	assert.Equal(t, 13, cci.FirstValid)
+	stochRSI, _ := ComputeStochRSISeries(ohlc, DefaultStochRSIParams())
+	assert.Equal(t, DefaultStochRSIParams().Warmup(), stochRSI.FirstValid)
+	dmi, _ := ComputeDMISeries(ohlc, DefaultDMIParams())
+	assert.Equal(t, 27, dmi.FirstValid)
+	lastDMI, ok := dmi.Last()
+	assert.True(t, ok)
+	assert.Equal(t, dmi.Values[len(ohlc)-1], lastDMI)
+
+	empty := NewSeries([]float64{}, 3)
+	_, ok = empty.Last()
+	assert.False(t, ok)
+}
@@ -0,0 +1,462 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"math"
+
+	"github.com/samber/lo"
+)
+
+// DMI is valueobject holding all values for DMI/ADX for a given day.
+// Tr, PlusDM and MinusDM are the Wilder-smoothed state carried to the next day.
+type DMI struct {
+	PlusDI  float64 `db:"plusdi" json:"plusdi"`
+	MinusDI float64 `db:"minusdi" json:"minusdi"`
+	Adx     float64 `db:"adx" json:"adx"`
+	Tr      float64 `db:"tr" json:"tr"`
+	PlusDM  float64 `db:"plusdm" json:"plusdm"`
+	MinusDM float64 `db:"minusdm" json:"minusdm"`
+}
+
+// DMIParams holds the Wilder smoothing period of DMI and ADX.
+type DMIParams struct {
+	N int `json:"n"`
+}
+
+// DefaultDMIParams returns the DMI(14) preset.
+func DefaultDMIParams() DMIParams {
+	return DMIParams{
+		N: 14,
+	}
+}
+
+func (p DMIParams) validate() error {
+	if p.N <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of ADX, which smooths DX over another N days.
+func (p DMIParams) Warmup() int {
+	return 2*p.N - 1
+}
+
+// ComputeDMI wraps ComputeDMIWith with the default period.
+func ComputeDMI(candles []OHLC) []DMI {
+	dmi, _ := ComputeDMIWith(candles, DefaultDMIParams())
+	return dmi
+}
+
+// ComputeDMIWith calculates +DI, -DI and ADX for given period.
+// The first day only seeds the true range; ADX is seeded by the second day's DX.
+func ComputeDMIWith(candles []OHLC, params DMIParams) ([]DMI, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	dmi := make([]DMI, len(candles))
+	for idx, candle := range candles {
+		if idx == 0 {
+			dmi[idx].Tr = candle.High - candle.Low
+			continue
+		}
+		dmi[idx] = computeDMIOne(candle, candles[idx-1], dmi[idx-1], float64(params.N), idx == 1)
+	}
+
+	return dmi, nil
+}
+
+// ComputeDMISeries calculates DMI for given params with its warm-up.
+func ComputeDMISeries(candles []OHLC, params DMIParams) (Series[DMI], error) {
+	dmi, err := ComputeDMIWith(candles, params)
+	if err != nil {
+		return Series[DMI]{}, err
+	}
+
+	return NewSeries(dmi, params.Warmup()), nil
+}
+
+// ComputeDMIOne calculates a single DMI from previous candle and values.
+func ComputeDMIOne(candle, lastCandle OHLC, lastDMI DMI) DMI {
+	dmi, _ := ComputeDMIOneWith(candle, lastCandle, lastDMI, DefaultDMIParams())
+	return dmi
+}
+
+// ComputeDMIOneWith calculates a single DMI from previous candle and values for given period.
+func ComputeDMIOneWith(candle, lastCandle OHLC, lastDMI DMI, params DMIParams) (DMI, error) {
+	if err := params.validate(); err != nil {
+		return DMI{}, err
+	}
+
+	return computeDMIOne(candle, lastCandle, lastDMI, float64(params.N), false), nil
+}
+
+func computeDMIOne(candle, lastCandle OHLC, lastDMI DMI, n float64, seedADX bool) DMI {
+	upMove := candle.High - lastCandle.High
+	downMove := lastCandle.Low - candle.Low
+	plusDM := 0.0
+	minusDM := 0.0
+	if upMove > downMove && upMove > 0.0 {
+		plusDM = upMove
+	}
+	if downMove > upMove && downMove > 0.0 {
+		minusDM = downMove
+	}
+	tr := computeTrueRange(candle.High, candle.Low, lastCandle.Close)
+
+	smoothTR := ((n-1.0)*lastDMI.Tr + tr) / n
+	smoothPlusDM := ((n-1.0)*lastDMI.PlusDM + plusDM) / n
+	smoothMinusDM := ((n-1.0)*lastDMI.MinusDM + minusDM) / n
+
+	plusDI := 0.0
+	minusDI := 0.0
+	if smoothTR != 0.0 {
+		plusDI = 100.0 * smoothPlusDM / smoothTR
+		minusDI = 100.0 * smoothMinusDM / smoothTR
+	}
+	dx := 0.0
+	if plusDI+minusDI != 0.0 {
+		dx = 100.0 * math.Abs(plusDI-minusDI) / (plusDI + minusDI)
+	}
+
+	adx := ((n-1.0)*lastDMI.Adx + dx) / n
+	if seedADX {
+		adx = dx
+	}
+
+	return DMI{
+		PlusDI:  plusDI,
+		MinusDI: minusDI,
+		Adx:     adx,
+		Tr:      smoothTR,
+		PlusDM:  smoothPlusDM,
+		MinusDM: smoothMinusDM,
+	}
+}
+
+// SAR is valueobject holding Parabolic SAR and its state for a given day.
+type SAR struct {
+	Sar float64 `db:"sar" json:"sar"`
+	Ep  float64 `db:"ep" json:"ep"`
+	Af  float64 `db:"af" json:"af"`
+	Up  bool    `db:"up" json:"up"`
+}
+
+// SARParams holds the acceleration step and maximum of Parabolic SAR.
+type SARParams struct {
+	Step float64 `json:"step"`
+	Max  float64 `json:"max"`
+}
+
+// DefaultSARParams returns the SAR(0.02,0.2) preset.
+func DefaultSARParams() SARParams {
+	return SARParams{
+		Step: 0.02,
+		Max:  0.2,
+	}
+}
+
+func (p SARParams) validate() error {
+	if p.Step <= 0.0 || p.Max < p.Step {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of SAR, after the assumed initial trend.
+func (p SARParams) Warmup() int {
+	return 1
+}
+
+// ComputeSAR wraps ComputeSARWith with the default acceleration.
+func ComputeSAR(candles []OHLC) []SAR {
+	sar, _ := ComputeSARWith(candles, DefaultSARParams())
+	return sar
+}
+
+// ComputeSARWith calculates Parabolic SAR for given acceleration.
+// The first day starts an uptrend with SAR at its low.
+func ComputeSARWith(candles []OHLC, params SARParams) ([]SAR, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	sar := make([]SAR, len(candles))
+	for idx := range candles {
+		if idx == 0 {
+			sar[idx] = SAR{
+				Sar: candles[idx].Low,
+				Ep:  candles[idx].High,
+				Af:  params.Step,
+				Up:  true,
+			}
+			continue
+		}
+		sar[idx] = computeSAROne(candles[max(idx-2, 0):idx+1], sar[idx-1], params.Step, params.Max)
+	}
+
+	return sar, nil
+}
+
+// ComputeSARSeries calculates Parabolic SAR for given params with its warm-up.
+func ComputeSARSeries(candles []OHLC, params SARParams) (Series[SAR], error) {
+	sar, err := ComputeSARWith(candles, params)
+	if err != nil {
+		return Series[SAR]{}, err
+	}
+
+	return NewSeries(sar, params.Warmup()), nil
+}
+
+// ComputeSAROne calculates a single SAR from previous state, candles ending on the current day.
+func ComputeSAROne(candles []OHLC, lastSAR SAR) SAR {
+	sar, _ := ComputeSAROneWith(candles, lastSAR, DefaultSARParams())
+	return sar
+}
+
+// ComputeSAROneWith calculates a single SAR from previous state for given acceleration.
+func ComputeSAROneWith(candles []OHLC, lastSAR SAR, params SARParams) (SAR, error) {
+	if err := params.validate(); err != nil {
+		return SAR{}, err
+	}
+	if len(candles) < 2 {
+		return SAR{}, ErrNotEnoughCandles
+	}
+
+	return computeSAROne(candles[max(len(candles)-3, 0):], lastSAR, params.Step, params.Max), nil
+}
+
+// computeSAROne advances SAR to the last candle of window, which also holds
+// up to two previous candles used to clamp SAR.
+func computeSAROne(window []OHLC, lastSAR SAR, step, maxAF float64) SAR {
+	candle := window[len(window)-1]
+	previous := window[:len(window)-1]
+
+	sar := lastSAR.Sar + lastSAR.Af*(lastSAR.Ep-lastSAR.Sar)
+	ep := lastSAR.Ep
+	af := lastSAR.Af
+
+	if lastSAR.Up {
+		sar = min(sar, lo.Min(OHLC2Low(previous)))
+		if candle.Low < sar {
+			return SAR{Sar: lastSAR.Ep, Ep: candle.Low, Af: step, Up: false}
+		}
+		if candle.High > ep {
+			ep = candle.High
+			af = min(af+step, maxAF)
+		}
+		return SAR{Sar: sar, Ep: ep, Af: af, Up: true}
+	}
+
+	sar = max(sar, lo.Max(OHLC2High(previous)))
+	if candle.High > sar {
+		return SAR{Sar: lastSAR.Ep, Ep: candle.High, Af: step, Up: true}
+	}
+	if candle.Low < ep {
+		ep = candle.Low
+		af = min(af+step, maxAF)
+	}
+	return SAR{Sar: sar, Ep: ep, Af: af, Up: false}
+}
+
+// Aroon is valueobject holding all values for Aroon for a given day.
+type Aroon struct {
+	Up   float64 `db:"up" json:"up"`
+	Down float64 `db:"down" json:"down"`
+	Osc  float64 `db:"osc" json:"osc"`
+}
+
+// AroonParams holds the lookback period of Aroon.
+type AroonParams struct {
+	N int `json:"n"`
+}
+
+// DefaultAroonParams returns the Aroon(25) preset.
+func DefaultAroonParams() AroonParams {
+	return AroonParams{
+		N: 25,
+	}
+}
+
+// Warmup returns the first valid index of Aroon, once the full lookback is seen.
+func (p AroonParams) Warmup() int {
+	return p.N
+}
+
+// ComputeAroon wraps ComputeAroonWith with the default period.
+func ComputeAroon(candles []OHLC) []Aroon {
+	aroon, _ := ComputeAroonWith(candles, DefaultAroonParams())
+	return aroon
+}
+
+// ComputeAroonWith calculates Aroon up/down/oscillator for given period.
+// The first n days use the candles available so far.
+func ComputeAroonWith(candles []OHLC, params AroonParams) ([]Aroon, error) {
+	if params.N <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	aroon := make([]Aroon, len(candles))
+	for idx := range candles {
+		start := max(idx-params.N, 0)
+		aroon[idx] = computeAroonOne(candles[start:idx+1], float64(params.N))
+	}
+
+	return aroon, nil
+}
+
+// ComputeAroonSeries calculates Aroon for given params with its warm-up.
+func ComputeAroonSeries(candles []OHLC, params AroonParams) (Series[Aroon], error) {
+	aroon, err := ComputeAroonWith(candles, params)
+	if err != nil {
+		return Series[Aroon]{}, err
+	}
+
+	return NewSeries(aroon, params.Warmup()), nil
+}
+
+// ComputeAroonOne calculates Aroon for the last candle given period.
+func ComputeAroonOne(candles []OHLC, params AroonParams) (Aroon, error) {
+	if params.N <= 0 {
+		return Aroon{}, ErrInvalidPeriod
+	}
+	if len(candles) <= params.N {
+		return Aroon{}, ErrNotEnoughCandles
+	}
+
+	return computeAroonOne(candles[len(candles)-(params.N+1):], float64(params.N)), nil
+}
+
+// computeAroonOne measures days since the window high and low, the most recent one on ties.
+func computeAroonOne(window []OHLC, n float64) Aroon {
+	last := len(window) - 1
+	idxHigh := 0
+	idxLow := 0
+	for idx, candle := range window {
+		if candle.High >= window[idxHigh].High {
+			idxHigh = idx
+		}
+		if candle.Low <= window[idxLow].Low {
+			idxLow = idx
+		}
+	}
+
+	up := 100.0 * (n - float64(last-idxHigh)) / n
+	down := 100.0 * (n - float64(last-idxLow)) / n
+
+	return Aroon{
+		Up:   up,
+		Down: down,
+		Osc:  up - down,
+	}
+}
+
+// Ichimoku is valueobject holding all values for Ichimoku Kinko Hyo for a given day.
+// SenkouA and SenkouB are the cloud spans projected onto this day, i.e. computed
+// Displacement days earlier, and Chikou is this day's close that charts plot
+// Displacement days back.
+type Ichimoku struct {
+	Tenkan  float64 `db:"tenkan" json:"tenkan"`
+	Kijun   float64 `db:"kijun" json:"kijun"`
+	SenkouA float64 `db:"senkoua" json:"senkoua"`
+	SenkouB float64 `db:"senkoub" json:"senkoub"`
+	Chikou  float64 `db:"chikou" json:"chikou"`
+}
+
+// IchimokuParams holds the conversion, base and span B periods and the displacement.
+type IchimokuParams struct {
+	Tenkan       int `json:"tenkan"`
+	Kijun        int `json:"kijun"`
+	SenkouB      int `json:"senkoub"`
+	Displacement int `json:"displacement"`
+}
+
+// DefaultIchimokuParams returns the Ichimoku(9,26,52) preset.
+func DefaultIchimokuParams() IchimokuParams {
+	return IchimokuParams{
+		Tenkan:       9,
+		Kijun:        26,
+		SenkouB:      52,
+		Displacement: 26,
+	}
+}
+
+func (p IchimokuParams) validate() error {
+	if p.Tenkan <= 0 || p.Kijun <= 0 || p.SenkouB <= 0 || p.Displacement < 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of the projected cloud.
+func (p IchimokuParams) Warmup() int {
+	return max(p.Tenkan, p.Kijun, p.SenkouB) - 1 + p.Displacement
+}
+
+// ComputeIchimoku wraps ComputeIchimokuWith with the default periods.
+func ComputeIchimoku(candles []OHLC) []Ichimoku {
+	ichimoku, _ := ComputeIchimokuWith(candles, DefaultIchimokuParams())
+	return ichimoku
+}
+
+// ComputeIchimokuWith calculates Ichimoku lines for given periods.
+// Windows and displacement shrink to the candles available so far.
+func ComputeIchimokuWith(candles []OHLC, params IchimokuParams) ([]Ichimoku, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	ichimoku := make([]Ichimoku, len(candles))
+	for idx := range candles {
+		ichimoku[idx] = computeIchimokuOne(candles[:idx+1], params)
+	}
+
+	return ichimoku, nil
+}
+
+// ComputeIchimokuSeries calculates Ichimoku for given params with its warm-up.
+func ComputeIchimokuSeries(candles []OHLC, params IchimokuParams) (Series[Ichimoku], error) {
+	ichimoku, err := ComputeIchimokuWith(candles, params)
+	if err != nil {
+		return Series[Ichimoku]{}, err
+	}
+
+	return NewSeries(ichimoku, params.Warmup()), nil
+}
+
+// ComputeIchimokuOne calculates Ichimoku for the last candle given periods.
+func ComputeIchimokuOne(candles []OHLC, params IchimokuParams) (Ichimoku, error) {
+	if err := params.validate(); err != nil {
+		return Ichimoku{}, err
+	}
+	if len(candles) < max(params.Kijun, params.SenkouB)+params.Displacement {
+		return Ichimoku{}, ErrNotEnoughCandles
+	}
+
+	return computeIchimokuOne(candles, params), nil
+}
+
+func computeIchimokuOne(candles []OHLC, params IchimokuParams) Ichimoku {
+	last := len(candles) - 1
+	projected := max(last-params.Displacement, 0)
+
+	tenkan := midRange(candles[:last+1], params.Tenkan)
+	kijun := midRange(candles[:last+1], params.Kijun)
+	senkouA := (midRange(candles[:projected+1], params.Tenkan) + midRange(candles[:projected+1], params.Kijun)) / 2.0
+	senkouB := midRange(candles[:projected+1], params.SenkouB)
+
+	return Ichimoku{
+		Tenkan:  tenkan,
+		Kijun:   kijun,
+		SenkouA: senkouA,
+		SenkouB: senkouB,
+		Chikou:  candles[last].Close,
+	}
+}
+
+// midRange calculates the midpoint of highest high and lowest low of the last n candles.
+func midRange(candles []OHLC, n int) float64 {
+	window := candles[max(len(candles)-n, 0):]
+	return (lo.Max(OHLC2High(window)) + lo.Min(OHLC2Low(window))) / 2.0
+}
@@ -0,0 +1,120 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeDMI(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_trend.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	got := ComputeDMI(ohlc)
+
+	assert.InDeltaSlice(t, gold["plusdi"], lo.Map(got, func(d DMI, _ int) float64 { return d.PlusDI }), 1e-9)
+	assert.InDeltaSlice(t, gold["minusdi"], lo.Map(got, func(d DMI, _ int) float64 { return d.MinusDI }), 1e-9)
+	assert.InDeltaSlice(t, gold["adx"], lo.Map(got, func(d DMI, _ int) float64 { return d.Adx }), 1e-9)
+}
+
+func TestComputeDMIOne(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_trend.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	dmi := ComputeDMI(ohlc)
+	total := len(ohlc)
+
+	for _, idx := range lo.RangeFrom(1, 10) {
+		got := ComputeDMIOne(ohlc[total-idx], ohlc[total-(idx+1)], dmi[total-(idx+1)])
+		assert.InDelta(t, gold["adx"][total-idx], got.Adx, 1e-9)
+		assert.InDelta(t, gold["plusdi"][total-idx], got.PlusDI, 1e-9)
+	}
+}
+
+func TestComputeSAR(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_trend.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	got := ComputeSAR(ohlc)
+
+	assert.InDeltaSlice(t, gold["sar"], lo.Map(got, func(s SAR, _ int) float64 { return s.Sar }), 1e-9)
+	assert.Equal(t, gold["sarup"], lo.Map(got, func(s SAR, _ int) float64 {
+		if s.Up {
+			return 1.0
+		}
+		return 0.0
+	}))
+
+	total := len(ohlc)
+	for _, idx := range lo.RangeFrom(1, 10) {
+		gotOne := ComputeSAROne(ohlc[:total-(idx-1)], got[total-(idx+1)])
+		assert.InDelta(t, gold["sar"][total-idx], gotOne.Sar, 1e-9)
+	}
+}
+
+func TestComputeAroon(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_trend.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	got := ComputeAroon(ohlc)
+
+	assert.InDeltaSlice(t, gold["aroonup"], lo.Map(got, func(a Aroon, _ int) float64 { return a.Up }), 1e-9)
+	assert.InDeltaSlice(t, gold["aroondown"], lo.Map(got, func(a Aroon, _ int) float64 { return a.Down }), 1e-9)
+
+	gotOne, err := ComputeAroonOne(ohlc, DefaultAroonParams())
+	if err != nil {
+		t.Fatal("fail to run ComputeAroonOne()")
+	}
+	assert.Equal(t, got[len(got)-1], gotOne)
+}
+
+func TestComputeIchimoku(t *testing.T) {
+	ohlc, err := loadOHLC()
+	if err != nil {
+		t.Fatalf("fail to loadOHLC")
+	}
+	gold, err := loadJSON("testdata/test_trend.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	got := ComputeIchimoku(ohlc)
+
+	assert.InDeltaSlice(t, gold["tenkan"], lo.Map(got, func(i Ichimoku, _ int) float64 { return i.Tenkan }), 1e-9)
+	assert.InDeltaSlice(t, gold["kijun"], lo.Map(got, func(i Ichimoku, _ int) float64 { return i.Kijun }), 1e-9)
+	assert.InDeltaSlice(t, gold["senkoua"], lo.Map(got, func(i Ichimoku, _ int) float64 { return i.SenkouA }), 1e-9)
+	assert.InDeltaSlice(t, gold["senkoub"], lo.Map(got, func(i Ichimoku, _ int) float64 { return i.SenkouB }), 1e-9)
+	assert.Equal(t, OHLC2Close(ohlc), lo.Map(got, func(i Ichimoku, _ int) float64 { return i.Chikou }))
+
+	gotOne, err := ComputeIchimokuOne(ohlc, DefaultIchimokuParams())
+	if err != nil {
+		t.Fatal("fail to run ComputeIchimokuOne()")
+	}
+	assert.Equal(t, got[len(got)-1], gotOne)
+}
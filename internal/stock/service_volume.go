@@ -0,0 +1,404 @@
+//nolint:gomnd //ignore
+package stock
+
+import (
+	"github.com/samber/lo"
+)
+
+// ComputeOBV calculates On-Balance Volume, starting from zero on the first day.
+func ComputeOBV(candles []OHLCV) []float64 {
+	obv := make([]float64, len(candles))
+	for idx := range candles {
+		if idx == 0 {
+			continue
+		}
+		obv[idx] = computeOBVOne(candles[idx].Close, candles[idx-1].Close, candles[idx].Volume, obv[idx-1])
+	}
+
+	return obv
+}
+
+// ComputeOBVOne calculates a single OBV from previous value.
+func ComputeOBVOne(candle OHLCV, lastClose, lastOBV float64) float64 {
+	return computeOBVOne(candle.Close, lastClose, candle.Volume, lastOBV)
+}
+
+// computeOBVOne adds volume on up days and subtracts it on down days.
+func computeOBVOne(thisClose, lastClose, volume, lastOBV float64) float64 {
+	switch {
+	case thisClose > lastClose:
+		return lastOBV + volume
+	case thisClose < lastClose:
+		return lastOBV - volume
+	default:
+		return lastOBV
+	}
+}
+
+// ComputeAD calculates the Accumulation/Distribution line.
+func ComputeAD(candles []OHLCV) []float64 {
+	ad := make([]float64, len(candles))
+	lastAD := 0.0
+	for idx, candle := range candles {
+		ad[idx] = lastAD + computeMoneyFlowVolume(candle)
+		lastAD = ad[idx]
+	}
+
+	return ad
+}
+
+// ComputeADOne calculates a single A/D value from previous value.
+func ComputeADOne(candle OHLCV, lastAD float64) float64 {
+	return lastAD + computeMoneyFlowVolume(candle)
+}
+
+// computeMoneyFlowVolume weighs volume by where the close sits in the day's range.
+func computeMoneyFlowVolume(candle OHLCV) float64 {
+	if candle.High == candle.Low {
+		return 0.0
+	}
+	clv := ((candle.Close - candle.Low) - (candle.High - candle.Close)) / (candle.High - candle.Low)
+
+	return clv * candle.Volume
+}
+
+// CMFParams holds the window of Chaikin Money Flow.
+type CMFParams struct {
+	N int `json:"n"`
+}
+
+// DefaultCMFParams returns the CMF(20) preset.
+func DefaultCMFParams() CMFParams {
+	return CMFParams{
+		N: 20,
+	}
+}
+
+// Warmup returns the first valid index of CMF.
+func (p CMFParams) Warmup() int {
+	return p.N - 1
+}
+
+// ComputeCMF calculates Chaikin Money Flow with the default window.
+func ComputeCMF(candles []OHLCV) []float64 {
+	cmf, _ := ComputeCMFWith(candles, DefaultCMFParams())
+	return cmf
+}
+
+// ComputeCMFWith calculates Chaikin Money Flow for given window.
+// The first n-1 days use the candles available so far.
+func ComputeCMFWith(candles []OHLCV, params CMFParams) ([]float64, error) {
+	if params.N <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	cmf := make([]float64, len(candles))
+	for idx := range candles {
+		start := max(idx+1-params.N, 0)
+		cmf[idx] = computeCMFOne(candles[start : idx+1])
+	}
+
+	return cmf, nil
+}
+
+// ComputeCMFSeries calculates CMF for given params with its warm-up.
+func ComputeCMFSeries(candles []OHLCV, params CMFParams) (Series[float64], error) {
+	cmf, err := ComputeCMFWith(candles, params)
+	if err != nil {
+		return Series[float64]{}, err
+	}
+
+	return NewSeries(cmf, params.Warmup()), nil
+}
+
+// ComputeCMFOne calculates Chaikin Money Flow for the last candle given window.
+func ComputeCMFOne(candles []OHLCV, params CMFParams) (float64, error) {
+	if params.N <= 0 {
+		return 0.0, ErrInvalidPeriod
+	}
+	if len(candles) < params.N {
+		return 0.0, ErrNotEnoughCandles
+	}
+
+	return computeCMFOne(candles[len(candles)-params.N:]), nil
+}
+
+func computeCMFOne(window []OHLCV) float64 {
+	volume := lo.SumBy(window, func(candle OHLCV) float64 { return candle.Volume })
+	if volume == 0.0 {
+		return 0.0
+	}
+	flow := lo.SumBy(window, computeMoneyFlowVolume)
+
+	return flow / volume
+}
+
+// MFIParams holds the window of Money Flow Index.
+type MFIParams struct {
+	N int `json:"n"`
+}
+
+// DefaultMFIParams returns the MFI(14) preset.
+func DefaultMFIParams() MFIParams {
+	return MFIParams{
+		N: 14,
+	}
+}
+
+// Warmup returns the first valid index of MFI, once N money flows are seen.
+func (p MFIParams) Warmup() int {
+	return p.N
+}
+
+// ComputeMFI calculates Money Flow Index with the default window.
+func ComputeMFI(candles []OHLCV) []float64 {
+	mfi, _ := ComputeMFIWith(candles, DefaultMFIParams())
+	return mfi
+}
+
+// ComputeMFIWith calculates Money Flow Index for given window.
+// The first n days use the money flows available so far.
+func ComputeMFIWith(candles []OHLCV, params MFIParams) ([]float64, error) {
+	if params.N <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	mfi := make([]float64, len(candles))
+	for idx := range candles {
+		start := max(idx-params.N, 0)
+		mfi[idx] = computeMFIOne(candles[start : idx+1])
+	}
+
+	return mfi, nil
+}
+
+// ComputeMFISeries calculates MFI for given params with its warm-up.
+func ComputeMFISeries(candles []OHLCV, params MFIParams) (Series[float64], error) {
+	mfi, err := ComputeMFIWith(candles, params)
+	if err != nil {
+		return Series[float64]{}, err
+	}
+
+	return NewSeries(mfi, params.Warmup()), nil
+}
+
+// ComputeMFIOne calculates Money Flow Index for the last candle given window.
+func ComputeMFIOne(candles []OHLCV, params MFIParams) (float64, error) {
+	if params.N <= 0 {
+		return 0.0, ErrInvalidPeriod
+	}
+	if len(candles) <= params.N {
+		return 0.0, ErrNotEnoughCandles
+	}
+
+	return computeMFIOne(candles[len(candles)-(params.N+1):]), nil
+}
+
+// computeMFIOne calculates MFI over the flows between consecutive candles of the window.
+func computeMFIOne(window []OHLCV) float64 {
+	positive := 0.0
+	negative := 0.0
+	for idx := 1; idx < len(window); idx++ {
+		thisTP := typicalPrice(window[idx])
+		lastTP := typicalPrice(window[idx-1])
+		flow := thisTP * window[idx].Volume
+		switch {
+		case thisTP > lastTP:
+			positive += flow
+		case thisTP < lastTP:
+			negative += flow
+		}
+	}
+
+	switch {
+	case positive == 0.0 && negative == 0.0:
+		return 50.0
+	case negative == 0.0:
+		return 100.0
+	}
+
+	return 100.0 - 100.0/(1.0+positive/negative)
+}
+
+func typicalPrice(candle OHLCV) float64 {
+	return (candle.High + candle.Low + candle.Close) / 3.0
+}
+
+// VWAPParams holds the rolling window of daily VWAP.
+type VWAPParams struct {
+	N int `json:"n"`
+}
+
+// DefaultVWAPParams returns the 20-day rolling VWAP preset.
+func DefaultVWAPParams() VWAPParams {
+	return VWAPParams{
+		N: 20,
+	}
+}
+
+// Warmup returns the first valid index of rolling VWAP.
+func (p VWAPParams) Warmup() int {
+	return p.N - 1
+}
+
+// ComputeVWAP calculates rolling VWAP with the default window.
+func ComputeVWAP(candles []OHLCV) []float64 {
+	vwap, _ := ComputeVWAPWith(candles, DefaultVWAPParams())
+	return vwap
+}
+
+// ComputeVWAPWith calculates rolling VWAP over n days from traded value and volume.
+// The first n-1 days use the candles available so far.
+func ComputeVWAPWith(candles []OHLCV, params VWAPParams) ([]float64, error) {
+	if params.N <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+
+	vwap := make([]float64, len(candles))
+	for idx := range candles {
+		start := max(idx+1-params.N, 0)
+		vwap[idx] = computeVWAPOne(candles[start : idx+1])
+	}
+
+	return vwap, nil
+}
+
+// ComputeVWAPSeries calculates rolling VWAP for given params with its warm-up.
+func ComputeVWAPSeries(candles []OHLCV, params VWAPParams) (Series[float64], error) {
+	vwap, err := ComputeVWAPWith(candles, params)
+	if err != nil {
+		return Series[float64]{}, err
+	}
+
+	return NewSeries(vwap, params.Warmup()), nil
+}
+
+// ComputeVWAPOne calculates rolling VWAP for the last candle given window.
+func ComputeVWAPOne(candles []OHLCV, params VWAPParams) (float64, error) {
+	if params.N <= 0 {
+		return 0.0, ErrInvalidPeriod
+	}
+	if len(candles) < params.N {
+		return 0.0, ErrNotEnoughCandles
+	}
+
+	return computeVWAPOne(candles[len(candles)-params.N:]), nil
+}
+
+// computeVWAPOne divides traded value by traded shares, falling back to
+// typical price weighting when the value is not available.
+func computeVWAPOne(window []OHLCV) float64 {
+	volume := lo.SumBy(window, func(candle OHLCV) float64 { return candle.Volume })
+	if volume == 0.0 {
+		return window[len(window)-1].Close
+	}
+
+	value := lo.SumBy(window, func(candle OHLCV) float64 { return candle.Value })
+	if value != 0.0 {
+		return value / (volume * SharesPerLot)
+	}
+
+	weighted := lo.SumBy(window, func(candle OHLCV) float64 { return typicalPrice(candle) * candle.Volume })
+
+	return weighted / volume
+}
+
+// VolumeMA is valueobject holding volume moving average and volume ratio (量比) for a given day.
+type VolumeMA struct {
+	Ma    float64 `db:"ma" json:"ma"`
+	Ratio float64 `db:"ratio" json:"ratio"`
+}
+
+// VolumeMAParams holds the moving average window N and the volume ratio lookback RatioDays.
+type VolumeMAParams struct {
+	N         int `json:"n"`
+	RatioDays int `json:"ratiodays"`
+}
+
+// DefaultVolumeMAParams returns the MAVOL(5) with 5-day volume ratio preset.
+func DefaultVolumeMAParams() VolumeMAParams {
+	return VolumeMAParams{
+		N:         5,
+		RatioDays: 5,
+	}
+}
+
+func (p VolumeMAParams) validate() error {
+	if p.N <= 0 || p.RatioDays <= 0 {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Warmup returns the first valid index of both volume moving average and ratio.
+func (p VolumeMAParams) Warmup() int {
+	return max(p.N-1, p.RatioDays)
+}
+
+// ComputeVolumeMA calculates volume moving average and ratio with the default windows.
+func ComputeVolumeMA(candles []OHLCV) []VolumeMA {
+	vma, _ := ComputeVolumeMAWith(candles, DefaultVolumeMAParams())
+	return vma
+}
+
+// ComputeVolumeMAWith calculates volume moving average and volume ratio for given windows.
+// Volume ratio compares the day's volume to the average of the previous RatioDays days.
+func ComputeVolumeMAWith(candles []OHLCV, params VolumeMAParams) ([]VolumeMA, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	volumes := OHLCV2Volume(candles)
+	vma := make([]VolumeMA, len(volumes))
+	for idx := range volumes {
+		vma[idx].Ma, vma[idx].Ratio = computeVolumeMAOne(volumes[:idx+1], params.N, params.RatioDays)
+	}
+
+	return vma, nil
+}
+
+// ComputeVolumeMASeries calculates volume moving averages for given params with its warm-up.
+func ComputeVolumeMASeries(candles []OHLCV, params VolumeMAParams) (Series[VolumeMA], error) {
+	volumeMA, err := ComputeVolumeMAWith(candles, params)
+	if err != nil {
+		return Series[VolumeMA]{}, err
+	}
+
+	return NewSeries(volumeMA, params.Warmup()), nil
+}
+
+// ComputeVolumeMAOne calculates volume moving average and ratio for the last candle.
+func ComputeVolumeMAOne(candles []OHLCV, params VolumeMAParams) (VolumeMA, error) {
+	if err := params.validate(); err != nil {
+		return VolumeMA{}, err
+	}
+	if len(candles) <= max(params.N, params.RatioDays) {
+		return VolumeMA{}, ErrNotEnoughCandles
+	}
+
+	ma, ratio := computeVolumeMAOne(OHLCV2Volume(candles), params.N, params.RatioDays)
+
+	return VolumeMA{
+		Ma:    ma,
+		Ratio: ratio,
+	}, nil
+}
+
+// computeVolumeMAOne calculates the values for the last volume using partial
+// windows when the history is shorter.
+func computeVolumeMAOne(volumes []float64, n, ratioDays int) (float64, float64) {
+	last := len(volumes) - 1
+	window := volumes[max(last+1-n, 0):]
+	ma := lo.Sum(window) / float64(len(window))
+
+	past := volumes[max(last-ratioDays, 0):last]
+	if len(past) == 0 {
+		return ma, 0.0
+	}
+	pastAvg := lo.Sum(past) / float64(len(past))
+	if pastAvg == 0.0 {
+		return ma, 0.0
+	}
+
+	return ma, volumes[last] / pastAvg
+}
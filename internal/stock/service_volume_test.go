@@ -0,0 +1,162 @@
+//nolint:testpackage,lll //ignore
+package stock
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadOHLCV() ([]OHLCV, error) {
+	jsonFile, err := os.Open("testdata/test_ohlcv.json")
+	if err != nil {
+		return nil, err
+	}
+	defer jsonFile.Close()
+
+	bytes, _ := io.ReadAll(jsonFile)
+
+	var ohlcv []OHLCV
+	if err = json.Unmarshal(bytes, &ohlcv); err != nil {
+		return nil, err
+	}
+
+	return ohlcv, nil
+}
+
+func TestComputeOBV(t *testing.T) {
+	ohlcv, err := loadOHLCV()
+	if err != nil {
+		t.Fatalf("fail to loadOHLCV")
+	}
+	gold, err := loadJSON("testdata/test_volume.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	got := ComputeOBV(ohlcv)
+	assert.InDeltaSlice(t, gold["obv"], got, 1e-6)
+
+	total := len(ohlcv)
+	for _, idx := range lo.RangeFrom(1, 10) {
+		gotOne := ComputeOBVOne(ohlcv[total-idx], ohlcv[total-(idx+1)].Close, got[total-(idx+1)])
+		assert.InDelta(t, gold["obv"][total-idx], gotOne, 1e-6)
+	}
+}
+
+func TestComputeAD(t *testing.T) {
+	ohlcv, err := loadOHLCV()
+	if err != nil {
+		t.Fatalf("fail to loadOHLCV")
+	}
+	gold, err := loadJSON("testdata/test_volume.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	got := ComputeAD(ohlcv)
+	assert.InDeltaSlice(t, gold["ad"], got, 1e-6)
+
+	total := len(ohlcv)
+	gotOne := ComputeADOne(ohlcv[total-1], got[total-2])
+	assert.InDelta(t, gold["ad"][total-1], gotOne, 1e-6)
+}
+
+func TestComputeCMFAndMFI(t *testing.T) {
+	ohlcv, err := loadOHLCV()
+	if err != nil {
+		t.Fatalf("fail to loadOHLCV")
+	}
+	gold, err := loadJSON("testdata/test_volume.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	assert.InDeltaSlice(t, gold["cmf"], ComputeCMF(ohlcv), 1e-9)
+	assert.InDeltaSlice(t, gold["mfi"], ComputeMFI(ohlcv), 1e-9)
+
+	total := len(ohlcv)
+	for _, idx := range lo.RangeFrom(0, 10) {
+		gotCMF, err := ComputeCMFOne(ohlcv[:total-idx], DefaultCMFParams())
+		if err != nil {
+			t.Fatal("fail to run ComputeCMFOne()")
+		}
+		assert.InDelta(t, gold["cmf"][total-(idx+1)], gotCMF, 1e-9)
+
+		gotMFI, err := ComputeMFIOne(ohlcv[:total-idx], DefaultMFIParams())
+		if err != nil {
+			t.Fatal("fail to run ComputeMFIOne()")
+		}
+		assert.InDelta(t, gold["mfi"][total-(idx+1)], gotMFI, 1e-9)
+	}
+}
+
+func TestComputeVWAP(t *testing.T) {
+	ohlcv, err := loadOHLCV()
+	if err != nil {
+		t.Fatalf("fail to loadOHLCV")
+	}
+	gold, err := loadJSON("testdata/test_volume.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	assert.InDeltaSlice(t, gold["vwap"], ComputeVWAP(ohlcv), 1e-9)
+
+	total := len(ohlcv)
+	gotOne, err := ComputeVWAPOne(ohlcv, DefaultVWAPParams())
+	if err != nil {
+		t.Fatal("fail to run ComputeVWAPOne()")
+	}
+	assert.InDelta(t, gold["vwap"][total-1], gotOne, 1e-9)
+}
+
+func TestComputeVolumeMA(t *testing.T) {
+	ohlcv, err := loadOHLCV()
+	if err != nil {
+		t.Fatalf("fail to loadOHLCV")
+	}
+	gold, err := loadJSON("testdata/test_volume.json")
+	if err != nil {
+		t.Fatalf("fail to loadJSON")
+	}
+
+	got := ComputeVolumeMA(ohlcv)
+	assert.InDeltaSlice(t, gold["volma"], lo.Map(got, func(v VolumeMA, _ int) float64 { return v.Ma }), 1e-9)
+	assert.InDeltaSlice(t, gold["volratio"], lo.Map(got, func(v VolumeMA, _ int) float64 { return v.Ratio }), 1e-9)
+
+	total := len(ohlcv)
+	gotOne, err := ComputeVolumeMAOne(ohlcv, DefaultVolumeMAParams())
+	if err != nil {
+		t.Fatal("fail to run ComputeVolumeMAOne()")
+	}
+	assert.InDelta(t, gold["volma"][total-1], gotOne.Ma, 1e-9)
+	assert.InDelta(t, gold["volratio"][total-1], gotOne.Ratio, 1e-9)
+}
+
+func TestComputeVolumeSeriesWarmup(t *testing.T) {
+	ohlcv, err := loadOHLCV()
+	if err != nil {
+		t.Fatalf("fail to loadOHLCV")
+	}
+
+	cmf, err := ComputeCMFSeries(ohlcv, DefaultCMFParams())
+	if err != nil {
+		t.Fatal("fail to run ComputeCMFSeries()")
+	}
+	assert.Equal(t, 19, cmf.FirstValid)
+	mfi, _ := ComputeMFISeries(ohlcv, DefaultMFIParams())
+	assert.Equal(t, 14, mfi.FirstValid)
+	volumeMA, _ := ComputeVolumeMASeries(ohlcv, DefaultVolumeMAParams())
+	assert.Equal(t, 5, volumeMA.FirstValid)
+	last, ok := volumeMA.Last()
+	assert.True(t, ok)
+	assert.Equal(t, volumeMA.Values[len(ohlcv)-1], last)
+
+	_, ok = lo.Must(ComputeVWAPSeries(ohlcv[:5], DefaultVWAPParams())).Last()
+	assert.False(t, ok)
+}
@@ -0,0 +1,23 @@
+// Package stocktest holds price and date fixtures shared by tests of stock and its callers.
+package stocktest
+
+import "time"
+
+// DeclineRally returns 100 closes falling by 0.5 a day from 100 for 80 days, then
+// rallying by 2 a day for 20 days: MACD, KD, SMA20 and SMA60 cross upwards during
+// the rally and the last close is a new high.
+func DeclineRally() []float64 {
+	closes := make([]float64, 0, 100) //nolint:gomnd //ignore
+	for idx := range 80 {
+		closes = append(closes, 100.0-0.5*float64(idx)) //nolint:gomnd //ignore
+	}
+	for idx := range 20 {
+		closes = append(closes, 60.0+2.0*float64(idx+1)) //nolint:gomnd //ignore
+	}
+	return closes
+}
+
+// Date returns the date idx days after 2024-01-01, in the layout of daily data.
+func Date(idx int) string {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, idx).Format("2006-01-02 15:04:05.000Z")
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"fmt"
+
+	"example.com/stocker-back/internal/backtest"
+	"example.com/stocker-back/internal/stock"
+)
+
+// RunBacktest replays stored daily history of config.Tickers, all stocks if empty,
+// against the entry and exit rules of config.
+func (q *Query) RunBacktest(config backtest.Config) (backtest.Result, error) {
+	histories, err := backtestHistories(q.repoStock, config.Tickers)
+	if err != nil {
+		return backtest.Result{}, err
+	}
+
+	return backtest.Run(config, histories)
+}
+
+// backtestHistories loads stocks with their full daily history, of tickers only if given.
+func backtestHistories(repo stock.Repository, tickers []string) ([]backtest.History, error) {
+	if len(tickers) > 0 {
+		histories := make([]backtest.History, 0, len(tickers))
+		for _, ticker := range tickers {
+			s, err := repo.GetStockByTicker(ticker)
+			if err != nil {
+				return nil, err
+			}
+			dailyData, err := repo.GetDailyDataByTicker(ticker, 0)
+			if err != nil {
+				return nil, err
+			}
+			histories = append(histories, backtest.History{Stock: s, Daily: dailyData})
+		}
+		return histories, nil
+	}
+
+	stocksAll, err := repo.GetStocks()
+	if err != nil {
+		return nil, err
+	}
+	dailyDataAll, err := repo.GetDailyDataAll()
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]backtest.History, 0, len(stocksAll))
+	for _, s := range stocksAll {
+		dailyData, ok := dailyDataAll[s.Ticker]
+		if !ok {
+			continue
+		}
+		histories = append(histories, backtest.History{Stock: s, Daily: dailyData})
+	}
+
+	return histories, nil
+}
+
+// ReportBacktest runs a backtest of config and computes its performance analytics,
+// relative to the index of benchmark ticker in `daily` if given.
+func (q *Query) ReportBacktest(config backtest.Config, benchmark string) (backtest.Result, backtest.Report, error) {
+	result, err := q.RunBacktest(config)
+	if err != nil {
+		return backtest.Result{}, backtest.Report{}, err
+	}
+
+	var benchmarkData []stock.DailyData
+	if benchmark != "" {
+		benchmarkData, err = q.repoStock.GetDailyDataByTicker(benchmark, 0)
+		if err != nil {
+			return backtest.Result{}, backtest.Report{}, err
+		}
+		if len(benchmarkData) == 0 {
+			return backtest.Result{}, backtest.Report{}, fmt.Errorf("no daily data of benchmark %s", benchmark)
+		}
+	}
+
+	return result, backtest.ComputeReport(result, benchmarkData, backtest.DefaultReportParams()), nil
+}
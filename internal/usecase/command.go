@@ -2,9 +2,15 @@ package usecase
 
 import (
 	"fmt"
+	"sync"
 
+	"example.com/stocker-back/internal/backtest"
 	"example.com/stocker-back/internal/infra"
 	apieastmoney "example.com/stocker-back/internal/infra/api_eastmoney"
+	"example.com/stocker-back/internal/journal"
+	"example.com/stocker-back/internal/portfolio"
+	"example.com/stocker-back/internal/risk"
+	"example.com/stocker-back/internal/scoring"
 	"example.com/stocker-back/internal/screener"
 	"example.com/stocker-back/internal/stock"
 	"example.com/stocker-back/internal/tracking"
@@ -12,23 +18,55 @@ import (
 )
 
 type Command struct {
-	repoStock    stock.Repository
-	repoScreen   screener.Repository
-	repoTracking tracking.Repository
-	logger       infra.Logger
-	notifier     infra.Notifier
+	repoStock     stock.Repository
+	repoScreen    screener.Repository
+	repoTracking  tracking.Repository
+	repoScore     scoring.Repository
+	repoSweep     backtest.Repository
+	repoPortfolio portfolio.Repository
+	repoJournal   journal.Repository
+	logger        infra.Logger
+	notifier      infra.Notifier
+	screenRule    screener.Rule
+	scoreConfig   scoring.Config
+	// sweeping holds names of sweeps in progress.
+	sweeping sync.Map
 }
 
-func NewCommand(repoStock stock.Repository, repoScreen screener.Repository, repoTracking tracking.Repository, logger infra.Logger, notifier infra.Notifier) *Command { //nolint:lll
-	return &Command{
-		repoStock:    repoStock,
-		repoScreen:   repoScreen,
-		repoTracking: repoTracking,
-		logger:       logger,
-		notifier:     notifier,
+func NewCommand(repoStock stock.Repository, repoScreen screener.Repository, repoTracking tracking.Repository, repoScore scoring.Repository, repoSweep backtest.Repository, repoPortfolio portfolio.Repository, repoJournal journal.Repository, logger infra.Logger, notifier infra.Notifier) *Command { //nolint:lll
+	return &Command{ //nolint:exhaustruct
+		repoStock:     repoStock,
+		repoScreen:    repoScreen,
+		repoTracking:  repoTracking,
+		repoScore:     repoScore,
+		repoSweep:     repoSweep,
+		repoPortfolio: repoPortfolio,
+		repoJournal:   repoJournal,
+		logger:        logger,
+		notifier:      notifier,
+		screenRule:    screener.DefaultRule(),
+		scoreConfig:   scoring.DefaultConfig(),
 	}
 }
 
+// SetScreenRule replaces the rule evaluated by the daily screen.
+func (c *Command) SetScreenRule(rule screener.Rule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	c.screenRule = rule
+	return nil
+}
+
+// SetScoreConfig replaces the factors and method of the daily scoring.
+func (c *Command) SetScoreConfig(config scoring.Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	c.scoreConfig = config
+	return nil
+}
+
 func (c *Command) UpdateStocks() error {
 	c.logger.Infof("UpdateStocks - starting...")
 	stocksAll, err := c.repoStock.GetStocks()
@@ -80,6 +118,13 @@ func (c *Command) UpdateDailyData() error {
 	apiServiceEastmoney := apieastmoney.NewAPIServiceEastmoney(c.logger)
 	dailyDataNew := apiServiceEastmoney.CrawlDailyToDate(dailyDataToCrawl)
 
+	// The risk benchmark is an index, not a stock: its history is seeded once, then
+	// crawled along with the stocks.
+	benchmark := risk.DefaultParams().Benchmark
+	if !lo.ContainsBy(dailyDataToCrawl, func(d stock.DailyData) bool { return d.Ticker == benchmark }) {
+		dailyDataNew = append(dailyDataNew, apiServiceEastmoney.CrawlDailyOne(benchmark, benchmarkSeedDays)...)
+	}
+
 	if err = c.repoStock.CreateDailyData(dailyDataNew); err != nil {
 		c.logger.Errorf("SetDailyData()", "error", err.Error())
 		c.notifier.Sendf("SetDailyData()", err.Error())
@@ -89,43 +134,300 @@ func (c *Command) UpdateDailyData() error {
 	c.logger.Infof("total crawled: [%d]", "len", len(dailyDataNew))
 	c.notifier.Sendf("Stocker - total crawled", fmt.Sprintf("%d", len(dailyDataNew)))
 
+	if err = c.UpdateIndicators(); err != nil {
+		c.logger.Errorf("UpdateIndicators()", "error", err.Error())
+		c.notifier.Sendf("UpdateIndicators()", err.Error())
+		return err
+	}
+
 	return nil
 }
 
+// benchmarkSeedDays is how many calendar days of the risk benchmark are crawled first,
+// over a year to cover the default lookback.
+const benchmarkSeedDays = 400
+
+// indicatorsWindow is how many recent candles are loaded to advance indicators incrementally.
+const indicatorsWindow = stock.IndicatorsLookback + 60
+
+// UpdateIndicators advances the persisted indicators of every stock by the candles added since.
+func (c *Command) UpdateIndicators() error {
+	c.logger.Infof("UpdateIndicators", "message", "start...")
+	stocksAll, err := c.repoStock.GetStocks()
+	if err != nil {
+		return err
+	}
+
+	indicatorsLastAll, err := c.repoStock.GetIndicatorsLastAll()
+	if err != nil {
+		return err
+	}
+	indicatorsLast := lo.KeyBy(indicatorsLastAll, func(indicators stock.Indicators) string {
+		return indicators.Ticker
+	})
+
+	total := 0
+	failedTickers := make([]string, 0)
+	for _, s := range stocksAll {
+		indicatorsNew, err := c.advanceIndicators(s.Ticker, indicatorsLast)
+		if err != nil {
+			c.logger.Errorf("advanceIndicators", "error", err.Error(), "ticker", s.Ticker)
+			failedTickers = append(failedTickers, s.Ticker)
+			continue
+		}
+
+		if err := c.repoStock.CreateIndicators(indicatorsNew); err != nil {
+			c.logger.Errorf("CreateIndicators", "error", err.Error(), "ticker", s.Ticker)
+			failedTickers = append(failedTickers, s.Ticker)
+			continue
+		}
+		total += len(indicatorsNew)
+	}
+
+	c.logger.Infof("UpdateIndicators - DONE", "total", total, "failed", len(failedTickers), "tickers", failedTickers)
+
+	return nil
+}
+
+// advanceIndicators computes indicators of ticker for the days after its last
+// persisted state, from full history when the state cannot be advanced.
+func (c *Command) advanceIndicators(ticker string, indicatorsLast map[string]stock.Indicators) ([]stock.Indicators, error) { //nolint:lll
+	last, hasLast := indicatorsLast[ticker]
+	if hasLast {
+		dailyData, err := c.repoStock.GetDailyDataByTicker(ticker, indicatorsWindow)
+		if err != nil {
+			return nil, err
+		}
+		if indicatorsNew, ok := stock.AdvanceIndicators(stock.DailyData2OHLC(dailyData), last); ok {
+			return indicatorsNew, nil
+		}
+	}
+
+	dailyData, err := c.repoStock.GetDailyDataByTicker(ticker, 0)
+	if err != nil {
+		return nil, err
+	}
+	indicatorsAll, err := stock.ComputeIndicators(ticker, stock.DailyData2OHLC(dailyData))
+	if err != nil {
+		return nil, err
+	}
+	if !hasLast {
+		return indicatorsAll, nil
+	}
+
+	return lo.Filter(indicatorsAll, func(indicators stock.Indicators, _ int) bool {
+		return indicators.Date > last.Date
+	}), nil
+}
+
+// RebuildIndicators recomputes the indicators collection from full daily history,
+// for when past daily data has changed.
+func (c *Command) RebuildIndicators() error {
+	c.logger.Infof("RebuildIndicators", "message", "start...")
+	if err := c.repoStock.DeleteIndicatorsAll(); err != nil {
+		return err
+	}
+
+	if err := c.UpdateIndicators(); err != nil {
+		return err
+	}
+
+	c.notifier.Sendf("RebuildIndicators DONE", "indicators rebuilt from full daily history")
+
+	return nil
+}
+
+// UpdateDailyScreen runs the default screen, then saved screens without own schedule.
 func (c *Command) UpdateDailyScreen() error {
 	c.logger.Infof("UpdateDailyScreen", "message", "start...")
 
-	dailyDataLastAll, err := c.repoStock.GetDailyDataAll()
+	screens, date, err := c.screenWith(c.screenRule)
+	if err != nil {
+		return err
+	}
+
+	err = c.repoScreen.SetScreens(screens)
 	if err != nil {
 		return err
 	}
+	if err := c.repoScreen.SaveSnapshots("", date, screens); err != nil {
+		return err
+	}
 
-	screens := make([]screener.Screen, 0, len(dailyDataLastAll))
-	for key, data := range dailyDataLastAll {
-		candles := make([]stock.OHLC, len(data))
-		for i, c := range data {
-			candles[i].Date = c.Date
-			candles[i].Open = c.Open
-			candles[i].High = c.High
-			candles[i].Low = c.Low
-			candles[i].Close = c.Close
+	definitions, err := c.repoScreen.GetDefinitions()
+	if err != nil {
+		return err
+	}
+	for _, definition := range definitions {
+		if definition.Schedule != "" {
+			continue
 		}
+		if err := c.RunScreen(definition.Name); err != nil {
+			c.logger.Errorf("RunScreen", "error", err.Error(), "name", definition.Name)
+		}
+	}
+
+	return nil
+}
+
+// RunScreen evaluates the saved screen of given name and replaces its hits.
+func (c *Command) RunScreen(name string) error {
+	definition, err := c.repoScreen.GetDefinitionByName(name)
+	if err != nil {
+		return err
+	}
+
+	screens, date, err := c.screenWith(definition.Rule)
+	if err != nil {
+		return err
+	}
+
+	c.logger.Infof("RunScreen", "name", name, "hits", len(screens))
+
+	if err := c.repoScreen.SetScreensByName(name, screens); err != nil {
+		return err
+	}
+
+	return c.repoScreen.SaveSnapshots(name, date, screens)
+}
+
+// CreateScreenDefinition saves a new named screen.
+func (c *Command) CreateScreenDefinition(definition screener.Definition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.repoScreen.GetDefinitionByName(definition.Name); err == nil {
+		return fmt.Errorf("%w: screen %q already exists", screener.ErrInvalidDefinition, definition.Name)
+	}
+
+	return c.repoScreen.SaveDefinition(definition)
+}
+
+// UpdateScreenDefinition replaces an existing named screen.
+func (c *Command) UpdateScreenDefinition(definition screener.Definition) error {
+	if err := definition.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.repoScreen.GetDefinitionByName(definition.Name); err != nil {
+		return err
+	}
+
+	return c.repoScreen.SaveDefinition(definition)
+}
+
+// DeleteScreenDefinition deletes a named screen and its hits.
+func (c *Command) DeleteScreenDefinition(name string) error {
+	return c.repoScreen.DeleteDefinitionByName(name)
+}
+
+// screenWith evaluates rule against latest indicators of all stocks, returning hits
+// and the latest trading date screened.
+func (c *Command) screenWith(rule screener.Rule) ([]screener.Screen, string, error) {
+	stocksAll, err := c.repoStock.GetStocks()
+	if err != nil {
+		return nil, "", err
+	}
+	stocksByTicker := lo.KeyBy(stocksAll, func(s stock.Stock) string {
+		return s.Ticker
+	})
+
+	indicatorsLastAll, err := c.repoStock.GetIndicatorsLastAll()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Sector aggregates need returns of the whole universe, computed once per run.
+	var returns map[string]map[string]float64
+	var sectors map[string]stock.SectorStats
+	if rule.NeedsSector() {
+		returns = sectorReturns(c.repoStock, c.logger, stocksAll)
+		sectors = lo.KeyBy(stock.ComputeSectorStats(stocksAll, returns), func(stats stock.SectorStats) string {
+			return stats.Sector
+		})
+	}
 
-		kdj := stock.ComputeKDJ(candles)
-		lastJ := kdj[len(kdj)-1].J
+	date := ""
+	screens := make([]screener.Screen, 0, len(indicatorsLastAll))
+	skippedTickers := make([]string, 0)
+	failedTickers := make([]string, 0)
+	for _, indicators := range indicatorsLastAll {
+		// Skip stocks whose history is still too short for a settled KDJ.
+		if !indicators.ValidKDJ() {
+			skippedTickers = append(skippedTickers, indicators.Ticker)
+			continue
+		}
+		date = max(date, indicators.Date)
+
+		s := stocksByTicker[indicators.Ticker]
+		facts, err := c.screenFacts(rule, s, indicators)
+		if err != nil {
+			c.logger.Errorf("screenFacts", "error", err.Error(), "ticker", indicators.Ticker)
+			failedTickers = append(failedTickers, indicators.Ticker)
+			continue
+		}
+		if rule.NeedsSector() {
+			facts.SetSector(sectors[s.Sector], returns[indicators.Ticker])
+		}
+
+		ok, err := rule.Eval(facts)
+		if err != nil {
+			c.logger.Errorf("Eval", "error", err.Error(), "ticker", indicators.Ticker)
+			failedTickers = append(failedTickers, indicators.Ticker)
+			continue
+		}
+		if !ok {
+			continue
+		}
 
 		screens = append(screens, screener.Screen{
-			Ticker: key,
-			Kdj:    lastJ,
+			Name:   "",
+			Ticker: indicators.Ticker,
+			Kdj:    indicators.J,
+			Events: rule.FiredEvents(facts),
 		})
 	}
+	c.logger.Infof("screenWith", "skipped warm-up", len(skippedTickers), "tickers", skippedTickers)
+	c.logger.Infof("screenWith", "hits", len(screens), "failed", len(failedTickers), "tickers", failedTickers)
 
-	err = c.repoScreen.SetScreens(screens)
+	return screens, date, nil
+}
+
+// screenFacts gathers what rule needs of a ticker, reading daily data only if the rule asks for it,
+// and candles for candle criteria and technical fields.
+func (c *Command) screenFacts(rule screener.Rule, s stock.Stock, indicators stock.Indicators) (screener.Facts, error) {
+	daily := stock.NewEmptyDailyData()
+	var candles []stock.OHLC
+	var candlesVolume []stock.OHLCV
+
+	switch {
+	case rule.NeedsCandles() || rule.NeedsTechnicals():
+		dailyData, err := c.repoStock.GetDailyDataByTicker(indicators.Ticker, stock.IndicatorsLookback)
+		if err != nil {
+			return screener.Facts{}, err
+		}
+		if len(dailyData) > 0 {
+			daily = dailyData[len(dailyData)-1]
+		}
+		candles = stock.DailyData2OHLC(dailyData)
+		candlesVolume = stock.DailyData2OHLCV(dailyData)
+	case rule.NeedsDaily():
+		dailyLast, err := c.repoStock.GetDailyDataLastByTicker(indicators.Ticker)
+		if err != nil {
+			return screener.Facts{}, err
+		}
+		daily = dailyLast
+	}
+
+	facts, err := screener.NewFacts(s, indicators, daily)
 	if err != nil {
-		return err
+		return screener.Facts{}, err
+	}
+	facts.Candles = candles
+	if rule.NeedsTechnicals() {
+		facts.SetTechnicals(candlesVolume)
 	}
 
-	return nil
+	return facts, nil
 }
 
 func (c *Command) CreateStock(ticker string) error {
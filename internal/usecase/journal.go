@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"example.com/stocker-back/internal/journal"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// RecordTrade validates and saves a trade of a known stock; sells beyond the shares
+// held by then are journal.ErrOversold.
+func (c *Command) RecordTrade(trade journal.Trade) (journal.Trade, error) {
+	if err := trade.Validate(); err != nil {
+		return journal.Trade{}, err
+	}
+	if _, err := c.repoStock.GetStockByTicker(trade.Ticker); err != nil {
+		return journal.Trade{}, fmt.Errorf("%w: unknown ticker %s", journal.ErrInvalidTrade, trade.Ticker)
+	}
+
+	trades, err := c.repoJournal.GetTradesByTicker(trade.Ticker)
+	if err != nil {
+		return journal.Trade{}, err
+	}
+	trade.ID = ""
+	if _, err := journal.Replay(append(trades, trade), journal.MethodFIFO); err != nil {
+		return journal.Trade{}, err
+	}
+
+	created, err := c.repoJournal.CreateTrades([]journal.Trade{trade})
+	if err != nil {
+		return journal.Trade{}, err
+	}
+	return created[0], nil
+}
+
+// ImportTrades saves trades of a broker export in format at once, leaving out rows
+// already in the journal, so that overlapping exports can be imported again. Nothing
+// is saved if any row is invalid or sells beyond the shares held.
+func (c *Command) ImportTrades(r io.Reader, format journal.CSVFormat) (journal.Import, error) {
+	parsed, skipped, err := journal.ParseCSV(r, format)
+	if err != nil {
+		return journal.Import{}, err
+	}
+	trades, err := c.repoJournal.GetTrades()
+	if err != nil {
+		return journal.Import{}, err
+	}
+
+	// A stored trade matches one row at most, keeping rows repeated within the export,
+	// e.g. partial fills at the same price.
+	stored := lo.CountValuesBy(trades, journal.Trade.Key)
+	imports := make([]journal.Trade, 0, len(parsed))
+	for _, t := range parsed {
+		if stored[t.Key()] > 0 {
+			stored[t.Key()]--
+			continue
+		}
+		imports = append(imports, t)
+	}
+	result := journal.Import{
+		Imported:   len(imports),
+		Duplicates: len(parsed) - len(imports),
+		Skipped:    skipped,
+	}
+	if len(imports) == 0 {
+		return result, nil
+	}
+
+	if _, err := journal.Replay(append(trades, imports...), journal.MethodFIFO); err != nil {
+		return journal.Import{}, err
+	}
+	if _, err := c.repoJournal.CreateTrades(imports); err != nil {
+		return journal.Import{}, err
+	}
+
+	c.logger.Infof("ImportTrades - DONE", "imported", result.Imported, "duplicates", result.Duplicates, "skipped", result.Skipped)
+	return result, nil
+}
+
+// DeleteTrade deletes the trade of id unless sells after it would exceed the shares
+// held without it.
+func (c *Command) DeleteTrade(id string) error {
+	trades, err := c.repoJournal.GetTrades()
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(trades, func(t journal.Trade) bool { return t.ID == id })
+	if idx < 0 {
+		return fmt.Errorf("%w: no trade %s", journal.ErrInvalidTrade, id)
+	}
+	if _, err := journal.Replay(slices.Delete(trades, idx, idx+1), journal.MethodFIFO); err != nil {
+		return err
+	}
+
+	return c.repoJournal.DeleteTradeByID(id)
+}
+
+// GetJournal queries the journal replayed with method, open positions marked at the
+// last daily data of each ticker.
+func (q *Query) GetJournal(method journal.Method) (journal.Book, error) {
+	return journalBook(q.repoJournal, q.repoStock, method)
+}
+
+// GetJournalTrades queries trades of ticker in date order, all trades if empty.
+func (q *Query) GetJournalTrades(ticker string) ([]journal.Trade, error) {
+	if ticker == "" {
+		return q.repoJournal.GetTrades()
+	}
+	return q.repoJournal.GetTradesByTicker(ticker)
+}
+
+// journalBook replays the journal with method, fifo if empty, and marks open positions
+// at the last daily data of each ticker.
+func journalBook(repo journal.Repository, repoStock stock.Repository, method journal.Method) (journal.Book, error) {
+	if method == "" {
+		method = journal.MethodFIFO
+	}
+	trades, err := repo.GetTrades()
+	if err != nil {
+		return journal.Book{}, err
+	}
+	book, err := journal.Replay(trades, method)
+	if err != nil {
+		return journal.Book{}, err
+	}
+
+	stocks := make(map[string]stock.Stock, len(book.Positions))
+	last := make(map[string]stock.DailyData, len(book.Positions))
+	for _, p := range book.Positions {
+		// Positions without stock or daily data are left unnamed, marked at cost.
+		if s, err := repoStock.GetStockByTicker(p.Ticker); err == nil {
+			stocks[p.Ticker] = s
+		}
+		if p.Shares <= 0.0 {
+			continue
+		}
+		if dailyData, err := repoStock.GetDailyDataLastByTicker(p.Ticker); err == nil {
+			last[p.Ticker] = dailyData
+		}
+	}
+
+	return book.Mark(stocks, last), nil
+}
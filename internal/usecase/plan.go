@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"example.com/stocker-back/internal/screener"
+	"example.com/stocker-back/internal/stock"
+)
+
+// GetScreensPlanned queries hits of the saved screen of given name, the default daily
+// screen if empty, augmented like GetScreens with a trade planned on each under "plan",
+// see screener.ComputePlan; hits without enough daily data have no plan.
+func (q *Query) GetScreensPlanned(name string, params screener.PlanParams) ([]map[string]interface{}, error) {
+	params = params.WithDefaults()
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	var screens []map[string]interface{}
+	var err error
+	if name == "" {
+		screens, err = q.GetScreens()
+	} else {
+		screens, err = q.GetScreensByName(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range screens {
+		ticker, _ := m["ticker"].(string)
+		m["plan"] = nil
+
+		dailyData, err := q.repoStock.GetDailyDataByTicker(ticker, stock.DefaultLevelsParams().Lookback)
+		if err != nil {
+			q.logger.Errorf("GetDailyDataByTicker", "error", err.Error(), "ticker", ticker)
+			continue
+		}
+		plan, err := screener.ComputePlan(dailyData, params)
+		if err != nil {
+			q.logger.Errorf("ComputePlan", "error", err.Error(), "ticker", ticker)
+			continue
+		}
+		m["plan"] = plan
+	}
+
+	return screens, nil
+}
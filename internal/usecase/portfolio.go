@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"example.com/stocker-back/internal/common"
+	"example.com/stocker-back/internal/portfolio"
+	"example.com/stocker-back/internal/screener"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// CreateAccount opens a paper-trading account with its capital in cash.
+func (c *Command) CreateAccount(account portfolio.Account) error {
+	account = account.WithDefaults()
+	if err := account.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.repoPortfolio.GetAccountByName(account.Name); err == nil {
+		return fmt.Errorf("%w: account %s exists", portfolio.ErrInvalidAccount, account.Name)
+	}
+
+	account.Cash, account.Created = account.Capital, ""
+	return c.repoPortfolio.CreateAccount(account)
+}
+
+// DeleteAccount deletes the paper-trading account of name with its orders and positions.
+func (c *Command) DeleteAccount(name string) error {
+	return c.repoPortfolio.DeleteAccountByName(name)
+}
+
+// PlaceOrder validates and saves a pending order of a known stock, filled by FillOrders.
+func (c *Command) PlaceOrder(order portfolio.Order) (portfolio.Order, error) {
+	account, err := c.repoPortfolio.GetAccountByName(order.Account)
+	if err != nil {
+		return portfolio.Order{}, err
+	}
+	if err := order.Validate(*account.WithDefaults().Market); err != nil {
+		return portfolio.Order{}, err
+	}
+	if _, err := c.repoStock.GetStockByTicker(order.Ticker); err != nil {
+		return portfolio.Order{}, fmt.Errorf("%w: unknown ticker %s", portfolio.ErrInvalidOrder, order.Ticker)
+	}
+
+	if order.Source == "" {
+		order.Source = portfolio.SourceManual
+	}
+	order.ID, order.Status, order.Reason = "", portfolio.OrderPending, ""
+	order.PlacedAt = time.Now().UTC().Format(common.DateLayoutPocketbase)
+	order.FilledAt, order.Price, order.Fees, order.Realized = "", 0.0, 0.0, 0.0
+
+	return c.repoPortfolio.CreateOrder(order)
+}
+
+// CancelOrder cancels a pending order of account.
+func (c *Command) CancelOrder(account, id string) error {
+	order, err := c.repoPortfolio.GetOrderByID(id)
+	if err != nil {
+		return err
+	}
+	if order.Account != account || order.Status != portfolio.OrderPending {
+		return fmt.Errorf("%w: no pending order %s in %s", portfolio.ErrInvalidOrder, id, account)
+	}
+
+	order.Status = portfolio.OrderCancelled
+	return c.repoPortfolio.UpdateOrder(order)
+}
+
+// FillOrders fills pending orders, oldest first, at the open of the first daily data
+// stored after the day each was placed; orders without it yet stay pending.
+func (c *Command) FillOrders() error {
+	orders, err := c.repoPortfolio.GetOrdersPending()
+	if err != nil {
+		return err
+	}
+
+	filled, rejected := 0, 0
+	for _, order := range orders {
+		s, err := c.repoStock.GetStockByTicker(order.Ticker)
+		if err != nil {
+			c.logger.Errorf("GetStockByTicker", "error", err.Error(), "ticker", order.Ticker)
+			continue
+		}
+		dailyData, err := c.repoStock.GetDailyDataByTickerSince(order.Ticker, fillSince(order.PlacedAt))
+		if err != nil {
+			c.logger.Errorf("GetDailyDataByTickerSince", "error", err.Error(), "ticker", order.Ticker)
+			continue
+		}
+		bar, prevClose, ok := portfolio.NextBar(dailyData, order.PlacedAt)
+		if !ok {
+			continue
+		}
+
+		// Fills change the account, read it afresh for each order.
+		account, err := c.repoPortfolio.GetAccountByName(order.Account)
+		if err != nil {
+			c.logger.Errorf("GetAccountByName", "error", err.Error(), "account", order.Account)
+			continue
+		}
+		positions, err := c.repoPortfolio.GetPositions(order.Account)
+		if err != nil {
+			c.logger.Errorf("GetPositions", "error", err.Error(), "account", order.Account)
+			continue
+		}
+		position, _ := lo.Find(positions, func(p portfolio.Position) bool { return p.Ticker == order.Ticker })
+
+		account, position, order = portfolio.Fill(account, position, order, s, prevClose, bar)
+		if order.Status == portfolio.OrderFilled {
+			err = c.repoPortfolio.SaveFill(account, position, order)
+		} else {
+			err = c.repoPortfolio.UpdateOrder(order)
+		}
+		switch {
+		case err != nil:
+			c.logger.Errorf("FillOrders", "error", err.Error(), "account", order.Account, "ticker", order.Ticker)
+		case order.Status == portfolio.OrderFilled:
+			filled++
+		default:
+			rejected++
+		}
+	}
+
+	if filled+rejected > 0 {
+		c.logger.Infof("FillOrders - DONE", "filled", filled, "rejected", rejected)
+		c.notifier.Sendf("FillOrders DONE", fmt.Sprintf("filled: %d rejected: %d", filled, rejected))
+	}
+	return nil
+}
+
+// fillSince returns the day daily data is loaded from to fill an order placed at
+// placedAt: two weeks before it, so that the close before the next bar is found
+// across the longest market closure.
+func fillSince(placedAt string) string {
+	placed, err := time.Parse(time.DateOnly, common.Day(placedAt))
+	if err != nil {
+		return common.Day(placedAt)
+	}
+	return placed.AddDate(0, 0, -14).Format(time.DateOnly) //nolint:gomnd //ignore
+}
+
+// PlaceSignalOrders places buys of the latest hits of the screen of each signal-driven
+// account, see portfolio.SignalOrders.
+func (c *Command) PlaceSignalOrders() error {
+	accounts, err := c.repoPortfolio.GetAccounts()
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		if account.Screen == nil {
+			continue
+		}
+		if err := c.placeSignalOrders(account); err != nil {
+			c.logger.Errorf("PlaceSignalOrders", "error", err.Error(), "account", account.Name)
+		}
+	}
+	return nil
+}
+
+func (c *Command) placeSignalOrders(account portfolio.Account) error {
+	screens, err := c.repoScreen.GetScreensByName(*account.Screen)
+	if err != nil {
+		return err
+	}
+	valuation, positions, orders, err := valueAccount(c.repoPortfolio, c.repoStock, account)
+	if err != nil {
+		return err
+	}
+	pending := lo.Filter(orders, func(o portfolio.Order, _ int) bool { return o.Status == portfolio.OrderPending })
+	hits := lo.Map(screens, func(s screener.Screen, _ int) string { return s.Ticker })
+
+	for _, order := range portfolio.SignalOrders(account, positions, pending, hits, valuation.Equity) {
+		if _, err := c.PlaceOrder(order); err != nil {
+			c.logger.Errorf("PlaceOrder", "error", err.Error(), "account", account.Name, "ticker", order.Ticker)
+		}
+	}
+	return nil
+}
+
+// GetAccounts queries all paper-trading accounts.
+func (q *Query) GetAccounts() ([]portfolio.Account, error) {
+	return q.repoPortfolio.GetAccounts()
+}
+
+// GetPortfolio queries holdings and P&L of the account of name marked at the last
+// daily data of each ticker.
+func (q *Query) GetPortfolio(name string) (portfolio.Valuation, error) {
+	account, err := q.repoPortfolio.GetAccountByName(name)
+	if err != nil {
+		return portfolio.Valuation{}, err
+	}
+
+	valuation, _, _, err := valueAccount(q.repoPortfolio, q.repoStock, account)
+	return valuation, err
+}
+
+// GetOrders queries orders of the account of name, latest placed first.
+func (q *Query) GetOrders(name string) ([]portfolio.Order, error) {
+	if _, err := q.repoPortfolio.GetAccountByName(name); err != nil {
+		return nil, err
+	}
+
+	return q.repoPortfolio.GetOrders(name)
+}
+
+// valueAccount marks account to market with its positions and orders, returned along.
+func valueAccount(repo portfolio.Repository, repoStock stock.Repository, account portfolio.Account) (portfolio.Valuation, []portfolio.Position, []portfolio.Order, error) { //nolint:lll
+	positions, err := repo.GetPositions(account.Name)
+	if err != nil {
+		return portfolio.Valuation{}, nil, nil, err
+	}
+	orders, err := repo.GetOrders(account.Name)
+	if err != nil {
+		return portfolio.Valuation{}, nil, nil, err
+	}
+
+	last := make(map[string]stock.DailyData, len(positions))
+	names := make(map[string]string, len(positions))
+	for _, p := range positions {
+		// Positions without daily data or stock are marked at cost, unnamed.
+		if dailyData, err := repoStock.GetDailyDataLastByTicker(p.Ticker); err == nil {
+			last[p.Ticker] = dailyData
+		}
+		if s, err := repoStock.GetStockByTicker(p.Ticker); err == nil {
+			names[p.Ticker] = s.Name
+		}
+	}
+
+	return portfolio.Value(account, positions, orders, last, names), positions, orders, nil
+}
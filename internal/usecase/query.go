@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"slices"
 
+	"example.com/stocker-back/internal/backtest"
 	"example.com/stocker-back/internal/infra"
+	"example.com/stocker-back/internal/journal"
+	"example.com/stocker-back/internal/portfolio"
+	"example.com/stocker-back/internal/scoring"
 	"example.com/stocker-back/internal/screener"
 	"example.com/stocker-back/internal/stock"
 	"example.com/stocker-back/internal/tracking"
@@ -12,21 +16,29 @@ import (
 )
 
 type Query struct {
-	repoStock    stock.Repository
-	repoScreen   screener.Repository
-	repoTracking tracking.Repository
-	logger       infra.Logger
-	notifier     infra.Notifier
+	repoStock     stock.Repository
+	repoScreen    screener.Repository
+	repoTracking  tracking.Repository
+	repoScore     scoring.Repository
+	repoSweep     backtest.Repository
+	repoPortfolio portfolio.Repository
+	repoJournal   journal.Repository
+	logger        infra.Logger
+	notifier      infra.Notifier
 }
 
 // DELE: fix this into config.
-func NewQuery(repoStock stock.Repository, repoScreen screener.Repository, repoTracking tracking.Repository, logger infra.Logger, notifier infra.Notifier) *Query { //nolint:lll
+func NewQuery(repoStock stock.Repository, repoScreen screener.Repository, repoTracking tracking.Repository, repoScore scoring.Repository, repoSweep backtest.Repository, repoPortfolio portfolio.Repository, repoJournal journal.Repository, logger infra.Logger, notifier infra.Notifier) *Query { //nolint:lll
 	return &Query{
-		repoStock:    repoStock,
-		repoScreen:   repoScreen,
-		repoTracking: repoTracking,
-		logger:       logger,
-		notifier:     notifier,
+		repoStock:     repoStock,
+		repoScreen:    repoScreen,
+		repoTracking:  repoTracking,
+		repoScore:     repoScore,
+		repoSweep:     repoSweep,
+		repoPortfolio: repoPortfolio,
+		repoJournal:   repoJournal,
+		logger:        logger,
+		notifier:      notifier,
 	}
 }
 
@@ -60,6 +72,30 @@ func (q *Query) GetScreens() ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
+	return q.enrichScreens(screens)
+}
+
+// GetScreensByName queries hits of the saved screen of given name augmented like GetScreens.
+func (q *Query) GetScreensByName(name string) ([]map[string]interface{}, error) {
+	if _, err := q.repoScreen.GetDefinitionByName(name); err != nil {
+		return nil, err
+	}
+
+	screens, err := q.repoScreen.GetScreensByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.enrichScreens(screens)
+}
+
+// GetScreenDefinitions queries all saved screens.
+func (q *Query) GetScreenDefinitions() ([]screener.Definition, error) {
+	return q.repoScreen.GetDefinitions()
+}
+
+// enrichScreens augments screen hits with stock, tracking and daily value.
+func (q *Query) enrichScreens(screens []screener.Screen) ([]map[string]interface{}, error) {
 	// DELE: better shape
 	trackings, err := q.repoTracking.GetTrackings()
 	if err != nil {
@@ -68,11 +104,6 @@ func (q *Query) GetScreens() ([]map[string]interface{}, error) {
 
 	var output []map[string]interface{}
 	for _, s := range screens {
-		// DELE: better shape
-		if s.Kdj > 30 { //nolint:gomnd // ignore
-			continue
-		}
-
 		var m map[string]interface{}
 
 		stock, err := q.repoStock.GetStockByTicker(s.Ticker)
@@ -87,6 +118,7 @@ func (q *Query) GetScreens() ([]map[string]interface{}, error) {
 			return nil, err
 		}
 		m["screenkdj"] = s.Kdj
+		m["screenevents"] = s.Events
 
 		isTracked := slices.ContainsFunc(trackings, func(t tracking.Tracking) bool {
 			return t.Ticker == stock.Ticker
@@ -132,6 +164,19 @@ func (q *Query) GetTrackings() ([]map[string]interface{}, error) {
 
 		m["tracking"] = true
 
+		m["support"], m["resistance"] = nil, nil
+		m["supportdistance"], m["resistancedistance"] = nil, nil
+		if levels, err := q.GetLevelsByTicker(s.Ticker); err == nil {
+			if distance, ok := levels.DistanceToSupport(); ok {
+				m["support"] = levels.Support.Price
+				m["supportdistance"] = distance
+			}
+			if distance, ok := levels.DistanceToResistance(); ok {
+				m["resistance"] = levels.Resistance.Price
+				m["resistancedistance"] = distance
+			}
+		}
+
 		output = append(output, m)
 	}
 
@@ -175,3 +220,76 @@ func (q *Query) GetStocksBySector(sector string) ([]map[string]any, error) {
 
 	return output, nil
 }
+
+// GetPatternsByTicker queries candlestick patterns fired within the last `days` trading days.
+func (q *Query) GetPatternsByTicker(ticker string, days int) ([]stock.Pattern, error) {
+	dailyData, err := q.repoStock.GetDailyDataByTicker(ticker, days+stock.PatternLookback)
+	if err != nil {
+		return nil, err
+	}
+
+	return stock.DetectPatternsRecent(stock.DailyData2OHLC(dailyData), days), nil
+}
+
+// GetDivergencesByTicker queries price/indicator divergences ending within the last `days` trading days.
+func (q *Query) GetDivergencesByTicker(ticker string, days int) ([]stock.Divergence, error) {
+	dailyData, err := q.repoStock.GetDailyDataByTicker(ticker, days+stock.IndicatorsLookback)
+	if err != nil {
+		return nil, err
+	}
+
+	candles := stock.DailyData2OHLC(dailyData)
+	if len(candles) == 0 {
+		return []stock.Divergence{}, nil
+	}
+	since := candles[max(len(candles)-days, 0)].Date
+
+	return lo.Filter(stock.ComputeDivergences(candles), func(d stock.Divergence, _ int) bool {
+		return d.End >= since
+	}), nil
+}
+
+// GetLevelsByTicker queries support/resistance levels as of the latest trading day.
+func (q *Query) GetLevelsByTicker(ticker string) (stock.Levels, error) {
+	dailyData, err := q.repoStock.GetDailyDataByTicker(ticker, stock.DefaultLevelsParams().Lookback)
+	if err != nil {
+		return stock.Levels{}, err
+	}
+
+	return stock.ComputeLevels(stock.DailyData2OHLCV(dailyData))
+}
+
+// screenHistoryRuns is the number of latest runs hit history looks back on.
+const screenHistoryRuns = 250
+
+// GetScreenHistoryByTicker queries when ticker last appeared in the screen of given name
+// (empty for the default daily screen) and for how many consecutive runs.
+func (q *Query) GetScreenHistoryByTicker(name, ticker string) (screener.HitHistory, error) {
+	runDates, err := q.repoScreen.GetSnapshotDates(name, screenHistoryRuns)
+	if err != nil {
+		return screener.HitHistory{}, err
+	}
+
+	snapshots, err := q.repoScreen.GetSnapshotsByTicker(name, ticker)
+	if err != nil {
+		return screener.HitHistory{}, err
+	}
+
+	return screener.ComputeHitHistory(name, ticker, runDates, snapshots), nil
+}
+
+// GetScreenDiff queries tickers that entered or left the screen of given name on date,
+// the latest run if date is empty, compared with the run before it.
+func (q *Query) GetScreenDiff(name, date string) (screener.Diff, error) {
+	return screenDiff(q.repoScreen, name, date)
+}
+
+// GetEventsByTicker queries crossover and breakout events fired within the last `days` trading days.
+func (q *Query) GetEventsByTicker(ticker string, days int) ([]stock.Event, error) {
+	dailyData, err := q.repoStock.GetDailyDataByTicker(ticker, days+stock.IndicatorsLookback)
+	if err != nil {
+		return nil, err
+	}
+
+	return stock.DetectEventsRecent(stock.DailyData2OHLC(dailyData), days), nil
+}
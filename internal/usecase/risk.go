@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/stocker-back/internal/journal"
+	"example.com/stocker-back/internal/portfolio"
+	"example.com/stocker-back/internal/risk"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// GetPortfolioRisk queries the risk of the holdings of the paper-trading account of name.
+func (q *Query) GetPortfolioRisk(name string, params risk.Params) (risk.Report, error) {
+	return portfolioRisk(q.repoPortfolio, q.repoStock, name, params)
+}
+
+// GetJournalRisk queries the risk of the open positions of the trade journal.
+func (q *Query) GetJournalRisk(params risk.Params) (risk.Report, error) {
+	return journalRisk(q.repoJournal, q.repoStock, params)
+}
+
+// NotifyRisk sends the risk of the trade journal and of each paper-trading account
+// holding stocks, one notification each.
+func (c *Command) NotifyRisk() error {
+	// Defaults all, the benchmark left out of reports until stored.
+	params := risk.Params{} //nolint:exhaustruct
+	sent := 0
+	notify := func(name string, report risk.Report, err error) {
+		switch {
+		case errors.Is(err, risk.ErrNoHoldings):
+		case err != nil:
+			c.logger.Errorf("NotifyRisk", "error", err.Error(), "name", name)
+		default:
+			c.notifier.Sendf(fmt.Sprintf("risk %s %s", name, report.End), riskMessage(report))
+			sent++
+		}
+	}
+
+	report, err := journalRisk(c.repoJournal, c.repoStock, params)
+	notify("journal", report, err)
+
+	accounts, err := c.repoPortfolio.GetAccounts()
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		report, err := portfolioRisk(c.repoPortfolio, c.repoStock, account.Name, params)
+		notify("portfolio "+account.Name, report, err)
+	}
+
+	c.logger.Infof("NotifyRisk - DONE", "sent", sent)
+	return nil
+}
+
+// riskMessage formats the summary of report sent weekly.
+func riskMessage(report risk.Report) string {
+	var b strings.Builder
+	beta := "n/a"
+	if report.Beta != nil {
+		beta = fmt.Sprintf("%.2f", *report.Beta)
+	}
+	fmt.Fprintf(&b, "value=%.0f vol=%.1f%% beta=%s div=%.2f corr=%.2f\n",
+		report.Value, report.Volatility, beta, report.DiversificationRatio, report.AvgCorrelation)
+	fmt.Fprintf(&b, "largest %s %.1f%% top5 %.1f%% effective %.1f\n",
+		report.Concentration.Largest, report.Concentration.LargestWeight,
+		report.Concentration.Top5Weight, report.Concentration.Effective)
+	for _, e := range report.Exposures[:min(3, len(report.Exposures))] { //nolint:gomnd //ignore
+		fmt.Fprintf(&b, "[%s] %.1f%%\n", e.Sector, e.Weight)
+	}
+	for _, v := range report.VaR {
+		fmt.Fprintf(&b, "%s %dd VaR %.0f%%=%.0f (%.1f%%) ES=%.0f (%.1f%%)\n",
+			v.Method, v.Horizon, 100.0*report.Confidence, v.VaRAmount, v.VaR, v.ESAmount, v.ES) //nolint:gomnd //ignore
+	}
+	return b.String()
+}
+
+// portfolioRisk assesses the holdings of the paper-trading account of name.
+func portfolioRisk(repo portfolio.Repository, repoStock stock.Repository, name string, params risk.Params) (risk.Report, error) { //nolint:lll
+	account, err := repo.GetAccountByName(name)
+	if err != nil {
+		return risk.Report{}, err
+	}
+	valuation, _, _, err := valueAccount(repo, repoStock, account)
+	if err != nil {
+		return risk.Report{}, err
+	}
+
+	holdings := lo.Map(valuation.Holdings, func(h portfolio.Holding, _ int) risk.Holding {
+		return risk.Holding{Ticker: h.Ticker, Name: h.Name, Sector: "", Value: h.Value}
+	})
+	return assessRisk(repoStock, holdings, params)
+}
+
+// journalRisk assesses the open positions of the trade journal.
+func journalRisk(repo journal.Repository, repoStock stock.Repository, params risk.Params) (risk.Report, error) {
+	book, err := journalBook(repo, repoStock, journal.MethodFIFO)
+	if err != nil {
+		return risk.Report{}, err
+	}
+
+	holdings := make([]risk.Holding, 0, len(book.Positions))
+	for _, p := range book.Positions {
+		if p.Shares > 0.0 {
+			holdings = append(holdings, risk.Holding{Ticker: p.Ticker, Name: p.Name, Sector: p.Sector, Value: p.Value})
+		}
+	}
+	return assessRisk(repoStock, holdings, params)
+}
+
+// assessRisk assesses holdings from their daily data, names and sectors missing taken
+// from stocks. A benchmark given without daily data is risk.ErrInsufficientData.
+func assessRisk(repoStock stock.Repository, holdings []risk.Holding, params risk.Params) (risk.Report, error) {
+	explicit := params.Benchmark != ""
+	params = params.WithDefaults()
+	if err := params.Validate(); err != nil {
+		return risk.Report{}, err
+	}
+
+	histories := make(map[string][]stock.DailyData, len(holdings))
+	for idx, h := range holdings {
+		if h.Sector == "" {
+			if s, err := repoStock.GetStockByTicker(h.Ticker); err == nil {
+				holdings[idx].Name, holdings[idx].Sector = s.Name, s.Sector
+			}
+		}
+		dailyData, err := repoStock.GetDailyDataByTicker(h.Ticker, params.Lookback+1)
+		if err != nil {
+			return risk.Report{}, err
+		}
+		histories[h.Ticker] = dailyData
+	}
+	// The default benchmark may not be stored yet, its betas left out then.
+	benchmark, err := repoStock.GetDailyDataByTicker(params.Benchmark, params.Lookback+1)
+	if err != nil {
+		return risk.Report{}, err
+	}
+	if len(benchmark) == 0 && explicit {
+		return risk.Report{}, fmt.Errorf("%w: no daily data of benchmark %s", risk.ErrInsufficientData, params.Benchmark)
+	}
+
+	return risk.Assess(holdings, histories, benchmark, params)
+}
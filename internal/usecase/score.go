@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"example.com/stocker-back/internal/scoring"
+	"example.com/stocker-back/internal/stock"
+	"github.com/samber/lo"
+)
+
+// UpdateScores scores every stock with the configured factors and saves the ranking
+// under the latest trading date.
+func (c *Command) UpdateScores() error {
+	c.logger.Infof("UpdateScores", "message", "start...")
+	stocksAll, err := c.repoStock.GetStocks()
+	if err != nil {
+		return err
+	}
+
+	date := ""
+	inputs := make([]scoring.Input, 0, len(stocksAll))
+	failedTickers := make([]string, 0)
+	for _, s := range stocksAll {
+		dailyData, err := c.repoStock.GetDailyDataByTicker(s.Ticker, stock.ReturnWindow)
+		if err != nil {
+			c.logger.Errorf("GetDailyDataByTicker", "error", err.Error(), "ticker", s.Ticker)
+			failedTickers = append(failedTickers, s.Ticker)
+			continue
+		}
+		if len(dailyData) > 0 {
+			date = max(date, dailyData[len(dailyData)-1].Date)
+		}
+
+		input, err := scoring.NewInput(s, dailyData)
+		if err != nil {
+			c.logger.Errorf("NewInput", "error", err.Error(), "ticker", s.Ticker)
+			failedTickers = append(failedTickers, s.Ticker)
+			continue
+		}
+		inputs = append(inputs, input)
+	}
+	if date == "" {
+		return fmt.Errorf("no daily data to score")
+	}
+
+	scores, err := scoring.ComputeScores(date, inputs, c.scoreConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := c.repoScore.SaveScores(date, scores); err != nil {
+		return err
+	}
+
+	c.logger.Infof("UpdateScores - DONE", "date", date, "scored", len(scores), "failed", len(failedTickers), "tickers", failedTickers)
+
+	return nil
+}
+
+// GetRanking queries the latest ranking, of sector only if given, limited to the best
+// `limit` if positive; each entry carries the stock name along with score and breakdown.
+func (q *Query) GetRanking(sector string, limit int) ([]map[string]any, error) {
+	scores, err := q.repoScore.GetScoresLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	if sector != "" {
+		scores = lo.Filter(scores, func(s scoring.Score, _ int) bool {
+			return s.Sector == sector
+		})
+	}
+	if limit > 0 && len(scores) > limit {
+		scores = scores[:limit]
+	}
+
+	stocks, err := q.repoStock.GetStocksByTickers(lo.Map(scores, func(s scoring.Score, _ int) string {
+		return s.Ticker
+	}))
+	if err != nil {
+		return nil, err
+	}
+	names := lo.SliceToMap(stocks, func(s stock.Stock) (string, string) {
+		return s.Ticker, s.Name
+	})
+
+	output := make([]map[string]any, 0, len(scores))
+	for _, s := range scores {
+		var m map[string]any
+		b, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+
+		m["name"] = names[s.Ticker]
+
+		output = append(output, m)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"example.com/stocker-back/internal/common"
+	"example.com/stocker-back/internal/screener"
+	"example.com/stocker-back/internal/stock"
+	"example.com/stocker-back/internal/tracking"
+	"github.com/samber/lo"
+)
+
+// screenDiff compares the run of the screen on date, the latest if empty, with the run before it.
+func screenDiff(repo screener.Repository, name, date string) (screener.Diff, error) {
+	runDates, err := repo.GetSnapshotDates(name, 0)
+	if err != nil {
+		return screener.Diff{}, err
+	}
+	if len(runDates) == 0 {
+		return screener.ComputeDiff(name, date, "", nil, nil), nil
+	}
+
+	idx := 0
+	if date != "" {
+		idx = slices.Index(runDates, date)
+		if idx < 0 {
+			return screener.Diff{}, fmt.Errorf("no screen run on %s", date)
+		}
+	}
+
+	curr, err := repo.GetSnapshotsByDate(name, runDates[idx])
+	if err != nil {
+		return screener.Diff{}, err
+	}
+	if idx+1 >= len(runDates) {
+		return screener.ComputeDiff(name, runDates[idx], "", nil, curr), nil
+	}
+
+	prev, err := repo.GetSnapshotsByDate(name, runDates[idx+1])
+	if err != nil {
+		return screener.Diff{}, err
+	}
+
+	return screener.ComputeDiff(name, runDates[idx], runDates[idx+1], prev, curr), nil
+}
+
+// NotifyScreenDiff pushes tickers entering and leaving the latest run of the screen of
+// given name (empty for the default daily screen); tracked tickers are starred. Nothing
+// is sent when the screen did not change, or when its latest run was already notified,
+// e.g. on holidays, so that the same change is not sent twice.
+func (c *Command) NotifyScreenDiff(name string) error {
+	diff, err := screenDiff(c.repoScreen, name, "")
+	if err != nil {
+		return err
+	}
+	notified, err := c.repoScreen.GetNotifiedDate(name)
+	if err != nil {
+		return err
+	}
+	if diff.Date == "" || diff.Date == notified {
+		c.logger.Infof("NotifyScreenDiff", "name", name, "message", "no new run", "date", diff.Date)
+		return nil
+	}
+	if len(diff.Entered) == 0 && len(diff.Left) == 0 {
+		c.logger.Infof("NotifyScreenDiff", "name", name, "message", "no change")
+		return c.repoScreen.SetNotifiedDate(name, diff.Date)
+	}
+
+	trackings, err := c.repoTracking.GetTrackings()
+	if err != nil {
+		return err
+	}
+	tracked := lo.SliceToMap(trackings, func(t tracking.Tracking) (string, bool) {
+		return t.Ticker, true
+	})
+
+	curr, err := c.repoScreen.GetSnapshotsByDate(name, diff.Date)
+	if err != nil {
+		return err
+	}
+	kdjByTicker := make(map[string]float64, len(curr))
+	for _, s := range curr {
+		kdjByTicker[s.Ticker] = s.Kdj
+	}
+
+	// Stocks and daily data of all tickers listed are read at once.
+	stocks, err := c.repoStock.GetStocksByTickers(append(slices.Clone(diff.Entered), diff.Left...))
+	if err != nil {
+		return err
+	}
+	stocksByTicker := lo.KeyBy(stocks, func(s stock.Stock) string { return s.Ticker })
+	dailyData, err := c.repoStock.GetDailyDataLastByTickers(diff.Entered)
+	if err != nil {
+		return err
+	}
+	dailyByTicker := lo.KeyBy(dailyData, func(d stock.DailyData) string { return d.Ticker })
+
+	var b strings.Builder
+	for _, ticker := range diff.Entered {
+		b.WriteString("+ " + screenDiffLine(ticker, stocksByTicker, tracked[ticker]))
+		fmt.Fprintf(&b, " J=%.1f", kdjByTicker[ticker])
+		if daily, ok := dailyByTicker[ticker]; ok {
+			fmt.Fprintf(&b, " value=%.2f亿", daily.Value/1e8) //nolint:gomnd //ignore
+		}
+		b.WriteString("\n")
+	}
+	for _, ticker := range diff.Left {
+		b.WriteString("- " + screenDiffLine(ticker, stocksByTicker, tracked[ticker]) + "\n")
+	}
+
+	title := name
+	if title == "" {
+		title = "daily"
+	}
+	topic := fmt.Sprintf("screen %s %s: +%d -%d", title, common.Day(diff.Date), len(diff.Entered), len(diff.Left))
+	c.notifier.Sendf(topic, b.String())
+
+	return c.repoScreen.SetNotifiedDate(name, diff.Date)
+}
+
+// screenDiffLine formats ticker with its name and sector, starred if tracked.
+func screenDiffLine(ticker string, stocks map[string]stock.Stock, tracked bool) string {
+	line := ticker
+	if s, ok := stocks[ticker]; ok {
+		line = fmt.Sprintf("%s %s [%s]", ticker, s.Name, s.Sector)
+	}
+	if tracked {
+		line = "★ " + line
+	}
+	return line
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"example.com/stocker-back/internal/infra"
+	"example.com/stocker-back/internal/stock"
+)
+
+// sectorReturns computes returns of every stock from its recent daily data; stocks whose
+// daily data cannot be read are left out and logged.
+func sectorReturns(repo stock.Repository, logger infra.Logger, stocks []stock.Stock) map[string]map[string]float64 {
+	returns := make(map[string]map[string]float64, len(stocks))
+	for _, s := range stocks {
+		dailyData, err := repo.GetDailyDataByTicker(s.Ticker, stock.ReturnWindow)
+		if err != nil {
+			logger.Errorf("GetDailyDataByTicker", "error", err.Error(), "ticker", s.Ticker)
+			continue
+		}
+		returns[s.Ticker] = stock.ComputeReturns(stock.OHLC2Close(stock.DailyData2OHLC(dailyData)))
+	}
+	return returns
+}
+
+// GetSectorStats queries aggregates of every sector from stocks and their daily data.
+func (q *Query) GetSectorStats() ([]stock.SectorStats, error) {
+	stocksAll, err := q.repoStock.GetStocks()
+	if err != nil {
+		return nil, err
+	}
+
+	return stock.ComputeSectorStats(stocksAll, sectorReturns(q.repoStock, q.logger, stocksAll)), nil
+}
@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"example.com/stocker-back/internal/backtest"
+	"github.com/samber/lo"
+)
+
+var ErrSweepInProgress = errors.New("sweep in progress")
+
+// StartSweep validates and saves a new sweep as running, to be run by RunSweep.
+func (c *Command) StartSweep(sweep backtest.Sweep) error {
+	sweep = sweep.WithDefaults()
+	if err := sweep.Validate(); err != nil {
+		return err
+	}
+	if _, err := c.repoSweep.GetSweepByName(sweep.Name); err == nil {
+		return fmt.Errorf("%w: sweep %s exists", backtest.ErrInvalidConfig, sweep.Name)
+	}
+
+	sweep.Status, sweep.Error = backtest.SweepRunning, ""
+	return c.repoSweep.SaveSweep(sweep)
+}
+
+// RunSweep runs the sweep of name over stored daily history, saving each run as it
+// finishes so an interrupted sweep resumes where it stopped, and marks it done or
+// failed at the end.
+func (c *Command) RunSweep(name string) error {
+	if _, loaded := c.sweeping.LoadOrStore(name, true); loaded {
+		return fmt.Errorf("%w: %s", ErrSweepInProgress, name)
+	}
+	defer c.sweeping.Delete(name)
+
+	sweep, err := c.repoSweep.GetSweepByName(name)
+	if err != nil {
+		return err
+	}
+	runs, err := c.repoSweep.GetSweepRuns(name)
+	if err != nil {
+		return err
+	}
+	done := lo.SliceToMap(runs, func(run backtest.SweepRun) (string, bool) {
+		return run.Key(), true
+	})
+
+	sweep.Status, sweep.Error = backtest.SweepRunning, ""
+	if err := c.repoSweep.SaveSweep(sweep); err != nil {
+		return err
+	}
+
+	c.logger.Infof("RunSweep - starting...", "name", name, "done", len(done), "total", sweep.Total())
+	histories, err := backtestHistories(c.repoStock, sweep.Base.Tickers)
+	if err == nil {
+		err = backtest.RunSweep(sweep, backtest.NewUniverse(histories), done, func(run backtest.SweepRun) error {
+			return c.repoSweep.SaveSweepRun(name, run)
+		})
+	}
+
+	sweep.Status = backtest.SweepDone
+	if err != nil {
+		sweep.Status, sweep.Error = backtest.SweepFailed, err.Error()
+		c.logger.Errorf("RunSweep", "error", err.Error(), "name", name)
+	}
+	if errSave := c.repoSweep.SaveSweep(sweep); errSave != nil {
+		c.logger.Errorf("SaveSweep", "error", errSave.Error(), "name", name)
+	}
+
+	c.logger.Infof("RunSweep - DONE", "name", name, "status", sweep.Status)
+	c.notifier.Sendf("RunSweep DONE", fmt.Sprintf("sweep %s %s %s", name, sweep.Status, sweep.Error))
+
+	return err
+}
+
+// ResumeSweeps runs, one after another, sweeps left running by a restart.
+func (c *Command) ResumeSweeps() error {
+	sweeps, err := c.repoSweep.GetSweeps()
+	if err != nil {
+		return err
+	}
+
+	for _, sweep := range sweeps {
+		if sweep.Status != backtest.SweepRunning {
+			continue
+		}
+		if err := c.RunSweep(sweep.Name); err != nil {
+			c.logger.Errorf("ResumeSweeps", "error", err.Error(), "name", sweep.Name)
+		}
+	}
+	return nil
+}
+
+// DeleteSweep deletes the sweep of name with its runs unless in progress.
+func (c *Command) DeleteSweep(name string) error {
+	if _, ok := c.sweeping.Load(name); ok {
+		return fmt.Errorf("%w: %s", ErrSweepInProgress, name)
+	}
+	return c.repoSweep.DeleteSweepByName(name)
+}
+
+// GetSweeps queries all sweeps.
+func (q *Query) GetSweeps() ([]backtest.Sweep, error) {
+	return q.repoSweep.GetSweeps()
+}
+
+// GetSweep queries the sweep of name.
+func (q *Query) GetSweep(name string) (backtest.Sweep, error) {
+	return q.repoSweep.GetSweepByName(name)
+}
+
+// GetSweepResult queries progress of the sweep of name and its parameter sets ranked
+// from the runs finished so far.
+func (q *Query) GetSweepResult(name string) (backtest.SweepResult, error) {
+	sweep, err := q.repoSweep.GetSweepByName(name)
+	if err != nil {
+		return backtest.SweepResult{}, err
+	}
+	runs, err := q.repoSweep.GetSweepRuns(name)
+	if err != nil {
+		return backtest.SweepResult{}, err
+	}
+
+	return backtest.RankSweep(sweep, runs), nil
+}